@@ -0,0 +1,60 @@
+package backlog
+
+import (
+	"net/http"
+
+	"github.com/sonyjop/camelgo/management"
+)
+
+type status struct {
+	Enabled      bool   `json:"enabled"`
+	RoutePattern string `json:"routePattern,omitempty"`
+	Events       int    `json:"events"`
+}
+
+// Mount adds the tracer to a management API:
+//
+//	GET    /backlog?routeId=&exchangeId=  captured events, oldest first
+//	DELETE /backlog                       clear the events
+//	GET    /backlog/status                whether capturing is on
+//	POST   /backlog/enable?routes=orders* start capturing, optionally filtered
+//	POST   /backlog/disable               stop capturing
+func (t *BacklogTracer) Mount(h *management.Handler) {
+	h.Handle("GET /backlog", http.HandlerFunc(t.getEvents))
+	h.Handle("DELETE /backlog", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Clear()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	h.Handle("GET /backlog/status", http.HandlerFunc(t.getStatus))
+	h.Handle("POST /backlog/enable", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("routes") {
+			t.mu.Lock()
+			t.routePattern = r.URL.Query().Get("routes")
+			t.mu.Unlock()
+		}
+		t.SetEnabled(true)
+		t.getStatus(w, r)
+	}))
+	h.Handle("POST /backlog/disable", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.SetEnabled(false)
+		t.getStatus(w, r)
+	}))
+}
+
+func (t *BacklogTracer) getStatus(w http.ResponseWriter, r *http.Request) {
+	t.mu.Lock()
+	st := status{Enabled: t.Enabled(), RoutePattern: t.routePattern, Events: len(t.events)}
+	t.mu.Unlock()
+	management.WriteJSON(w, http.StatusOK, st)
+}
+
+func (t *BacklogTracer) getEvents(w http.ResponseWriter, r *http.Request) {
+	routeID, exchangeID := r.URL.Query().Get("routeId"), r.URL.Query().Get("exchangeId")
+	out := []Event{}
+	for _, e := range t.Events() {
+		if (routeID == "" || e.RouteID == routeID) && (exchangeID == "" || e.ExchangeID == exchangeID) {
+			out = append(out, e)
+		}
+	}
+	management.WriteJSON(w, http.StatusOK, out)
+}
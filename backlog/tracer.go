@@ -0,0 +1,244 @@
+// Package backlog captures snapshots of exchanges before and after every
+// route step, for inspecting what a running context does.
+package backlog
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	camellog "github.com/sonyjop/camelgo/component/log"
+	"github.com/sonyjop/camelgo/core"
+)
+
+// DefaultSize is the number of events kept when no size is given.
+const DefaultSize = 1000
+
+// DefaultBodyMaxChars limits the body preview of each event.
+const DefaultBodyMaxChars = 1024
+
+// Phase says whether an event was captured before or after a step.
+type Phase string
+
+const (
+	Before Phase = "before"
+	After  Phase = "after"
+)
+
+// Event is a snapshot of an exchange at one step.
+type Event struct {
+	Seq        int64             `json:"seq"`
+	Time       time.Time         `json:"time"`
+	Phase      Phase             `json:"phase"`
+	RouteID    string            `json:"routeId"`
+	StepID     string            `json:"stepId"`
+	ExchangeID string            `json:"exchangeId"`
+	BodyType   string            `json:"bodyType,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+	Elapsed    time.Duration     `json:"elapsed,omitempty"` // After only
+	Error      string            `json:"error,omitempty"`   // After only
+}
+
+// BacklogTracer keeps the most recent events in a ring buffer. It is an
+// InterceptStrategy installed once and switched on and off at runtime;
+// while disabled it costs one atomic load per step.
+type BacklogTracer struct {
+	// BodyMaxChars limits body previews; 0 means DefaultBodyMaxChars.
+	BodyMaxChars int
+
+	enabled atomic.Bool
+
+	mu           sync.Mutex
+	size         int
+	events       []Event
+	next         int
+	seq          int64
+	routePattern string
+	predicate    core.Predicate
+}
+
+// NewBacklogTracer returns a disabled tracer keeping the last size events.
+func NewBacklogTracer(size int) *BacklogTracer {
+	if size < 1 {
+		size = DefaultSize
+	}
+	return &BacklogTracer{size: size}
+}
+
+// Install registers the tracer on ctx. Only routes added afterwards are
+// traced, so call it before AddRoutes.
+func (t *BacklogTracer) Install(ctx core.Context) {
+	ctx.AddInterceptStrategy(t)
+}
+
+// SetEnabled starts or stops capturing.
+func (t *BacklogTracer) SetEnabled(enabled bool) {
+	t.enabled.Store(enabled)
+}
+
+// Enabled reports whether the tracer is capturing.
+func (t *BacklogTracer) Enabled() bool {
+	return t.enabled.Load()
+}
+
+// SetFilter limits capturing to routes whose ID matches routePattern (see
+// core.MatchPattern) and exchanges matching predicate. Empty values match
+// everything.
+func (t *BacklogTracer) SetFilter(routePattern string, predicate core.Predicate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.routePattern = routePattern
+	t.predicate = predicate
+}
+
+// RoutePattern returns the route filter.
+func (t *BacklogTracer) RoutePattern() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.routePattern
+}
+
+// Events returns the captured events, oldest first.
+func (t *BacklogTracer) Events() []Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]Event, 0, len(t.events))
+	out = append(out, t.events[t.next:]...)
+	return append(out, t.events[:t.next]...)
+}
+
+// EventsFor returns the captured events of one exchange, oldest first.
+func (t *BacklogTracer) EventsFor(exchangeID string) []Event {
+	var out []Event
+	for _, e := range t.Events() {
+		if e.ExchangeID == exchangeID {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Clear drops all captured events.
+func (t *BacklogTracer) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = nil
+	t.next = 0
+}
+
+func (t *BacklogTracer) WrapProcessor(ctx core.Context, info core.StepInfo, target core.Processor) (core.Processor, error) {
+	if info.Kind == core.RouteStepKind {
+		return target, nil
+	}
+	return &tracingProcessor{tracer: t, routeID: info.RouteID, stepID: info.StepID, target: target}, nil
+}
+
+// accepts applies the filter to an exchange about to enter a step.
+func (t *BacklogTracer) accepts(ctx core.Context, routeID string, exchange *core.Exchange) bool {
+	t.mu.Lock()
+	pattern, pred := t.routePattern, t.predicate
+	t.mu.Unlock()
+	if pattern != "" && !core.MatchPattern(routeID, pattern) {
+		return false
+	}
+	if pred == nil {
+		return true
+	}
+	ok, err := pred.Evaluate(ctx, exchange)
+	return err == nil && ok
+}
+
+func (t *BacklogTracer) add(e Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seq++
+	e.Seq = t.seq
+	if len(t.events) < t.size {
+		t.events = append(t.events, e)
+		return
+	}
+	t.events[t.next] = e
+	t.next = (t.next + 1) % t.size
+}
+
+func (t *BacklogTracer) snapshot(phase Phase, routeID, stepID string, exchange *core.Exchange) Event {
+	e := Event{
+		Time:       time.Now(),
+		Phase:      phase,
+		RouteID:    routeID,
+		StepID:     stepID,
+		ExchangeID: exchange.ID(),
+		Headers:    stringify(exchange.In().Headers()),
+		Properties: stringify(exchange.Properties()),
+	}
+	if body := exchange.In().Body(); body != nil {
+		e.BodyType = fmt.Sprintf("%T", body)
+		e.Body = preview(body, t.BodyMaxChars)
+	}
+	return e
+}
+
+type tracingProcessor struct {
+	tracer  *BacklogTracer
+	routeID string
+	stepID  string
+	target  core.Processor
+}
+
+func (p *tracingProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	t := p.tracer
+	if !t.Enabled() || !t.accepts(ctx, p.routeID, exchange) {
+		return p.target.Process(ctx, exchange)
+	}
+
+	t.add(t.snapshot(Before, p.routeID, p.stepID, exchange))
+	start := time.Now()
+	err := p.target.Process(ctx, exchange)
+
+	after := t.snapshot(After, p.routeID, p.stepID, exchange)
+	after.Elapsed = time.Since(start)
+	if failure := err; failure != nil || exchange.Error() != nil {
+		if failure == nil {
+			failure = exchange.Error()
+		}
+		after.Error = failure.Error()
+	}
+	t.add(after)
+	return err
+}
+
+// stringify renders values with sensitive keys masked.
+func stringify(m map[string]interface{}) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		if camellog.IsSensitive(k) {
+			out[k] = camellog.MaskedValue
+		} else {
+			out[k] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
+
+func preview(body interface{}, max int) string {
+	if max <= 0 {
+		max = DefaultBodyMaxChars
+	}
+	var s string
+	switch b := body.(type) {
+	case []byte:
+		s = string(b)
+	default:
+		s = fmt.Sprint(b)
+	}
+	if r := []rune(s); len(r) > max {
+		return string(r[:max]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,136 @@
+package backlog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/dsl"
+	"github.com/sonyjop/camelgo/internal/routetest"
+	"github.com/sonyjop/camelgo/language"
+	"github.com/sonyjop/camelgo/management"
+)
+
+func newTracedContext(t *testing.T, size int) (*core.DefaultContext, *BacklogTracer) {
+	t.Helper()
+	tracer := NewBacklogTracer(size)
+	ctx := routetest.NewContext()
+	tracer.Install(ctx)
+	routetest.Start(t, ctx, func(b *dsl.BaseRouteBuilder) {
+		b.From("direct:orders").RouteID("orders").
+			SetHeader("status", language.Constant("checked")).
+			To("direct:billing")
+		b.From("direct:billing").RouteID("billing").
+			SetHeader("Authorization", language.Constant("Bearer abc"))
+	})
+	return ctx, tracer
+}
+
+func send(t *testing.T, ctx core.Context, body string) *core.Exchange {
+	t.Helper()
+	ex, err := routetest.Send(ctx, "direct:orders", body, nil)
+	if err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	return ex
+}
+
+func TestBacklogTracer_CapturesBeforeAndAfter(t *testing.T) {
+	ctx, tracer := newTracedContext(t, 100)
+	send(t, ctx, "ignored")
+	if len(tracer.Events()) != 0 {
+		t.Fatalf("expected nothing captured while disabled")
+	}
+
+	tracer.SetEnabled(true)
+	ex := send(t, ctx, "order-1")
+
+	events := tracer.EventsFor(ex.ID())
+	var got []string
+	for _, e := range events {
+		got = append(got, e.RouteID+"/"+e.StepID+":"+string(e.Phase))
+	}
+	want := "orders/setHeader1:before,orders/setHeader1:after,orders/to1:before," +
+		"billing/setHeader1:before,billing/setHeader1:after,orders/to1:after"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("unexpected events %s", strings.Join(got, ","))
+	}
+	if events[0].Headers["status"] != "" || events[1].Headers["status"] != "checked" {
+		t.Errorf("expected snapshots before and after the header was set, got %v / %v", events[0].Headers, events[1].Headers)
+	}
+	if events[4].Headers["Authorization"] != "xxxxxx" {
+		t.Errorf("expected sensitive header to be masked, got %v", events[4].Headers)
+	}
+	if events[0].Body != "order-1" || events[0].BodyType != "string" {
+		t.Errorf("unexpected body preview %+v", events[0])
+	}
+
+	tracer.Clear()
+	if len(tracer.Events()) != 0 {
+		t.Errorf("expected events to be cleared")
+	}
+}
+
+func TestBacklogTracer_FilterAndRingBuffer(t *testing.T) {
+	ctx, tracer := newTracedContext(t, 3)
+	tracer.SetEnabled(true)
+	tracer.SetFilter("bill*", language.Equals(language.Body(), "vip"))
+
+	send(t, ctx, "regular")
+	if n := len(tracer.Events()); n != 0 {
+		t.Fatalf("expected predicate to filter, got %d events", n)
+	}
+	send(t, ctx, "vip")
+	send(t, ctx, "vip")
+
+	events := tracer.Events()
+	if len(events) != 3 {
+		t.Fatalf("expected the ring buffer to keep 3 events, got %d", len(events))
+	}
+	for i, e := range events {
+		if e.RouteID != "billing" {
+			t.Errorf("expected only billing events, got %+v", e)
+		}
+		if i > 0 && e.Seq != events[i-1].Seq+1 {
+			t.Errorf("expected events oldest first, got seqs %d then %d", events[i-1].Seq, e.Seq)
+		}
+	}
+}
+
+func TestBacklogTracer_ManagementAPI(t *testing.T) {
+	ctx, tracer := newTracedContext(t, 100)
+	h := management.NewHandler(ctx, "")
+	tracer.Mount(h)
+
+	do := func(method, path string, out interface{}) int {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		if out != nil {
+			json.Unmarshal(rec.Body.Bytes(), out)
+		}
+		return rec.Code
+	}
+
+	var st status
+	do(http.MethodPost, "/backlog/enable?routes=orders", &st)
+	if !st.Enabled || st.RoutePattern != "orders" {
+		t.Fatalf("unexpected status %+v", st)
+	}
+	send(t, ctx, "order-2")
+
+	var events []Event
+	do(http.MethodGet, "/backlog?routeId=orders", &events)
+	if len(events) != 4 {
+		t.Errorf("expected 4 orders events, got %d", len(events))
+	}
+	if code := do(http.MethodDelete, "/backlog", nil); code != http.StatusNoContent {
+		t.Errorf("DELETE: %d", code)
+	}
+	do(http.MethodPost, "/backlog/disable", &st)
+	if st.Enabled || st.Events != 0 {
+		t.Errorf("unexpected status %+v", st)
+	}
+}
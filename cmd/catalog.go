@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// Output formats of the catalog command.
+const (
+	catalogFormatTable = "table"
+	catalogFormatJSON  = "json"
+)
+
+func newCatalogCommand() *cobra.Command {
+	var format string
+	c := &cobra.Command{
+		Use:   "catalog [scheme]",
+		Short: "List the available components and their endpoint options",
+		Long: `Catalog lists the built-in components. Given a scheme, it lists the
+endpoint options of that component: their type, default, allowed values and
+whether they are required.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			ctx := newContext()
+			var descs []core.ComponentDescriptor
+			if len(args) == 1 {
+				desc, ok := ctx.ComponentDescriptor(args[0])
+				if !ok {
+					return fmt.Errorf("unknown component %q", args[0])
+				}
+				descs = []core.ComponentDescriptor{desc}
+			} else {
+				descs = ctx.Catalog()
+			}
+
+			switch format {
+			case catalogFormatTable:
+				if len(args) == 1 {
+					return writeOptionTable(c.OutOrStdout(), descs[0])
+				}
+				return writeComponentTable(c.OutOrStdout(), descs)
+			case catalogFormatJSON:
+				enc := json.NewEncoder(c.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if len(args) == 1 {
+					return enc.Encode(descs[0])
+				}
+				return enc.Encode(descs)
+			default:
+				return fmt.Errorf("unknown format %q, expected %s or %s", format, catalogFormatTable, catalogFormatJSON)
+			}
+		},
+	}
+	c.Flags().StringVarP(&format, "format", "o", catalogFormatTable, "output format: table or json")
+	return c
+}
+
+func writeComponentTable(w io.Writer, descs []core.ComponentDescriptor) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCHEME\tSYNTAX\tCONSUMER\tPRODUCER\tDESCRIPTION")
+	for _, d := range descs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", d.Scheme, d.Syntax, yesNo(d.Consumer), yesNo(d.Producer), d.Description)
+	}
+	return tw.Flush()
+}
+
+func writeOptionTable(w io.Writer, d core.ComponentDescriptor) error {
+	if d.Syntax != "" {
+		fmt.Fprintf(w, "%s - %s\n\n", d.Syntax, d.Description)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OPTION\tKIND\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION")
+	for _, o := range d.Options {
+		typ := string(o.Type)
+		if len(o.Enum) > 0 {
+			typ += " (" + strings.Join(o.Enum, "|") + ")"
+		}
+		desc := o.Description
+		if o.Secret {
+			desc = "[secret] " + desc
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", o.Name, o.Kind, typ, o.Default, yesNo(o.Required), desc)
+	}
+	return tw.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+func TestCatalogCommand(t *testing.T) {
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		root := NewRootCommand()
+		root.SetOut(&out)
+		root.SetArgs(append([]string{"catalog"}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	out, err := run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, scheme := range []string{"direct", "file", "log", "seda"} {
+		if !strings.Contains(out, "\n"+scheme+" ") {
+			t.Errorf("expected %s in the component table, got:\n%s", scheme, out)
+		}
+	}
+
+	out, err = run("log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "trace|debug|info|warn|error") || !strings.Contains(out, "showHeaders") {
+		t.Errorf("expected the log options, got:\n%s", out)
+	}
+
+	out, err = run("seda", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var desc core.ComponentDescriptor
+	if err := json.Unmarshal([]byte(out), &desc); err != nil {
+		t.Fatal(err)
+	}
+	if o, ok := desc.Option("size"); !ok || o.Type != core.IntOption || o.Default != "1000" {
+		t.Errorf("expected seda size to be an int defaulting to 1000, got %+v", o)
+	}
+
+	if _, err := run("nope"); err == nil {
+		t.Error("expected an unknown scheme to fail")
+	}
+}
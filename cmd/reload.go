@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/yamldsl"
+)
+
+// routeFiles remembers which routes came from which file, so a changed
+// file can replace exactly its own routes.
+type routeFiles struct {
+	ctx    *core.DefaultContext
+	loader *yamldsl.Loader
+	files  map[string]*routeFile
+}
+
+type routeFile struct {
+	modTime  time.Time
+	routeIDs []string
+}
+
+func newRouteFiles(ctx *core.DefaultContext) *routeFiles {
+	return &routeFiles{ctx: ctx, loader: yamldsl.NewLoader(), files: make(map[string]*routeFile)}
+}
+
+// add loads the routes of path into the context. Routes added before a
+// failure are remembered too, so remove still finds them.
+func (r *routeFiles) add(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	ids, err := r.addRoutes(path)
+	r.files[path] = &routeFile{modTime: info.ModTime(), routeIDs: ids}
+	return err
+}
+
+// addRoutes adds the routes of source and returns the IDs of those that
+// were added, also when a later one failed.
+func (r *routeFiles) addRoutes(source interface{}) ([]string, error) {
+	before := make(map[string]bool)
+	for _, route := range r.ctx.Routes() {
+		before[route.ID] = true
+	}
+	err := r.ctx.AddRoutes(source)
+	var ids []string
+	for _, route := range r.ctx.Routes() {
+		if !before[route.ID] {
+			ids = append(ids, route.ID)
+		}
+	}
+	return ids, err
+}
+
+// reload replaces the routes of path with its current content. A file that
+// does not parse leaves the running routes alone; one that fails to compile
+// or start has its new routes removed and the previous ones added again.
+func (r *routeFiles) reload(path string) error {
+	if _, err := r.loader.Load(path); err != nil {
+		return err
+	}
+	previous, modTime := r.definitions(path)
+	r.remove(path)
+	err := r.add(path)
+	if err == nil {
+		return nil
+	}
+
+	r.remove(path)
+	ids, restoreErr := r.addRoutes(previous)
+	r.files[path] = &routeFile{modTime: modTime, routeIDs: ids}
+	if restoreErr != nil {
+		return errors.Join(err, fmt.Errorf("restoring the previous routes: %w", restoreErr))
+	}
+	return err
+}
+
+// definitions returns the definitions of the running routes of path and
+// the modification time they were loaded at.
+func (r *routeFiles) definitions(path string) ([]*core.RouteDefinition, time.Time) {
+	f, ok := r.files[path]
+	if !ok {
+		return nil, time.Time{}
+	}
+	var defs []*core.RouteDefinition
+	for _, id := range f.routeIDs {
+		if route := r.ctx.Route(id); route != nil {
+			defs = append(defs, route.Definition)
+		}
+	}
+	return defs, f.modTime
+}
+
+// remove drops the routes of path from the context.
+func (r *routeFiles) remove(path string) {
+	f, ok := r.files[path]
+	if !ok {
+		return
+	}
+	for _, id := range f.routeIDs {
+		if err := r.ctx.RemoveRoute(id); err != nil {
+			core.Log(r.ctx, logCategory).WithError(err).Warn("removing route")
+		}
+	}
+	delete(r.files, path)
+}
+
+// changed returns the files modified or deleted since they were loaded.
+func (r *routeFiles) changed() []string {
+	var out []string
+	for path, f := range r.files {
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().Equal(f.modTime) {
+			out = append(out, path)
+		}
+	}
+	return out
+}
+
+// watch reloads changed files every interval until ctx is done. Deleted
+// files have their routes removed; a file that fails to load is retried
+// when it changes again.
+func (r *routeFiles) watch(ctx context.Context, interval time.Duration) {
+	log := core.Log(r.ctx, logCategory)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, path := range r.changed() {
+			info, err := os.Stat(path)
+			if err != nil {
+				log.WithField("file", path).Info("route file removed, removing its routes")
+				r.remove(path)
+				continue
+			}
+			if err := r.reload(path); err != nil {
+				log.WithError(err).WithField("file", path).Error("reload failed, keeping the running routes")
+				if f, ok := r.files[path]; ok {
+					f.modTime = info.ModTime()
+				} else {
+					r.files[path] = &routeFile{modTime: info.ModTime()}
+				}
+				continue
+			}
+			log.WithField("file", path).Info("route file reloaded")
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Package cmd implements the camelgo command line.
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/sonyjop/camelgo/component/bean"
+	"github.com/sonyjop/camelgo/component/direct"
+	"github.com/sonyjop/camelgo/component/file"
+	camellog "github.com/sonyjop/camelgo/component/log"
+	"github.com/sonyjop/camelgo/component/seda"
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/yamldsl"
+)
+
+// NewRootCommand returns the camelgo command with all its subcommands.
+func NewRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "camelgo",
+		Short:         "Run and inspect camelgo integration routes",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.AddCommand(newRunCommand())
+	root.AddCommand(newValidateCommand())
+	root.AddCommand(newCatalogCommand())
+	return root
+}
+
+// Execute runs the camelgo command with the process arguments.
+func Execute() error {
+	return NewRootCommand().Execute()
+}
+
+// newContext returns a context with the built-in components registered and
+// the YAML route loader set.
+func newContext() *core.DefaultContext {
+	ctx := core.NewContext()
+	ctx.RegisterComponent("bean", bean.NewBeanComponent(ctx.Registry()))
+	ctx.RegisterComponent("direct", direct.NewDirectComponent())
+	ctx.RegisterComponent("file", file.NewFileComponent())
+	ctx.RegisterComponent("log", camellog.NewLogComponent())
+	ctx.RegisterComponent("seda", seda.NewSedaComponent())
+	ctx.SetLoader(yamldsl.NewLoader())
+	return ctx
+}
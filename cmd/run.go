@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/management"
+	"github.com/sonyjop/camelgo/properties"
+)
+
+// Settings read from the properties file. Flags override them.
+const (
+	keyDev             = "camelgo.dev"
+	keyLogLevel        = "camelgo.log.level"
+	keyManagementAddr  = "camelgo.management.address"
+	keyManagementToken = "camelgo.management.token"
+	keyReloadInterval  = "camelgo.reload.interval"
+	keyProfile         = "camelgo.profile"
+)
+
+// DefaultDevManagementAddr is where --dev serves the management API when no
+// address is configured.
+const DefaultDevManagementAddr = "localhost:8081"
+
+// logCategory is the log category of the camelgo command.
+const logCategory = "camelgo"
+
+type runOptions struct {
+	properties string
+	config     *viper.Viper
+	logOutput  io.Writer
+
+	// onStarted is called once the context is running; tests use it.
+	onStarted func(ctx *core.DefaultContext)
+}
+
+func newRunCommand() *cobra.Command {
+	opts := &runOptions{config: viper.New()}
+	c := &cobra.Command{
+		Use:   "run <route files>...",
+		Short: "Run routes from YAML files until interrupted",
+		Long: `Run loads the routes of the given YAML files (glob patterns are expanded),
+starts them and runs until SIGINT or SIGTERM, then shuts down gracefully.
+
+With --dev, changed route files are reloaded while running, message history
+is recorded and the management API is served on ` + DefaultDevManagementAddr + ` unless
+--management says otherwise.
+
+The properties file also supplies the {{key}} placeholders of the routes,
+together with environment variables. --profile merges the profile's file,
+e.g. app-prod.yaml next to app.yaml; --dev selects the dev profile unless
+another one is given.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(c.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			opts.logOutput = c.ErrOrStderr()
+			return opts.run(ctx, args)
+		},
+	}
+
+	flags := c.Flags()
+	flags.StringVar(&opts.properties, "properties", "", "properties file (YAML, JSON, TOML or .properties)")
+	flags.Bool("dev", false, "development mode: hot reload, message history and management API")
+	flags.String("management", "", "address to serve the management API on, e.g. :8081")
+	flags.String("management-token", "", "token required by the management API")
+	flags.String("log-level", "info", "log level")
+	flags.Duration("reload-interval", time.Second, "how often --dev checks route files for changes")
+	flags.String("profile", "", "properties profile, e.g. dev or prod")
+
+	opts.config.BindPFlag(keyDev, flags.Lookup("dev"))
+	opts.config.BindPFlag(keyManagementAddr, flags.Lookup("management"))
+	opts.config.BindPFlag(keyManagementToken, flags.Lookup("management-token"))
+	opts.config.BindPFlag(keyLogLevel, flags.Lookup("log-level"))
+	opts.config.BindPFlag(keyReloadInterval, flags.Lookup("reload-interval"))
+	opts.config.BindPFlag(keyProfile, flags.Lookup("profile"))
+	return c
+}
+
+// run starts a context with the routes of the files matching patterns and
+// stops it when ctx is done.
+func (o *runOptions) run(ctx context.Context, patterns []string) error {
+	if o.properties != "" {
+		o.config.SetConfigFile(o.properties)
+		if err := o.config.ReadInConfig(); err != nil {
+			return fmt.Errorf("reading properties: %w", err)
+		}
+	}
+	files, err := expandRouteFiles(patterns)
+	if err != nil {
+		return err
+	}
+
+	camelCtx := newContext()
+	if err := o.configureLogging(camelCtx); err != nil {
+		return err
+	}
+	dev := o.config.GetBool(keyDev)
+	if dev {
+		camelCtx.SetMessageHistory(true)
+	}
+	if err := o.configureProperties(camelCtx, dev); err != nil {
+		return err
+	}
+
+	addr := o.config.GetString(keyManagementAddr)
+	if addr == "" && dev {
+		addr = DefaultDevManagementAddr
+	}
+	var server *management.Server
+	if addr != "" {
+		server = management.NewServer(addr, camelCtx, o.config.GetString(keyManagementToken))
+		camelCtx.AddService(server)
+	}
+
+	routes := newRouteFiles(camelCtx)
+	for _, f := range files {
+		if err := routes.add(f); err != nil {
+			return err
+		}
+	}
+
+	log := camelCtx.Logger(logCategory)
+	if err := camelCtx.Start(); err != nil {
+		camelCtx.Stop()
+		return fmt.Errorf("starting: %w", err)
+	}
+	log.Infof("started %d routes from %d files", len(camelCtx.Routes()), len(files))
+	if server != nil {
+		log.Infof("management API on %s", server.ListenAddr())
+	}
+	if dev {
+		go routes.watch(ctx, o.config.GetDuration(keyReloadInterval))
+	}
+	if o.onStarted != nil {
+		o.onStarted(camelCtx)
+	}
+
+	<-ctx.Done()
+	log.Info("shutting down")
+	return camelCtx.Stop()
+}
+
+func (o *runOptions) configureLogging(ctx *core.DefaultContext) error {
+	level, err := logrus.ParseLevel(o.config.GetString(keyLogLevel))
+	if err != nil {
+		return err
+	}
+	logger := logrus.New()
+	logger.SetLevel(level)
+	if o.logOutput != nil {
+		logger.SetOutput(o.logOutput)
+	}
+	ctx.SetLogger(logger)
+	return nil
+}
+
+// configureProperties installs the placeholder properties: the properties
+// file, if any, with the selected profile.
+func (o *runOptions) configureProperties(ctx *core.DefaultContext, dev bool) error {
+	profile := o.config.GetString(keyProfile)
+	if profile == "" && dev {
+		profile = "dev"
+	}
+	props, err := newProperties(o.properties, profile)
+	if err != nil {
+		return err
+	}
+	props.Install(ctx)
+	return nil
+}
+
+// newProperties returns the properties of file, if any, with profile
+// selected.
+func newProperties(file, profile string) (*properties.PropertiesComponent, error) {
+	props := properties.NewPropertiesComponent()
+	if err := props.SetProfile(profile); err != nil {
+		return nil, err
+	}
+	if file != "" {
+		if err := props.AddLocation(file); err != nil {
+			return nil, err
+		}
+	}
+	return props, nil
+}
+
+// expandRouteFiles expands glob patterns into a sorted list of files.
+// Directories stand for the YAML files below them. A pattern matching
+// nothing is an error, so typos do not go unnoticed.
+func expandRouteFiles(patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(f string) {
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	for _, p := range patterns {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no route files match %q", p)
+		}
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil {
+				return nil, err
+			}
+			if !info.IsDir() {
+				add(m)
+				continue
+			}
+			err = filepath.WalkDir(m, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if ext := filepath.Ext(path); !d.IsDir() && (ext == ".yaml" || ext == ".yml") {
+					add(path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
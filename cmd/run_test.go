@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+const routeYAML = `
+- route:
+    id: greet
+    from:
+      uri: direct:greet
+      steps:
+        - setHeader: {name: greeting, constant: %s}
+`
+
+func writeRoutes(t *testing.T, path, greeting string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(strings.Replace(routeYAML, "%s", greeting, 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func greet(t *testing.T, ctx *core.DefaultContext) interface{} {
+	t.Helper()
+	p, err := ctx.CreateProducer("direct:greet")
+	if err != nil {
+		t.Fatalf("CreateProducer error: %v", err)
+	}
+	ex := ctx.NewExchange()
+	if err := p.Process(ctx, ex); err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	return ex.In().Header("greeting")
+}
+
+// startRun runs opts in the background and returns the running context.
+func startRun(t *testing.T, opts *runOptions, patterns ...string) (*core.DefaultContext, func() error) {
+	t.Helper()
+	started := make(chan *core.DefaultContext, 1)
+	opts.onStarted = func(ctx *core.DefaultContext) { started <- ctx }
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- opts.run(ctx, patterns) }()
+
+	select {
+	case camelCtx := <-started:
+		return camelCtx, func() error { cancel(); return <-done }
+	case err := <-done:
+		cancel()
+		t.Fatalf("run failed: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatalf("context did not start")
+	}
+	return nil, nil
+}
+
+func TestRun_PropertiesAndShutdown(t *testing.T) {
+	dir := t.TempDir()
+	writeRoutes(t, filepath.Join(dir, "greet.yaml"), "hello")
+	os.WriteFile(filepath.Join(dir, "hi.yaml"), []byte("- route: {from: {uri: direct:hi, steps: [{to: \"direct:{{greet.name}}\"}]}}"), 0o644)
+	props := filepath.Join(t.TempDir(), "app.yaml")
+	os.WriteFile(props, []byte("camelgo:\n  management:\n    address: 127.0.0.1:0\n  log:\n    level: debug\ngreet:\n  name: nobody\n"), 0o644)
+	os.WriteFile(strings.Replace(props, "app.yaml", "app-prod.yaml", 1), []byte("greet:\n  name: greet\n"), 0o644)
+
+	var logs bytes.Buffer
+	opts := &runOptions{properties: props, config: viper.New(), logOutput: &logs}
+	opts.config.Set(keyProfile, "prod")
+	ctx, stop := startRun(t, opts, filepath.Join(dir, "*.yaml"))
+
+	if got := greet(t, ctx); got != "hello" {
+		t.Errorf("expected route from file to run, got %v", got)
+	}
+	if ctx.Route("greet").Statistics().ExchangesTotal != 1 {
+		t.Fatalf("expected one exchange so far")
+	}
+	hi, err := ctx.CreateProducer("direct:hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := hi.Process(ctx, ctx.NewExchange()); err != nil {
+		t.Fatalf("expected the placeholder of the prod profile to be resolved: %v", err)
+	}
+	if ctx.Route("greet").Statistics().ExchangesTotal != 2 {
+		t.Errorf("expected direct:hi to call the greet route")
+	}
+	if len(ctx.Services()) == 0 {
+		t.Errorf("expected the management server to be added as a service")
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("stop error: %v", err)
+	}
+	if ctx.Status() != core.Stopped {
+		t.Errorf("expected context Stopped, got %s", ctx.Status())
+	}
+	if !strings.Contains(logs.String(), "started 2 routes from 2 files") {
+		t.Errorf("unexpected logs: %s", logs.String())
+	}
+}
+
+func TestRun_DevReloadsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "greet.yaml")
+	writeRoutes(t, path, "hello")
+
+	opts := &runOptions{config: viper.New(), logOutput: &bytes.Buffer{}}
+	opts.config.Set(keyDev, true)
+	opts.config.Set(keyManagementAddr, "127.0.0.1:0")
+	opts.config.Set(keyReloadInterval, 10*time.Millisecond)
+	opts.config.Set(keyLogLevel, "info")
+	ctx, stop := startRun(t, opts, path)
+	defer stop()
+
+	if !ctx.IsMessageHistory() {
+		t.Errorf("expected --dev to enable message history")
+	}
+
+	// A broken edit keeps the running route.
+	os.WriteFile(path, []byte("- route: {from: {uri: direct:greet, steps: [{bogus: x}]}}"), 0o644)
+	touch(path, time.Now().Add(time.Second))
+	time.Sleep(100 * time.Millisecond)
+	if got := greet(t, ctx); got != "hello" {
+		t.Fatalf("expected the old route to survive a broken edit, got %v", got)
+	}
+
+	writeRoutes(t, path, "bonjour")
+	touch(path, time.Now().Add(2*time.Second))
+	deadline := time.Now().Add(2 * time.Second)
+	for greet(t, ctx) != "bonjour" {
+		if time.Now().After(deadline) {
+			t.Fatalf("route was not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(ctx.Routes()) != 1 {
+		t.Errorf("expected the reloaded route to replace the old one, got %d routes", len(ctx.Routes()))
+	}
+}
+
+func TestRouteFiles_ReloadRestoresRoutesThatFailToCompile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "greet.yaml")
+	writeRoutes(t, path, "hello")
+	ctx := newContext()
+	files := newRouteFiles(ctx)
+	if err := files.add(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Stop()
+
+	// The first route compiles and is added before the second one fails.
+	broken := strings.Replace(routeYAML, "%s", "bonjour", 1) + `
+- route:
+    id: other
+    from: {uri: direct:other, steps: [{to: "nosuch:x"}]}
+`
+	os.WriteFile(path, []byte(broken), 0o644)
+	if err := files.reload(path); err == nil || !strings.Contains(err.Error(), "nosuch") {
+		t.Fatalf("expected the unknown scheme to fail the reload, got %v", err)
+	}
+	if got := greet(t, ctx); got != "hello" {
+		t.Errorf("expected the previous route to be running again, got %v", got)
+	}
+	if ids := files.files[path].routeIDs; len(ctx.Routes()) != 1 || len(ids) != 1 || ids[0] != "greet" {
+		t.Errorf("expected only the previous route, got %d routes and IDs %v", len(ctx.Routes()), ids)
+	}
+
+	writeRoutes(t, path, "bonjour")
+	if err := files.reload(path); err != nil {
+		t.Fatalf("reload error: %v", err)
+	}
+	if got := greet(t, ctx); got != "bonjour" || len(ctx.Routes()) != 1 {
+		t.Errorf("expected the fixed file to replace the route, got %v and %d routes", got, len(ctx.Routes()))
+	}
+}
+
+func touch(path string, at time.Time) {
+	os.Chtimes(path, at, at)
+}
+
+func TestExpandRouteFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.yaml", "a.yaml"} {
+		os.WriteFile(filepath.Join(dir, name), nil, 0o644)
+	}
+	files, err := expandRouteFiles([]string{filepath.Join(dir, "*.yaml"), filepath.Join(dir, "a.yaml")})
+	if err != nil || len(files) != 2 || filepath.Base(files[0]) != "a.yaml" {
+		t.Errorf("unexpected files %v (%v)", files, err)
+	}
+	if _, err := expandRouteFiles([]string{filepath.Join(dir, "*.yml")}); err == nil {
+		t.Errorf("expected an error for a pattern matching nothing")
+	}
+}
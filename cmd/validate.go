@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/validate"
+	"github.com/sonyjop/camelgo/yamldsl"
+)
+
+// errValidationFailed is returned when validation found errors, so the
+// command exits non-zero after the report has been written.
+var errValidationFailed = errors.New("validation failed")
+
+func newValidateCommand() *cobra.Command {
+	var format, propertiesFile, profile string
+	var strict bool
+	c := &cobra.Command{
+		Use:   "validate <route files or directories>...",
+		Short: "Check route files without running them",
+		Long: `Validate loads route files without starting anything and reports unknown
+components, invalid endpoints, unresolved or cyclic direct calls, duplicate
+route IDs, unreachable branches and routes that fail to compile.
+
+Placeholders are resolved with --properties and --profile as by run.
+
+It exits non-zero when errors are found, or warnings with --strict.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			files, err := expandRouteFiles(args)
+			if err != nil {
+				return err
+			}
+			props, err := newProperties(propertiesFile, profile)
+			if err != nil {
+				return err
+			}
+			v := validate.New(func() *core.DefaultContext {
+				ctx := newContext()
+				props.Install(ctx)
+				return ctx
+			})
+			loader := yamldsl.NewLoader()
+			for _, f := range files {
+				v.Load(f, loader)
+			}
+			issues := v.Validate()
+			if err := validate.Write(c.OutOrStdout(), format, issues); err != nil {
+				return err
+			}
+			if validate.HasErrors(issues) || (strict && len(issues) > 0) {
+				return errValidationFailed
+			}
+			return nil
+		},
+	}
+	c.Flags().StringVarP(&format, "format", "o", validate.FormatText, "output format: text, json or sarif")
+	c.Flags().BoolVar(&strict, "strict", false, "fail on warnings too")
+	c.Flags().StringVar(&propertiesFile, "properties", "", "properties file (YAML, JSON, TOML or .properties)")
+	c.Flags().StringVar(&profile, "profile", "", "properties profile, e.g. dev or prod")
+	return c
+}
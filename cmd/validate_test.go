@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateCommand(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "nested"), 0o755)
+	writeRoutes(t, filepath.Join(dir, "greet.yaml"), "hello")
+	os.WriteFile(filepath.Join(dir, "nested", "send.yml"), []byte("- route:\n    from:\n      uri: direct:send\n      steps:\n        - to: direct:greet\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a route"), 0o644)
+
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		root := NewRootCommand()
+		root.SetOut(&out)
+		root.SetArgs(append([]string{"validate"}, args...))
+		err := root.Execute()
+		return out.String(), err
+	}
+
+	out, err := run(dir)
+	if err != nil || out != "no problems found\n" {
+		t.Fatalf("expected a clean directory to pass, got %q (%v)", out, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "nested", "send.yml"), []byte("- route:\n    from:\n      uri: direct:send\n      steps:\n        - to: \"direct:{{target}}\"\n"), 0o644)
+	props := filepath.Join(t.TempDir(), "app.yaml")
+	os.WriteFile(props, []byte("target: greet\n"), 0o644)
+	if out, err := run("--properties", props, dir); err != nil {
+		t.Errorf("expected placeholders to resolve with --properties, got %q (%v)", out, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, "nested", "send.yml"), []byte("- route:\n    from:\n      uri: direct:send\n      steps:\n        - to: direct:missing\n"), 0o644)
+	out, err = run("-o", "json", dir)
+	if err != errValidationFailed || !strings.Contains(out, `"rule": "unresolved-direct"`) {
+		t.Errorf("expected a failed validation with JSON output, got %q (%v)", out, err)
+	}
+}
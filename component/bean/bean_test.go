@@ -0,0 +1,160 @@
+package bean
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+type orderService struct {
+	calls int
+}
+
+func (s *orderService) Validate(order string) (string, error) {
+	s.calls++
+	if order == "" {
+		return "", errors.New("empty order")
+	}
+	return "valid:" + order, nil
+}
+
+func (s *orderService) Price(item string, qty int) float64 {
+	return float64(qty) * 2.5
+}
+
+func (s *orderService) Tag(ex *core.Exchange, headers map[string]interface{}) {
+	ex.In().SetHeader("tagged", headers["customer"])
+}
+
+type upper struct{}
+
+func (upper) Process(ctx core.Context, exchange *core.Exchange) error {
+	exchange.In().SetBody(strings.ToUpper(exchange.In().Body().(string)))
+	return nil
+}
+
+func newTestContext() (*core.DefaultContext, *orderService) {
+	ctx := core.NewContext()
+	ctx.RegisterComponent("bean", NewBeanComponent(ctx.Registry()))
+	svc := &orderService{}
+	ctx.Registry().Bind("orders", svc)
+	ctx.Registry().Bind("upper", upper{})
+	ctx.Registry().Bind("double", func(n int) int { return 2 * n })
+	return ctx, svc
+}
+
+func call(t *testing.T, ctx *core.DefaultContext, uri string, ex *core.Exchange) error {
+	t.Helper()
+	ep, err := ctx.GetEndpoint(uri)
+	if err != nil {
+		t.Fatalf("GetEndpoint(%s): %v", uri, err)
+	}
+	prod, err := ep.CreateProducer()
+	if err != nil {
+		t.Fatalf("CreateProducer: %v", err)
+	}
+	return prod.Process(ctx, ex)
+}
+
+func TestBean_Methods(t *testing.T) {
+	ctx, svc := newTestContext()
+
+	ex := core.NewExchange()
+	ex.In().SetBody("A1")
+	if err := call(t, ctx, "bean:orders?method=Validate", ex); err != nil {
+		t.Fatal(err)
+	}
+	if ex.In().Body() != "valid:A1" || svc.calls != 1 {
+		t.Errorf("expected the body to be bound and the result set, got %v", ex.In().Body())
+	}
+
+	ex = core.NewExchange()
+	ex.In().SetBody("")
+	if err := call(t, ctx, "bean:orders?method=Validate", ex); err == nil || err.Error() != "empty order" {
+		t.Errorf("expected the method's error, got %v", err)
+	}
+
+	ex = core.NewExchange()
+	ex.In().SetBody("apple")
+	ex.In().SetHeader("qty", "4")
+	if err := call(t, ctx, "bean:orders?method=Price(${body}, ${header.qty})", ex); err != nil {
+		t.Fatal(err)
+	}
+	if ex.In().Body() != 10.0 {
+		t.Errorf("expected arguments converted from strings, got %v", ex.In().Body())
+	}
+
+	ex = core.NewExchange()
+	ex.In().SetBody("keep")
+	ex.In().SetHeader("customer", "acme")
+	if err := call(t, ctx, "bean:orders?method=Tag", ex); err != nil {
+		t.Fatal(err)
+	}
+	if ex.In().Header("tagged") != "acme" || ex.In().Body() != "keep" {
+		t.Errorf("expected the exchange and headers bound by type, got %v %v", ex.In().Header("tagged"), ex.In().Body())
+	}
+}
+
+func TestBean_ProcessorsAndFunctions(t *testing.T) {
+	ctx, _ := newTestContext()
+
+	ex := core.NewExchange()
+	ex.In().SetBody("abc")
+	if err := call(t, ctx, "bean:upper", ex); err != nil {
+		t.Fatal(err)
+	}
+	if ex.In().Body() != "ABC" {
+		t.Errorf("expected Process to be called, got %v", ex.In().Body())
+	}
+
+	ex = core.NewExchange()
+	ex.In().SetBody("21")
+	if err := call(t, ctx, "bean:double", ex); err != nil {
+		t.Fatal(err)
+	}
+	if ex.In().Body() != 42 {
+		t.Errorf("expected the function to be called, got %v", ex.In().Body())
+	}
+}
+
+func TestBean_Errors(t *testing.T) {
+	ctx, _ := newTestContext()
+	cases := map[string]string{
+		"bean:missing":                          `no bean named "missing"`,
+		"bean:orders":                           "choose one with ?method=",
+		"bean:orders?method=Cancel":             "has no method Cancel; it has Price, Tag, Validate",
+		"bean:orders?method=Price":              "cannot bind parameter 2",
+		"bean:orders?method=Price(${body})":     "1 arguments given",
+		"bean:orders?method=Validate(${body}":   "missing closing parenthesis",
+		"bean:orders?method=Validate(a, b)":     "2 arguments given for 1 parameters",
+		"bean:orders?method=Validate&timeout=1": "unknown option",
+	}
+	for uri, want := range cases {
+		if _, err := ctx.GetEndpoint(uri); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", uri, want, err)
+		}
+	}
+
+	ex := core.NewExchange()
+	ex.In().SetBody("x")
+	ex.In().SetHeader("qty", "many")
+	err := call(t, ctx, "bean:orders?method=Price(${body}, ${header.qty})", ex)
+	if err == nil || !strings.Contains(err.Error(), `cannot convert "many" to int`) {
+		t.Errorf("expected a conversion error, got %v", err)
+	}
+}
+
+func TestBean_MethodCache(t *testing.T) {
+	comp := NewBeanComponent(core.NewMapRegistry())
+	if _, err := comp.invoker(&orderService{}, "Validate"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := comp.invoker(&orderService{}, "Validate"); err != nil {
+		t.Fatal(err)
+	}
+	if len(comp.methods) != 1 {
+		t.Errorf("expected beans of one type to share the prepared method, got %d entries", len(comp.methods))
+	}
+}
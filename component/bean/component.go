@@ -0,0 +1,102 @@
+package bean
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// BeanComponent calls methods of beans in a registry, e.g.
+// bean:orderService?method=Validate. The endpoint path is the bean name.
+//
+// Without arguments, parameters are bound by type: core.Context,
+// *core.Exchange, *core.Message and the headers
+// (map[string]interface{}) get those, and a single other parameter gets
+// the body. Arguments may be given as simple expressions instead, e.g.
+// method=Validate(${body}, ${header.customer}); parameters of the types
+// above are still bound by type. Values are converted to the parameter types
+// with the context's type converter. A non-error result becomes the new body.
+//
+// A bean that is a function is called as is, and a bean implementing
+// core.Processor is called through Process when no method is given.
+type BeanComponent struct {
+	registry core.Registry
+
+	mu      sync.Mutex
+	methods map[methodKey]*method
+}
+
+// NewBeanComponent returns a component looking beans up in registry,
+// usually the context's.
+func NewBeanComponent(registry core.Registry) *BeanComponent {
+	return &BeanComponent{registry: registry, methods: make(map[methodKey]*method)}
+}
+
+func (c *BeanComponent) GetScheme() string {
+	return "bean"
+}
+
+// Options are the endpoint options of the bean component.
+type Options struct {
+	Name   string `option:"path" required:"true" description:"The name of the bean in the registry."`
+	Method string `option:"method" description:"The method to call, optionally with arguments, e.g. Validate(${body}, ${header.id})."`
+}
+
+// CreateEndpoint looks the bean and its method up, so unknown beans and
+// methods are reported when the route is added.
+func (c *BeanComponent) CreateEndpoint(epCfg core.EndpointConfig) (core.Endpoint, error) {
+	var opts Options
+	if err := core.BindOptions(epCfg.Params, &opts); err != nil {
+		return nil, fmt.Errorf("bean endpoint %s: %w", epCfg.RawURI, err)
+	}
+	bean, ok := c.registry.Lookup(opts.Name)
+	if !ok {
+		return nil, fmt.Errorf("bean endpoint %s: no bean named %q in the registry", epCfg.RawURI, opts.Name)
+	}
+	inv, err := c.invoker(bean, opts.Method)
+	if err != nil {
+		return nil, fmt.Errorf("bean endpoint %s: %w", epCfg.RawURI, err)
+	}
+	return NewBeanEndpoint(epCfg, opts, inv), nil
+}
+
+// invoker prepares calls of methodSpec on bean.
+func (c *BeanComponent) invoker(bean interface{}, methodSpec string) (invoker, error) {
+	v := reflect.ValueOf(bean)
+	if methodSpec == "" {
+		if p, ok := bean.(core.Processor); ok {
+			return func(ctx core.Context, exchange *core.Exchange) error {
+				return p.Process(ctx, exchange)
+			}, nil
+		}
+	}
+	name, args, err := parseMethodSpec(methodSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	key := methodKey{typ: v.Type(), spec: methodSpec}
+	c.mu.Lock()
+	m, cached := c.methods[key]
+	c.mu.Unlock()
+	if !cached {
+		if m, err = newMethod(v.Type(), name, args); err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.methods[key] = m
+		c.mu.Unlock()
+	}
+	return m.bind(v), nil
+}
+
+func (c *BeanComponent) Descriptor() core.ComponentDescriptor {
+	return core.ComponentDescriptor{
+		Syntax:      "bean:name",
+		Description: "Calls a method of a bean in the registry.",
+		Producer:    true,
+		Options:     core.DescribeOptions(Options{}),
+	}
+}
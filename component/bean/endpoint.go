@@ -0,0 +1,34 @@
+package bean
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// invoker calls the bean method of an endpoint for an exchange.
+type invoker func(ctx core.Context, exchange *core.Exchange) error
+
+type BeanEndpoint struct {
+	core.EndpointConfig
+	Options Options
+
+	invoke invoker
+}
+
+func NewBeanEndpoint(epCfg core.EndpointConfig, opts Options, invoke invoker) *BeanEndpoint {
+	return &BeanEndpoint{
+		EndpointConfig: epCfg,
+		Options:        opts,
+		invoke:         invoke,
+	}
+}
+func (e *BeanEndpoint) CreateProducer() (core.Producer, error) {
+	return NewBeanProducer(e), nil
+}
+func (e *BeanEndpoint) CreateConsumer(target core.Processor) (core.Consumer, error) {
+	return nil, fmt.Errorf("bean endpoint %s does not support consumers", e.RawURI)
+}
+func (e *BeanEndpoint) GetURI() string {
+	return e.RawURI
+}
@@ -0,0 +1,236 @@
+package bean
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/language"
+)
+
+var (
+	contextType  = reflect.TypeOf((*core.Context)(nil)).Elem()
+	exchangeType = reflect.TypeOf((*core.Exchange)(nil))
+	messageType  = reflect.TypeOf((*core.Message)(nil))
+	headersType  = reflect.TypeOf(map[string]interface{}(nil))
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// methodKey identifies a prepared method in the component's cache.
+type methodKey struct {
+	typ  reflect.Type
+	spec string
+}
+
+type paramKind int
+
+const (
+	paramContext paramKind = iota
+	paramExchange
+	paramMessage
+	paramHeaders
+	paramBody
+	paramArg
+)
+
+type param struct {
+	kind paramKind
+	typ  reflect.Type
+	arg  core.Expression
+}
+
+// method is a bean method with its parameter bindings worked out, so calls
+// need no further lookups.
+type method struct {
+	name     string
+	index    int // in the method set of the bean type; -1 for function beans
+	params   []param
+	hasValue bool
+	hasError bool
+}
+
+// parseMethodSpec splits "Validate(${body}, x)" into the name and the
+// arguments. Without parentheses args is nil.
+func parseMethodSpec(spec string) (name string, args []string, err error) {
+	name, rest, hasArgs := strings.Cut(spec, "(")
+	name = strings.TrimSpace(name)
+	if !hasArgs {
+		return name, nil, nil
+	}
+	if !strings.HasSuffix(rest, ")") {
+		return "", nil, fmt.Errorf("method %q: missing closing parenthesis", spec)
+	}
+	rest = strings.TrimSpace(strings.TrimSuffix(rest, ")"))
+	args = []string{}
+	if rest == "" {
+		return name, args, nil
+	}
+	depth, start := 0, 0
+	for i, r := range rest {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(rest[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return name, append(args, strings.TrimSpace(rest[start:])), nil
+}
+
+// newMethod prepares the method called name of beans of type t. An empty
+// name selects the function itself for function beans, or the only method.
+func newMethod(t reflect.Type, name string, args []string) (*method, error) {
+	m := &method{name: name, index: -1}
+	var in []reflect.Type
+	var out []reflect.Type
+	switch {
+	case name == "" && t.Kind() == reflect.Func:
+		m.name = t.String()
+		for i := 0; i < t.NumIn(); i++ {
+			in = append(in, t.In(i))
+		}
+		for i := 0; i < t.NumOut(); i++ {
+			out = append(out, t.Out(i))
+		}
+		if t.IsVariadic() {
+			return nil, fmt.Errorf("%s: variadic functions are not supported", m.name)
+		}
+	default:
+		if name == "" {
+			if t.NumMethod() != 1 {
+				return nil, fmt.Errorf("%s has methods %s; choose one with ?method=", t, methodNames(t))
+			}
+			name = t.Method(0).Name
+		}
+		mt, ok := t.MethodByName(name)
+		if !ok {
+			return nil, fmt.Errorf("%s has no method %s; it has %s", t, name, methodNames(t))
+		}
+		m.name, m.index = t.String()+"."+name, mt.Index
+		if mt.Type.IsVariadic() {
+			return nil, fmt.Errorf("%s: variadic methods are not supported", m.name)
+		}
+		for i := 1; i < mt.Type.NumIn(); i++ { // skip the receiver
+			in = append(in, mt.Type.In(i))
+		}
+		for i := 0; i < mt.Type.NumOut(); i++ {
+			out = append(out, mt.Type.Out(i))
+		}
+	}
+
+	next, body := 0, false
+	for i, pt := range in {
+		p := param{typ: pt}
+		switch pt {
+		case contextType:
+			p.kind = paramContext
+		case exchangeType:
+			p.kind = paramExchange
+		case messageType:
+			p.kind = paramMessage
+		case headersType:
+			p.kind = paramHeaders
+		default:
+			switch {
+			case args != nil:
+				if next >= len(args) {
+					return nil, fmt.Errorf("%s: %d arguments given, but parameter %d (%s) has none", m.name, len(args), i+1, pt)
+				}
+				expr, err := language.Simple(args[next])
+				if err != nil {
+					return nil, fmt.Errorf("%s: argument %d: %w", m.name, next+1, err)
+				}
+				p.kind, p.arg = paramArg, expr
+				next++
+			case body:
+				return nil, fmt.Errorf("%s: cannot bind parameter %d (%s); give the arguments, e.g. method=%s(${body}, ${header.name})", m.name, i+1, pt, name)
+			default:
+				p.kind, body = paramBody, true
+			}
+		}
+		m.params = append(m.params, p)
+	}
+	if args != nil && next != len(args) {
+		return nil, fmt.Errorf("%s: %d arguments given for %d parameters", m.name, len(args), next)
+	}
+
+	switch {
+	case len(out) == 1 && out[0] == errorType:
+		m.hasError = true
+	case len(out) == 1:
+		m.hasValue = true
+	case len(out) == 2 && out[1] == errorType:
+		m.hasValue, m.hasError = true, true
+	case len(out) > 1:
+		return nil, fmt.Errorf("%s: results must be (value), (error) or (value, error)", m.name)
+	}
+	return m, nil
+}
+
+// bind returns an invoker calling m on bean.
+func (m *method) bind(bean reflect.Value) invoker {
+	fn := bean
+	if m.index >= 0 {
+		fn = bean.Method(m.index)
+	}
+	return func(ctx core.Context, exchange *core.Exchange) error {
+		in := make([]reflect.Value, len(m.params))
+		for i, p := range m.params {
+			var v interface{}
+			switch p.kind {
+			case paramContext:
+				v = ctx
+			case paramExchange:
+				v = exchange
+			case paramMessage:
+				v = exchange.In()
+			case paramHeaders:
+				v = exchange.In().Headers()
+			case paramBody:
+				v = exchange.In().Body()
+			case paramArg:
+				var err error
+				if v, err = p.arg.Evaluate(ctx, exchange); err != nil {
+					return fmt.Errorf("%s: argument %d: %w", m.name, i+1, err)
+				}
+			}
+			arg, err := core.Convert(ctx, v, p.typ)
+			if err != nil {
+				return fmt.Errorf("%s: parameter %d: %w", m.name, i+1, err)
+			}
+			if arg == nil {
+				in[i] = reflect.Zero(p.typ)
+			} else {
+				in[i] = reflect.ValueOf(arg)
+			}
+		}
+
+		out := fn.Call(in)
+		if m.hasError {
+			if err := out[len(out)-1]; !err.IsNil() {
+				return err.Interface().(error)
+			}
+		}
+		if m.hasValue {
+			exchange.In().SetBody(out[0].Interface())
+		}
+		return nil
+	}
+}
+
+func methodNames(t reflect.Type) string {
+	if t.NumMethod() == 0 {
+		return "no exported methods"
+	}
+	names := make([]string, t.NumMethod())
+	for i := range names {
+		names[i] = t.Method(i).Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,28 @@
+package bean
+
+import "github.com/sonyjop/camelgo/core"
+
+// BeanProducer calls the endpoint's bean method for each exchange.
+type BeanProducer struct {
+	core.ServiceSupport
+
+	endpoint *BeanEndpoint
+}
+
+func NewBeanProducer(endpoint *BeanEndpoint) *BeanProducer {
+	return &BeanProducer{
+		endpoint: endpoint,
+	}
+}
+
+func (p *BeanProducer) Start(ctx core.Context) error {
+	return p.DoStart(nil)
+}
+
+func (p *BeanProducer) Stop(ctx core.Context) error {
+	return p.DoStop(nil)
+}
+
+func (p *BeanProducer) Process(ctx core.Context, exchange *core.Exchange) error {
+	return p.endpoint.invoke(ctx, exchange)
+}
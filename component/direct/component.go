@@ -0,0 +1,56 @@
+package direct
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// DirectComponent provides synchronous, in-memory calls between routes.
+// Endpoints are shared by name, so every "direct:a" URI reaches the same
+// consumer.
+type DirectComponent struct {
+	mu        sync.Mutex
+	endpoints map[string]*DirectEndpoint
+}
+
+func NewDirectComponent() *DirectComponent {
+	return &DirectComponent{endpoints: make(map[string]*DirectEndpoint)}
+}
+
+func (c *DirectComponent) GetScheme() string {
+	return "direct"
+}
+
+// Options are the endpoint options of the direct component.
+type Options struct {
+	Name string `option:"path" required:"true" description:"The endpoint name shared by producers and the consuming route."`
+}
+
+func (c *DirectComponent) CreateEndpoint(epCfg core.EndpointConfig) (core.Endpoint, error) {
+	var opts Options
+	if err := core.BindOptions(epCfg.Params, &opts); err != nil {
+		return nil, fmt.Errorf("direct endpoint %s: %w", epCfg.RawURI, err)
+	}
+	name := opts.Name
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ep, ok := c.endpoints[name]; ok {
+		return ep, nil
+	}
+	ep := NewDirectEndpoint(epCfg, name)
+	c.endpoints[name] = ep
+	return ep, nil
+}
+
+func (c *DirectComponent) Descriptor() core.ComponentDescriptor {
+	return core.ComponentDescriptor{
+		Syntax:      "direct:name",
+		Description: "Calls another route synchronously on the caller's goroutine.",
+		Consumer:    true,
+		Producer:    true,
+		Options:     core.DescribeOptions(Options{}),
+	}
+}
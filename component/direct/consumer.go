@@ -0,0 +1,34 @@
+package direct
+
+import "github.com/sonyjop/camelgo/core"
+
+// DirectConsumer receives exchanges from DirectProducers on the calling
+// goroutine and hands them to its route.
+type DirectConsumer struct {
+	core.ServiceSupport
+
+	endpoint *DirectEndpoint
+	target   core.Processor
+}
+
+func NewDirectConsumer(endpoint *DirectEndpoint, target core.Processor) *DirectConsumer {
+	return &DirectConsumer{
+		endpoint: endpoint,
+		target:   target,
+	}
+}
+
+// Start registers the consumer with its endpoint.
+func (c *DirectConsumer) Start(ctx core.Context) error {
+	return c.DoStart(func() error {
+		return c.endpoint.attach(c)
+	})
+}
+
+// Stop unregisters the consumer; later sends fail until it is started again.
+func (c *DirectConsumer) Stop(ctx core.Context) error {
+	return c.DoStop(func() error {
+		c.endpoint.detach(c)
+		return nil
+	})
+}
@@ -0,0 +1,79 @@
+package direct
+
+import (
+	"testing"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+type recordingProcessor struct {
+	bodies []interface{}
+}
+
+func (r *recordingProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	r.bodies = append(r.bodies, exchange.In().Body())
+	return nil
+}
+
+func newEndpoint(t *testing.T, comp *DirectComponent, uri, name string) *DirectEndpoint {
+	t.Helper()
+	ep, err := comp.CreateEndpoint(core.EndpointConfig{RawURI: uri, Scheme: "direct", Params: map[string]interface{}{"path": name}})
+	if err != nil {
+		t.Fatalf("CreateEndpoint error: %v", err)
+	}
+	return ep.(*DirectEndpoint)
+}
+
+func TestDirect_ProducerCallsConsumer(t *testing.T) {
+	comp := NewDirectComponent()
+	in := newEndpoint(t, comp, "direct:orders", "orders")
+	out := newEndpoint(t, comp, "direct:orders?timeout=5", "orders")
+	if in != out {
+		t.Fatalf("expected endpoints with the same name to be shared")
+	}
+
+	target := &recordingProcessor{}
+	cons, _ := in.CreateConsumer(target)
+	prod, _ := out.CreateProducer()
+
+	ex := core.NewExchange()
+	ex.In().SetBody("order-1")
+	if err := prod.Process(nil, ex); err == nil {
+		t.Fatalf("expected error without a started consumer")
+	}
+
+	if err := cons.Start(nil); err != nil {
+		t.Fatalf("consumer start error: %v", err)
+	}
+	if err := prod.Process(nil, ex); err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	if len(target.bodies) != 1 || target.bodies[0] != "order-1" {
+		t.Errorf("expected consumer to receive order-1, got %v", target.bodies)
+	}
+
+	cons.Stop(nil)
+	if err := prod.Process(nil, ex); err == nil {
+		t.Errorf("expected error after consumer stopped")
+	}
+}
+
+func TestDirect_SingleConsumerPerEndpoint(t *testing.T) {
+	comp := NewDirectComponent()
+	ep := newEndpoint(t, comp, "direct:a", "a")
+
+	first, _ := ep.CreateConsumer(&recordingProcessor{})
+	second, _ := ep.CreateConsumer(&recordingProcessor{})
+	if err := first.Start(nil); err != nil {
+		t.Fatalf("first start error: %v", err)
+	}
+	if err := second.Start(nil); err == nil {
+		t.Errorf("expected second consumer on the same endpoint to fail")
+	}
+}
+
+func TestDirect_RequiresName(t *testing.T) {
+	if _, err := NewDirectComponent().CreateEndpoint(core.EndpointConfig{RawURI: "direct:", Params: map[string]interface{}{}}); err == nil {
+		t.Errorf("expected error for a direct endpoint without a name")
+	}
+}
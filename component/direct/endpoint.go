@@ -0,0 +1,57 @@
+package direct
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+type DirectEndpoint struct {
+	core.EndpointConfig
+	Name string
+
+	mu       sync.RWMutex
+	consumer *DirectConsumer
+}
+
+func NewDirectEndpoint(epCfg core.EndpointConfig, name string) *DirectEndpoint {
+	return &DirectEndpoint{
+		EndpointConfig: epCfg,
+		Name:           name,
+	}
+}
+func (e *DirectEndpoint) CreateProducer() (core.Producer, error) {
+	return NewDirectProducer(e), nil
+}
+func (e *DirectEndpoint) CreateConsumer(target core.Processor) (core.Consumer, error) {
+	return NewDirectConsumer(e, target), nil
+}
+func (e *DirectEndpoint) GetURI() string {
+	return e.RawURI
+}
+
+// attach makes c the consumer receiving exchanges sent to this endpoint.
+func (e *DirectEndpoint) attach(c *DirectConsumer) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.consumer != nil && e.consumer != c {
+		return fmt.Errorf("direct:%s already has a consumer; only one route may consume from it", e.Name)
+	}
+	e.consumer = c
+	return nil
+}
+
+func (e *DirectEndpoint) detach(c *DirectConsumer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.consumer == c {
+		e.consumer = nil
+	}
+}
+
+func (e *DirectEndpoint) currentConsumer() *DirectConsumer {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.consumer
+}
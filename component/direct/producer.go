@@ -0,0 +1,37 @@
+package direct
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// DirectProducer calls the consuming route of its endpoint synchronously.
+type DirectProducer struct {
+	core.ServiceSupport
+
+	endpoint *DirectEndpoint
+}
+
+func NewDirectProducer(endpoint *DirectEndpoint) *DirectProducer {
+	return &DirectProducer{
+		endpoint: endpoint,
+	}
+}
+
+func (p *DirectProducer) Start(ctx core.Context) error {
+	return p.DoStart(nil)
+}
+
+func (p *DirectProducer) Stop(ctx core.Context) error {
+	return p.DoStop(nil)
+}
+
+// Process runs the consuming route on the caller's goroutine.
+func (p *DirectProducer) Process(ctx core.Context, exchange *core.Exchange) error {
+	consumer := p.endpoint.currentConsumer()
+	if consumer == nil {
+		return fmt.Errorf("no consumers available on endpoint: direct:%s", p.endpoint.Name)
+	}
+	return consumer.target.Process(ctx, exchange)
+}
@@ -1,6 +1,13 @@
 package file
 
-import "github.com/sonyjop/camelgo/core"
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// logCategory is the log category of this component.
+const logCategory = "component.file"
 
 type FileComponent struct{}
 
@@ -12,6 +19,25 @@ func (c *FileComponent) GetScheme() string {
 	return "file"
 }
 
+// Options are the endpoint options of the file component.
+type Options struct {
+	Path string `option:"path" required:"true" description:"The file to read or write."`
+}
+
 func (c *FileComponent) CreateEndpoint(epCfg core.EndpointConfig) (core.Endpoint, error) {
-	return NewFileEndpoint(epCfg), nil
+	var opts Options
+	if err := core.BindOptions(epCfg.Params, &opts); err != nil {
+		return nil, fmt.Errorf("file endpoint %s: %w", epCfg.RawURI, err)
+	}
+	return NewFileEndpoint(epCfg, opts), nil
+}
+
+func (c *FileComponent) Descriptor() core.ComponentDescriptor {
+	return core.ComponentDescriptor{
+		Syntax:      "file:path",
+		Description: "Reads a file line by line, or appends message bodies to a file.",
+		Consumer:    true,
+		Producer:    true,
+		Options:     core.DescribeOptions(Options{}),
+	}
 }
@@ -4,96 +4,195 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
 
 	"github.com/sonyjop/camelgo/core"
 )
 
 // FileConsumer reads messages from a file and passes them to a processor.
 type FileConsumer struct {
+	core.ServiceSupport
+
 	endpoint *FileEndpoint
 	target   core.Processor
 	file     *os.File
-	running  bool
 	stopChan chan struct{}
+	failures chan error
+
+	mu      sync.Mutex
+	resumed chan struct{} // non-nil while suspended, closed on resume
 }
 
 func NewFileConsumer(endpoint *FileEndpoint, target core.Processor) *FileConsumer {
 	return &FileConsumer{
 		endpoint: endpoint,
 		target:   target,
-		running:  false,
-		stopChan: make(chan struct{}),
+		failures: make(chan error, 1),
 	}
 }
 
 // Start opens the file and begins reading lines, sending each as a message to the target processor.
 func (c *FileConsumer) Start(ctx core.Context) error {
-	if c.running {
-		return nil // idempotent
-	}
+	return c.DoStart(func() error {
+		filePath := c.endpoint.Options.Path
 
-	// Extract file path from URI
-	filePath := c.endpoint.Params["path"].(string)
-
-	// Open file for reading
-	f, err := os.Open(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to open file %s: %w", filePath, err)
-	}
+		// Open file for reading
+		f, err := os.Open(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %w", filePath, err)
+		}
 
-	c.file = f
-	c.running = true
+		c.file = f
+		c.stopChan = make(chan struct{})
 
-	// Start reading in a goroutine
-	go c.readLoop(ctx)
+		// Start reading in a goroutine
+		go c.readLoop(ctx, f, c.stopChan)
 
-	return nil
+		return nil
+	})
 }
 
 // Stop closes the file and stops reading.
 func (c *FileConsumer) Stop(ctx core.Context) error {
-	if !c.running {
+	return c.DoStop(func() error {
+		// Signal read loop to stop
+		if c.stopChan != nil {
+			close(c.stopChan)
+			c.stopChan = nil
+		}
+
+		c.mu.Lock()
+		c.resumed = nil
+		c.mu.Unlock()
+
+		if c.file != nil {
+			f := c.file
+			c.file = nil
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("failed to close file: %w", err)
+			}
+		}
 		return nil
-	}
+	})
+}
 
-	// Signal read loop to stop
-	close(c.stopChan)
+// Suspend implements core.Suspendable: the file stays open and reading
+// continues from the same line on Resume, where restarting the consumer
+// would read the file again from the start.
+func (c *FileConsumer) Suspend(ctx core.Context) error {
+	return c.DoSuspend(func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.resumed = make(chan struct{})
+		return nil
+	})
+}
 
-	if c.file != nil {
-		if err := c.file.Close(); err != nil {
-			return fmt.Errorf("failed to close file: %w", err)
+// Resume continues reading after Suspend.
+func (c *FileConsumer) Resume(ctx core.Context) error {
+	return c.DoResume(func() error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.resumed != nil {
+			close(c.resumed)
+			c.resumed = nil
 		}
-	}
+		return nil
+	})
+}
 
-	c.running = false
-	return nil
+// awaitResume blocks while the consumer is suspended and reports whether
+// reading should continue.
+func (c *FileConsumer) awaitResume(stop <-chan struct{}) bool {
+	c.mu.Lock()
+	resumed := c.resumed
+	c.mu.Unlock()
+	if resumed == nil {
+		return true
+	}
+	select {
+	case <-resumed:
+		return true
+	case <-stop:
+		return false
+	}
 }
 
 // readLoop reads lines from the file and passes each to the target processor.
-func (c *FileConsumer) readLoop(ctx core.Context) {
-	scanner := bufio.NewScanner(c.file)
+func (c *FileConsumer) readLoop(ctx core.Context, f *os.File, stop <-chan struct{}) {
+	scanner := bufio.NewScanner(f)
 
 	for scanner.Scan() {
 		select {
-		case <-c.stopChan:
+		case <-stop:
 			return
 		default:
 		}
+		if !c.awaitResume(stop) {
+			return
+		}
 
 		line := scanner.Text()
 
 		// Create an exchange with the line as the message body
-		exchange := core.NewExchange()
+		exchange := c.newExchange(ctx)
 		exchange.In().SetBody(line)
 
 		// Process the exchange
 		if err := c.target.Process(ctx, exchange); err != nil {
-			// Log error or propagate as needed
-			fmt.Printf("error processing line: %v\n", err)
+			core.LogExchangeFailure(ctx, logCategory, exchange, err, "error processing line")
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		fmt.Printf("scanner error: %v\n", err)
+		core.Log(ctx, logCategory).WithError(err).WithField(core.LogFieldEndpointURI, core.MaskURI(ctx, c.endpoint.GetURI())).Error("scanner error")
+		c.reportFailure(err, stop)
+	}
+}
+
+// newExchange creates the exchange through the context when there is one,
+// so it gets a unique ID and ExchangeCreated is reported.
+func (c *FileConsumer) newExchange(ctx core.Context) *core.Exchange {
+	if ctx == nil {
+		return core.NewExchange()
+	}
+	return ctx.NewExchange()
+}
+
+// Health implements core.HealthAware: the file's directory must be readable
+// and the file itself must exist and be readable.
+func (c *FileConsumer) Health(ctx core.Context) error {
+	filePath := c.endpoint.Options.Path
+	dir, err := os.Open(filepath.Dir(filePath))
+	if err != nil {
+		return fmt.Errorf("directory not readable: %w", err)
+	}
+	dir.Close()
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("file not readable: %w", err)
+	}
+	return f.Close()
+}
+
+// Failures implements core.FailureNotifier so a supervising route controller
+// can restart the route when the read loop dies.
+func (c *FileConsumer) Failures() <-chan error {
+	return c.failures
+}
+
+// reportFailure publishes err unless the consumer is being stopped or a
+// previous failure is still pending.
+func (c *FileConsumer) reportFailure(err error, stop <-chan struct{}) {
+	select {
+	case <-stop:
+		return
+	default:
+	}
+	select {
+	case c.failures <- err:
+	default:
 	}
 }
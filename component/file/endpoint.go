@@ -4,13 +4,13 @@ import "github.com/sonyjop/camelgo/core"
 
 type FileEndpoint struct {
 	core.EndpointConfig
-	//uri       string
-	//component *FileComponent
+	Options Options
 }
 
-func NewFileEndpoint(epCfg core.EndpointConfig) *FileEndpoint {
+func NewFileEndpoint(epCfg core.EndpointConfig, opts Options) *FileEndpoint {
 	return &FileEndpoint{
 		EndpointConfig: epCfg,
+		Options:        opts,
 	}
 }
 func (e *FileEndpoint) CreateProducer() (core.Producer, error) {
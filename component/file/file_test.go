@@ -3,6 +3,7 @@ package file
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ func TestCreateEndpoint(t *testing.T) {
 	cfg := core.EndpointConfig{
 		RawURI: "file:/tmp/f.txt",
 		Scheme: "file",
+		Params: map[string]interface{}{"path": "/tmp/f.txt"},
 	}
 
 	comp := NewFileComponent()
@@ -24,6 +26,19 @@ func TestCreateEndpoint(t *testing.T) {
 	if ep.GetURI() != cfg.RawURI {
 		t.Fatalf("expected URI %s, got %s", cfg.RawURI, ep.GetURI())
 	}
+	if ep.(*FileEndpoint).Options.Path != "/tmp/f.txt" {
+		t.Fatalf("expected path to be bound, got %+v", ep.(*FileEndpoint).Options)
+	}
+}
+
+func TestCreateEndpoint_InvalidOptions(t *testing.T) {
+	comp := NewFileComponent()
+	if _, err := comp.CreateEndpoint(core.EndpointConfig{RawURI: "file:", Params: map[string]interface{}{}}); err == nil {
+		t.Error("expected a missing path to fail instead of panicking on start")
+	}
+	if _, err := comp.CreateEndpoint(core.EndpointConfig{RawURI: "file:a?mode=read", Params: map[string]interface{}{"path": "a", "mode": "read"}}); err == nil {
+		t.Error("expected an unknown option to fail")
+	}
 }
 
 func TestFileProducer_WriteAndClose(t *testing.T) {
@@ -36,7 +51,7 @@ func TestFileProducer_WriteAndClose(t *testing.T) {
 		Params: map[string]interface{}{"path": path},
 	}
 
-	ep := NewFileEndpoint(cfg)
+	ep := NewFileEndpoint(cfg, Options{Path: path})
 	prod, err := ep.CreateProducer()
 	if err != nil {
 		t.Fatalf("CreateProducer error: %v", err)
@@ -101,7 +116,7 @@ func TestFileConsumer_ReadLines(t *testing.T) {
 		Params: map[string]interface{}{"path": path},
 	}
 
-	ep := NewFileEndpoint(cfg)
+	ep := NewFileEndpoint(cfg, Options{Path: path})
 
 	wg := &sync.WaitGroup{}
 	wg.Add(len(lines))
@@ -144,3 +159,62 @@ func TestFileConsumer_ReadLines(t *testing.T) {
 		}
 	}
 }
+
+func TestFileConsumer_SuspendResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "in.txt")
+	if err := os.WriteFile(path, []byte("one\ntwo\nthree\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ep := NewFileEndpoint(core.EndpointConfig{RawURI: "file:" + path}, Options{Path: path})
+
+	first, release := make(chan struct{}), make(chan struct{})
+	var mu sync.Mutex
+	var bodies []string
+	cons, err := ep.CreateConsumer(core.ProcessorFunc(func(ctx core.Context, ex *core.Exchange) error {
+		mu.Lock()
+		bodies = append(bodies, ex.In().Body().(string))
+		n := len(bodies)
+		mu.Unlock()
+		if n == 1 {
+			close(first)
+			<-release
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cons.Start(nil); err != nil {
+		t.Fatal(err)
+	}
+	defer cons.Stop(nil)
+
+	<-first
+	if err := cons.(core.Suspendable).Suspend(nil); err != nil {
+		t.Fatalf("suspend error: %v", err)
+	}
+	close(release)
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	if len(bodies) != 1 {
+		t.Errorf("expected no lines to be read while suspended, got %v", bodies)
+	}
+	mu.Unlock()
+
+	if err := cons.(core.Suspendable).Resume(nil); err != nil {
+		t.Fatalf("resume error: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		got := strings.Join(bodies, ",")
+		mu.Unlock()
+		if got == "one,two,three" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected reading to continue where it stopped, got %s", got)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -9,57 +9,51 @@ import (
 
 // FileProducer writes messages to a file.
 type FileProducer struct {
+	core.ServiceSupport
+
 	endpoint *FileEndpoint
 	file     *os.File
-	running  bool
 }
 
 func NewFileProducer(endpoint *FileEndpoint) *FileProducer {
 	return &FileProducer{
 		endpoint: endpoint,
-		running:  false,
 	}
 }
 
 // Start opens the file for writing.
 func (p *FileProducer) Start(ctx core.Context) error {
-	if p.running {
-		return nil // idempotent
-	}
+	return p.DoStart(func() error {
+		filePath := p.endpoint.Options.Path
 
-	// Extract file path from URI (e.g., "file:/path/to/file.txt" -> "/path/to/file.txt")
-	filePath := p.endpoint.Params["path"].(string)
-
-	// Open file in append mode, create if not exists
-	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to open file %s: %w", filePath, err)
-	}
+		// Open file in append mode, create if not exists
+		f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %w", filePath, err)
+		}
 
-	p.file = f
-	p.running = true
-	return nil
+		p.file = f
+		return nil
+	})
 }
 
 // Stop closes the file.
 func (p *FileProducer) Stop(ctx core.Context) error {
-	if !p.running {
-		return nil
-	}
-
-	if p.file != nil {
-		if err := p.file.Close(); err != nil {
-			return fmt.Errorf("failed to close file: %w", err)
+	return p.DoStop(func() error {
+		if p.file != nil {
+			f := p.file
+			p.file = nil
+			if err := f.Close(); err != nil {
+				return fmt.Errorf("failed to close file: %w", err)
+			}
 		}
-	}
-
-	p.running = false
-	return nil
+		return nil
+	})
 }
 
 // Process writes the message body to the file.
 func (p *FileProducer) Process(ctx core.Context, exchange *core.Exchange) error {
-	if !p.running {
+	if !p.IsStarted() {
 		return fmt.Errorf("FileProducer not started")
 	}
 
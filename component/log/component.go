@@ -0,0 +1,62 @@
+package log
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// LogComponent logs the exchanges sent to it, e.g.
+// log:orders?level=INFO&showHeaders=true&maxChars=200. The endpoint path is
+// the log category.
+type LogComponent struct{}
+
+func NewLogComponent() *LogComponent {
+	return &LogComponent{}
+}
+
+func (c *LogComponent) GetScheme() string {
+	return "log"
+}
+
+// Options are the endpoint options of the log component.
+type Options struct {
+	Name           string `option:"path" required:"true" description:"The log category."`
+	Level          string `option:"level" default:"info" enum:"trace,debug,info,warn,error" description:"The level to log at."`
+	ShowBody       bool   `option:"showBody" default:"true" description:"Include the body."`
+	ShowHeaders    bool   `option:"showHeaders" default:"false" description:"Include the headers."`
+	ShowProperties bool   `option:"showProperties" default:"false" description:"Include the exchange properties."`
+	ShowExchangeID bool   `option:"showExchangeId" default:"false" description:"Include the exchange ID."`
+	Mask           bool   `option:"mask" default:"true" description:"Mask the values of sensitive headers and properties."`
+	MaxChars       int    `option:"maxChars" default:"0" min:"0" description:"Truncate the logged text to this many characters; 0 means no limit."`
+}
+
+func (c *LogComponent) CreateEndpoint(epCfg core.EndpointConfig) (core.Endpoint, error) {
+	var opts Options
+	if err := core.BindOptions(epCfg.Params, &opts); err != nil {
+		return nil, fmt.Errorf("log endpoint %s: %w", epCfg.RawURI, err)
+	}
+	level, err := logrus.ParseLevel(opts.Level)
+	if err != nil {
+		return nil, fmt.Errorf("log endpoint %s: %w", epCfg.RawURI, err)
+	}
+	return NewLogEndpoint(epCfg, opts.Name, level, FormatOptions{
+		ShowExchangeID: opts.ShowExchangeID,
+		ShowHeaders:    opts.ShowHeaders,
+		ShowProperties: opts.ShowProperties,
+		ShowBody:       opts.ShowBody,
+		Mask:           opts.Mask,
+		MaxChars:       opts.MaxChars,
+	}), nil
+}
+
+func (c *LogComponent) Descriptor() core.ComponentDescriptor {
+	return core.ComponentDescriptor{
+		Syntax:      "log:loggerName",
+		Description: "Logs the exchanges sent to it.",
+		Producer:    true,
+		Options:     core.DescribeOptions(Options{}),
+	}
+}
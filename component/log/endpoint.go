@@ -0,0 +1,34 @@
+package log
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+type LogEndpoint struct {
+	core.EndpointConfig
+	Name    string
+	Level   logrus.Level
+	Options FormatOptions
+}
+
+func NewLogEndpoint(epCfg core.EndpointConfig, name string, level logrus.Level, opts FormatOptions) *LogEndpoint {
+	return &LogEndpoint{
+		EndpointConfig: epCfg,
+		Name:           name,
+		Level:          level,
+		Options:        opts,
+	}
+}
+func (e *LogEndpoint) CreateProducer() (core.Producer, error) {
+	return NewLogProducer(e), nil
+}
+func (e *LogEndpoint) CreateConsumer(target core.Processor) (core.Consumer, error) {
+	return nil, fmt.Errorf("log endpoint %s does not support consumers", e.RawURI)
+}
+func (e *LogEndpoint) GetURI() string {
+	return e.RawURI
+}
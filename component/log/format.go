@@ -0,0 +1,73 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// MaskedValue replaces the values of sensitive headers and properties.
+const MaskedValue = core.MaskedValue
+
+// IsSensitive reports whether a header or property named name should be
+// masked when logged.
+func IsSensitive(name string) bool {
+	return core.IsSensitive(name)
+}
+
+// FormatOptions controls what FormatExchange includes.
+type FormatOptions struct {
+	ShowExchangeID bool
+	ShowHeaders    bool
+	ShowProperties bool
+	ShowBody       bool
+	Mask           bool // mask sensitive headers and properties
+	MaxChars       int  // truncate the result; 0 means no limit
+}
+
+// DefaultFormatOptions shows only the body and masks sensitive values.
+func DefaultFormatOptions() FormatOptions {
+	return FormatOptions{ShowBody: true, Mask: true}
+}
+
+// FormatExchange renders exchange as
+// "Exchange[Id: 1, Headers: {a=b}, Body: hello]".
+func FormatExchange(exchange *core.Exchange, opts FormatOptions) string {
+	var fields []string
+	if opts.ShowExchangeID {
+		fields = append(fields, "Id: "+exchange.ID())
+	}
+	if opts.ShowHeaders {
+		fields = append(fields, "Headers: "+formatMap(exchange.In().Headers(), opts.Mask))
+	}
+	if opts.ShowProperties {
+		fields = append(fields, "Properties: "+formatMap(exchange.Properties(), opts.Mask))
+	}
+	if opts.ShowBody {
+		fields = append(fields, fmt.Sprintf("Body: %v", exchange.In().Body()))
+	}
+	out := "Exchange[" + strings.Join(fields, ", ") + "]"
+	if runes := []rune(out); opts.MaxChars > 0 && len(runes) > opts.MaxChars {
+		out = string(runes[:opts.MaxChars]) + "... [truncated]"
+	}
+	return out
+}
+
+func formatMap(m map[string]interface{}, mask bool) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		v := m[k]
+		if mask && IsSensitive(k) {
+			v = MaskedValue
+		}
+		pairs[i] = fmt.Sprintf("%s=%v", k, v)
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
@@ -0,0 +1,100 @@
+package log
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/dsl"
+	"github.com/sonyjop/camelgo/internal/routetest"
+)
+
+func newLoggingContext(t *testing.T, fn func(b *dsl.BaseRouteBuilder)) (*core.DefaultContext, *test.Hook) {
+	t.Helper()
+	logger, hook := test.NewNullLogger()
+	ctx := routetest.NewContext()
+	ctx.SetLogger(logger)
+	ctx.RegisterComponent("log", NewLogComponent())
+	routetest.Start(t, ctx, fn)
+	return ctx, hook
+}
+
+func send(t *testing.T, ctx *core.DefaultContext, uri string, body interface{}, headers map[string]interface{}) *core.Exchange {
+	t.Helper()
+	ex, err := routetest.Send(ctx, uri, body, headers)
+	if err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	return ex
+}
+
+func TestLogEndpoint_FormatsAndMasks(t *testing.T) {
+	ctx, hook := newLoggingContext(t, func(b *dsl.BaseRouteBuilder) {
+		b.From("direct:orders").RouteID("orders").
+			To("log:orders?level=WARN&showHeaders=true&showBody=true&maxChars=200")
+	})
+
+	ex := send(t, ctx, "direct:orders", "order-1", map[string]interface{}{
+		"Authorization": "Bearer abc",
+		"region":        "eu",
+	})
+
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatalf("expected a log entry")
+	}
+	if entry.Level != logrus.WarnLevel {
+		t.Errorf("expected WARN, got %s", entry.Level)
+	}
+	if strings.Contains(entry.Message, "abc") || !strings.Contains(entry.Message, "Authorization="+MaskedValue) {
+		t.Errorf("expected Authorization to be masked, got %q", entry.Message)
+	}
+	if !strings.Contains(entry.Message, "region=eu") || !strings.Contains(entry.Message, "Body: order-1") {
+		t.Errorf("expected headers and body, got %q", entry.Message)
+	}
+	if entry.Data[core.LogFieldLogger] != "orders" || entry.Data[core.LogFieldRouteID] != "orders" || entry.Data[core.LogFieldExchangeID] != ex.ID() {
+		t.Errorf("unexpected fields %v", entry.Data)
+	}
+}
+
+func TestLogEIP_EvaluatesSimpleMessage(t *testing.T) {
+	ctx, hook := newLoggingContext(t, func(b *dsl.BaseRouteBuilder) {
+		b.From("direct:in").RouteID("in").Log(logrus.InfoLevel, "got ${body} for ${header.region}").ID("audit")
+	})
+
+	send(t, ctx, "direct:in", "order-2", map[string]interface{}{"region": "us"})
+
+	entry := hook.LastEntry()
+	if entry == nil || entry.Message != "got order-2 for us" {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	if entry.Data[core.LogFieldStepID] != "audit" {
+		t.Errorf("expected step ID field, got %v", entry.Data)
+	}
+}
+
+func TestFormatExchange_Truncates(t *testing.T) {
+	ex := core.NewExchange()
+	ex.In().SetBody(strings.Repeat("x", 100))
+	out := FormatExchange(ex, FormatOptions{ShowBody: true, MaxChars: 20})
+	if !strings.HasPrefix(out, "Exchange[Body: xxxxx") || !strings.HasSuffix(out, "... [truncated]") {
+		t.Errorf("unexpected output %q", out)
+	}
+}
+
+func TestLogComponent_InvalidOptions(t *testing.T) {
+	comp := NewLogComponent()
+	for _, params := range []map[string]interface{}{
+		{"path": "a", "level": "LOUD"},
+		{"path": "a", "showBody": "maybe"},
+		{"path": "a", "colour": "red"},
+		{},
+	} {
+		if _, err := comp.CreateEndpoint(core.EndpointConfig{RawURI: "log:a", Params: params}); err == nil {
+			t.Errorf("expected error for %v", params)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package log
+
+import "github.com/sonyjop/camelgo/core"
+
+// LogProducer writes a formatted view of each exchange to the logger named
+// after the endpoint.
+type LogProducer struct {
+	core.ServiceSupport
+
+	endpoint *LogEndpoint
+}
+
+func NewLogProducer(endpoint *LogEndpoint) *LogProducer {
+	return &LogProducer{
+		endpoint: endpoint,
+	}
+}
+
+func (p *LogProducer) Start(ctx core.Context) error {
+	return p.DoStart(nil)
+}
+
+func (p *LogProducer) Stop(ctx core.Context) error {
+	return p.DoStop(nil)
+}
+
+func (p *LogProducer) Process(ctx core.Context, exchange *core.Exchange) error {
+	logger := core.LogExchange(ctx, p.endpoint.Name, exchange)
+	if !logger.Logger.IsLevelEnabled(p.endpoint.Level) {
+		return nil
+	}
+	logger.Log(p.endpoint.Level, FormatExchange(exchange, p.endpoint.Options))
+	return nil
+}
@@ -0,0 +1,64 @@
+package seda
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// logCategory is the log category of this component.
+const logCategory = "component.seda"
+
+// DefaultQueueSize is the capacity of a queue when no size option is given.
+// It matches the default of Options.Size.
+const DefaultQueueSize = 1000
+
+// SedaComponent provides asynchronous, in-memory queues between routes.
+// Endpoints are shared by name, so producers and the consumer of "seda:a"
+// use the same queue regardless of options.
+type SedaComponent struct {
+	mu        sync.Mutex
+	endpoints map[string]*SedaEndpoint
+}
+
+func NewSedaComponent() *SedaComponent {
+	return &SedaComponent{endpoints: make(map[string]*SedaEndpoint)}
+}
+
+func (c *SedaComponent) GetScheme() string {
+	return "seda"
+}
+
+// Options are the endpoint options of the seda component.
+type Options struct {
+	Name                string `option:"path" required:"true" description:"The queue name shared by producers and the consuming route."`
+	Size                int    `option:"size" default:"1000" min:"1" description:"The queue capacity; sending to a full queue fails."`
+	ConcurrentConsumers int    `option:"concurrentConsumers" default:"1" min:"1" description:"The number of goroutines consuming from the queue."`
+}
+
+func (c *SedaComponent) CreateEndpoint(epCfg core.EndpointConfig) (core.Endpoint, error) {
+	var opts Options
+	if err := core.BindOptions(epCfg.Params, &opts); err != nil {
+		return nil, fmt.Errorf("seda endpoint %s: %w", epCfg.RawURI, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ep, ok := c.endpoints[opts.Name]; ok {
+		return ep, nil
+	}
+	ep := NewSedaEndpoint(epCfg, opts.Name, opts.Size, opts.ConcurrentConsumers)
+	c.endpoints[opts.Name] = ep
+	return ep, nil
+}
+
+func (c *SedaComponent) Descriptor() core.ComponentDescriptor {
+	return core.ComponentDescriptor{
+		Syntax:      "seda:name",
+		Description: "Passes exchanges to another route asynchronously through an in-memory queue.",
+		Consumer:    true,
+		Producer:    true,
+		Options:     core.DescribeOptions(Options{}),
+	}
+}
@@ -0,0 +1,64 @@
+package seda
+
+import (
+	"sync"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// SedaConsumer drains the endpoint's queue with ConcurrentConsumers
+// goroutines and hands each exchange to its route.
+type SedaConsumer struct {
+	core.ServiceSupport
+
+	endpoint *SedaEndpoint
+	target   core.Processor
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+func NewSedaConsumer(endpoint *SedaEndpoint, target core.Processor) *SedaConsumer {
+	return &SedaConsumer{
+		endpoint: endpoint,
+		target:   target,
+	}
+}
+
+// Start launches the worker goroutines.
+func (c *SedaConsumer) Start(ctx core.Context) error {
+	return c.DoStart(func() error {
+		c.stopChan = make(chan struct{})
+		for i := 0; i < c.endpoint.ConcurrentConsumers; i++ {
+			c.wg.Add(1)
+			go c.poll(ctx, c.stopChan)
+		}
+		return nil
+	})
+}
+
+// Stop waits for in-flight exchanges to finish. Queued exchanges stay on the
+// queue for the next consumer.
+func (c *SedaConsumer) Stop(ctx core.Context) error {
+	return c.DoStop(func() error {
+		if c.stopChan != nil {
+			close(c.stopChan)
+			c.stopChan = nil
+		}
+		c.wg.Wait()
+		return nil
+	})
+}
+
+func (c *SedaConsumer) poll(ctx core.Context, stop <-chan struct{}) {
+	defer c.wg.Done()
+	for {
+		select {
+		case <-stop:
+			return
+		case exchange := <-c.endpoint.queue:
+			if err := c.target.Process(ctx, exchange); err != nil {
+				core.LogExchangeFailure(ctx, logCategory, exchange, err, "error processing exchange")
+			}
+		}
+	}
+}
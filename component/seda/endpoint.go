@@ -0,0 +1,39 @@
+package seda
+
+import "github.com/sonyjop/camelgo/core"
+
+type SedaEndpoint struct {
+	core.EndpointConfig
+	Name                string
+	ConcurrentConsumers int
+
+	queue chan *core.Exchange
+}
+
+func NewSedaEndpoint(epCfg core.EndpointConfig, name string, size, concurrentConsumers int) *SedaEndpoint {
+	return &SedaEndpoint{
+		EndpointConfig:      epCfg,
+		Name:                name,
+		ConcurrentConsumers: concurrentConsumers,
+		queue:               make(chan *core.Exchange, size),
+	}
+}
+func (e *SedaEndpoint) CreateProducer() (core.Producer, error) {
+	return NewSedaProducer(e), nil
+}
+func (e *SedaEndpoint) CreateConsumer(target core.Processor) (core.Consumer, error) {
+	return NewSedaConsumer(e, target), nil
+}
+func (e *SedaEndpoint) GetURI() string {
+	return e.RawURI
+}
+
+// QueueDepth returns the number of exchanges waiting to be consumed.
+func (e *SedaEndpoint) QueueDepth() int {
+	return len(e.queue)
+}
+
+// QueueCapacity returns the maximum number of waiting exchanges.
+func (e *SedaEndpoint) QueueCapacity() int {
+	return cap(e.queue)
+}
@@ -0,0 +1,43 @@
+package seda
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// SedaProducer puts a copy of each exchange on the endpoint's queue and
+// returns without waiting for it to be consumed.
+type SedaProducer struct {
+	core.ServiceSupport
+
+	endpoint *SedaEndpoint
+}
+
+func NewSedaProducer(endpoint *SedaEndpoint) *SedaProducer {
+	return &SedaProducer{
+		endpoint: endpoint,
+	}
+}
+
+func (p *SedaProducer) Start(ctx core.Context) error {
+	return p.DoStart(nil)
+}
+
+func (p *SedaProducer) Stop(ctx core.Context) error {
+	return p.DoStop(nil)
+}
+
+// Process enqueues a copy of the exchange. It fails instead of blocking when
+// the queue is full. The copy starts a new unit of work in the consuming
+// route, so it is not tied to the sending route.
+func (p *SedaProducer) Process(ctx core.Context, exchange *core.Exchange) error {
+	copied := exchange.Clone()
+	copied.SetFromRouteID("")
+	select {
+	case p.endpoint.queue <- &copied:
+		return nil
+	default:
+		return fmt.Errorf("seda:%s queue is full (capacity %d)", p.endpoint.Name, p.endpoint.QueueCapacity())
+	}
+}
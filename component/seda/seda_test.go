@@ -0,0 +1,95 @@
+package seda
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+type recordingProcessor struct {
+	mu     sync.Mutex
+	bodies []interface{}
+	got    chan struct{}
+}
+
+func newRecordingProcessor() *recordingProcessor {
+	return &recordingProcessor{got: make(chan struct{}, 16)}
+}
+
+func (r *recordingProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	r.mu.Lock()
+	r.bodies = append(r.bodies, exchange.In().Body())
+	r.mu.Unlock()
+	r.got <- struct{}{}
+	return nil
+}
+
+func newEndpoint(t *testing.T, comp *SedaComponent, uri string, params map[string]interface{}) *SedaEndpoint {
+	t.Helper()
+	ep, err := comp.CreateEndpoint(core.EndpointConfig{RawURI: uri, Scheme: "seda", Params: params})
+	if err != nil {
+		t.Fatalf("CreateEndpoint error: %v", err)
+	}
+	return ep.(*SedaEndpoint)
+}
+
+func TestSeda_QueuesUntilConsumed(t *testing.T) {
+	comp := NewSedaComponent()
+	ep := newEndpoint(t, comp, "seda:orders?size=2", map[string]interface{}{"path": "orders", "size": "2"})
+	if other := newEndpoint(t, comp, "seda:orders", map[string]interface{}{"path": "orders"}); other != ep {
+		t.Fatalf("expected endpoints with the same name to be shared")
+	}
+
+	prod, _ := ep.CreateProducer()
+	for _, body := range []string{"a", "b"} {
+		ex := core.NewExchange()
+		ex.In().SetBody(body)
+		if err := prod.Process(nil, ex); err != nil {
+			t.Fatalf("process error: %v", err)
+		}
+	}
+	if ep.QueueDepth() != 2 {
+		t.Errorf("expected queue depth 2, got %d", ep.QueueDepth())
+	}
+	if err := prod.Process(nil, core.NewExchange()); err == nil {
+		t.Errorf("expected error when the queue is full")
+	}
+
+	target := newRecordingProcessor()
+	cons, _ := ep.CreateConsumer(target)
+	if err := cons.Start(nil); err != nil {
+		t.Fatalf("consumer start error: %v", err)
+	}
+	defer cons.Stop(nil)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-target.got:
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for exchange %d", i)
+		}
+	}
+	if ep.QueueDepth() != 0 {
+		t.Errorf("expected empty queue, got %d", ep.QueueDepth())
+	}
+}
+
+func TestSeda_InvalidOptions(t *testing.T) {
+	comp := NewSedaComponent()
+	if _, err := comp.CreateEndpoint(core.EndpointConfig{RawURI: "seda:", Params: map[string]interface{}{}}); err == nil {
+		t.Errorf("expected error for a seda endpoint without a name")
+	}
+	if _, err := comp.CreateEndpoint(core.EndpointConfig{RawURI: "seda:a?size=0", Params: map[string]interface{}{"path": "a", "size": "0"}}); err == nil {
+		t.Errorf("expected error for a non-positive size")
+	}
+
+	ctx := core.NewContext()
+	ctx.RegisterComponent("seda", comp)
+	_, err := ctx.GetEndpoint("seda:b?sise=5&concurrentConsumers=x")
+	if err == nil || !strings.Contains(err.Error(), "did you mean size?") || !strings.Contains(err.Error(), "concurrentConsumers: must be an integer") {
+		t.Errorf("expected all option errors from GetEndpoint, got %v", err)
+	}
+}
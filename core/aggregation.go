@@ -0,0 +1,36 @@
+package core
+
+// AggregationStrategy combines exchanges into one, such as the replies of
+// the recipients of a recipient list. oldExchange is nil for the first
+// exchange; the result is passed as oldExchange with the next one.
+type AggregationStrategy interface {
+	Aggregate(oldExchange, newExchange *Exchange) (*Exchange, error)
+}
+
+// AggregationStrategyFunc adapts a function to an AggregationStrategy.
+type AggregationStrategyFunc func(oldExchange, newExchange *Exchange) (*Exchange, error)
+
+func (f AggregationStrategyFunc) Aggregate(oldExchange, newExchange *Exchange) (*Exchange, error) {
+	return f(oldExchange, newExchange)
+}
+
+// UseLatestStrategy keeps the latest exchange.
+type UseLatestStrategy struct{}
+
+func (UseLatestStrategy) Aggregate(oldExchange, newExchange *Exchange) (*Exchange, error) {
+	return newExchange, nil
+}
+
+// GroupedBodyStrategy collects the bodies of the exchanges, in order, into
+// the []interface{} body of the first.
+type GroupedBodyStrategy struct{}
+
+func (GroupedBodyStrategy) Aggregate(oldExchange, newExchange *Exchange) (*Exchange, error) {
+	if oldExchange == nil {
+		newExchange.In().SetBody([]interface{}{newExchange.In().Body()})
+		return newExchange, nil
+	}
+	bodies, _ := oldExchange.In().Body().([]interface{})
+	oldExchange.In().SetBody(append(bodies, newExchange.In().Body()))
+	return oldExchange, nil
+}
@@ -0,0 +1,99 @@
+package core
+
+import "sort"
+
+// OptionKind says where an endpoint option goes in the URI.
+type OptionKind string
+
+const (
+	PathOption  OptionKind = "path"      // the part after the scheme, e.g. "orders" in seda:orders
+	QueryOption OptionKind = "parameter" // a query parameter, e.g. size in seda:orders?size=10
+)
+
+// OptionType is the type an option value must parse as.
+type OptionType string
+
+const (
+	StringOption   OptionType = "string"
+	IntOption      OptionType = "int"
+	BoolOption     OptionType = "bool"
+	DurationOption OptionType = "duration"
+	EnumOption     OptionType = "enum"
+	ListOption     OptionType = "list"
+	BeanOption     OptionType = "bean" // a #name reference into the registry
+)
+
+// OptionDescriptor documents one endpoint option.
+type OptionDescriptor struct {
+	Name        string     `json:"name"`
+	Kind        OptionKind `json:"kind"`
+	Type        OptionType `json:"type"`
+	Default     string     `json:"default,omitempty"`
+	Enum        []string   `json:"enum,omitempty"`
+	Required    bool       `json:"required,omitempty"`
+	Secret      bool       `json:"secret,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+// ComponentDescriptor documents a component and its endpoint options.
+type ComponentDescriptor struct {
+	Scheme      string             `json:"scheme"`
+	Syntax      string             `json:"syntax,omitempty"` // e.g. "seda:name"
+	Description string             `json:"description,omitempty"`
+	Consumer    bool               `json:"consumer"`
+	Producer    bool               `json:"producer"`
+	Options     []OptionDescriptor `json:"options"`
+
+	// Described is false for components that do not implement
+	// DescribedComponent; only Scheme is known for them.
+	Described bool `json:"described"`
+}
+
+// Option returns the option called name.
+func (d ComponentDescriptor) Option(name string) (OptionDescriptor, bool) {
+	for _, o := range d.Options {
+		if o.Name == name {
+			return o, true
+		}
+	}
+	return OptionDescriptor{}, false
+}
+
+// DescribedComponent is implemented by components that document their
+// endpoint options for the catalog.
+type DescribedComponent interface {
+	Component
+	Descriptor() ComponentDescriptor
+}
+
+// DescribeComponent returns the descriptor of component registered under
+// scheme.
+func DescribeComponent(scheme string, component Component) ComponentDescriptor {
+	if d, ok := component.(DescribedComponent); ok {
+		desc := d.Descriptor()
+		desc.Scheme = scheme
+		desc.Described = true
+		return desc
+	}
+	return ComponentDescriptor{Scheme: scheme, Options: []OptionDescriptor{}}
+}
+
+// Catalog describes the registered components, ordered by scheme.
+func (c *DefaultContext) Catalog() []ComponentDescriptor {
+	components := c.Components()
+	out := make([]ComponentDescriptor, 0, len(components))
+	for scheme, comp := range components {
+		out = append(out, DescribeComponent(scheme, comp))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Scheme < out[j].Scheme })
+	return out
+}
+
+// ComponentDescriptor describes the component registered under scheme.
+func (c *DefaultContext) ComponentDescriptor(scheme string) (ComponentDescriptor, bool) {
+	comp, err := c.GetComponent(scheme)
+	if err != nil {
+		return ComponentDescriptor{}, false
+	}
+	return DescribeComponent(scheme, comp), true
+}
@@ -0,0 +1,51 @@
+package core
+
+import "testing"
+
+// describedComponent documents its endpoint options.
+type describedComponent struct {
+	recordingComponent
+}
+
+func (c *describedComponent) Descriptor() ComponentDescriptor {
+	return ComponentDescriptor{
+		Scheme:   "ignored",
+		Syntax:   "rec:name",
+		Producer: true,
+		Options: []OptionDescriptor{
+			{Name: "path", Kind: PathOption, Type: StringOption, Required: true},
+			{Name: "password", Kind: QueryOption, Type: StringOption, Secret: true},
+		},
+	}
+}
+
+func TestContext_Catalog(t *testing.T) {
+	ctx := NewContext()
+	ctx.RegisterComponent("zz", &recordingComponent{rec: &recorder{}})
+	ctx.RegisterComponent("rec", &describedComponent{recordingComponent{rec: &recorder{}}})
+
+	catalog := ctx.Catalog()
+	if len(catalog) != 2 || catalog[0].Scheme != "rec" || catalog[1].Scheme != "zz" {
+		t.Fatalf("expected the catalog ordered by scheme, got %+v", catalog)
+	}
+	if !catalog[0].Described || catalog[1].Described {
+		t.Errorf("expected only rec to be described, got %+v", catalog)
+	}
+	if catalog[1].Options == nil {
+		t.Error("expected undescribed components to have an empty option list")
+	}
+
+	desc, ok := ctx.ComponentDescriptor("rec")
+	if !ok || desc.Scheme != "rec" {
+		t.Fatalf("expected the registered scheme to win, got %+v", desc)
+	}
+	if o, ok := desc.Option("password"); !ok || !o.Secret {
+		t.Errorf("expected password to be secret, got %+v", o)
+	}
+	if _, ok := desc.Option("missing"); ok {
+		t.Error("expected no missing option")
+	}
+	if _, ok := ctx.ComponentDescriptor("nope"); ok {
+		t.Error("expected no descriptor for an unknown scheme")
+	}
+}
@@ -18,11 +18,17 @@ type Endpoint interface {
 
 type Producer interface {
 	Processor
-	Start(ctx Context) error
-	Stop(ctx Context) error
+	Service
 }
 
 type Consumer interface {
-	Start(ctx Context) error
-	Stop(ctx Context) error
+	Service
+}
+
+// QueueEndpoint is implemented by endpoints that buffer exchanges between
+// producers and consumers, such as seda.
+type QueueEndpoint interface {
+	Endpoint
+	QueueDepth() int
+	QueueCapacity() int
 }
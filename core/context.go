@@ -3,9 +3,11 @@ package core
 import (
 	"errors"
 	"fmt"
-	"net/url"
-	"strings"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
 // Context is the interface that components and processors interact with.
@@ -13,6 +15,8 @@ type Context interface {
 	// Lifecycle
 	Start() error
 	Stop() error
+	Status() ServiceStatus
+	AddService(svc Service) error
 
 	// Route Management
 	SetLoader(loader RouteLoader)
@@ -22,17 +26,64 @@ type Context interface {
 	RegisterComponent(scheme string, component Component)
 	GetComponent(scheme string) (Component, error)
 	GetEndpoint(uri string) (Endpoint, error)
+	CreateProducer(uri string) (Producer, error)
+
+	// Interception
+	AddInterceptStrategy(s InterceptStrategy)
+	AddProducerInterceptor(p ProducerInterceptor)
 
 	// Runtime
 	NewExchange() *Exchange
+	Registry() Registry
+	NewProducerCache(capacity int) (*ProducerCache, error)
+
+	// Events
+	AddEventNotifier(n EventNotifier)
+	IsEventEnabled(t EventType) bool
+	NotifyEvent(event Event)
+
+	// Logging
+	Logger(category string) *logrus.Entry
 }
 type DefaultContext struct {
+	ServiceSupport
+
 	components map[string]Component
 	endpoints  map[string]Endpoint
 	mu         sync.RWMutex
 	loader     RouteLoader
 	routes     []*Route
-	started    bool
+
+	routeCounter int
+	events       eventSupport
+	logs         logSupport
+
+	healthOnce sync.Once
+	health     *HealthCheckRegistry
+
+	inflight       InflightRepository
+	failures       *FailureHistory
+	messageHistory bool
+	properties     PlaceholderResolver
+	registry       Registry
+	typeConverter  *TypeConverter
+
+	interceptStrategies  []InterceptStrategy
+	producerInterceptors []ProducerInterceptor
+
+	routeController RouteController
+
+	// Managed services, started in this order and stopped in reverse:
+	// user services (by Order), endpoints, producers, then routes.
+	services        []Service
+	endpointSvcs    []Service
+	producers       []Service
+	servicesStarted bool
+	startedAt       time.Time
+
+	initHooks  []LifecycleHook
+	startHooks []LifecycleHook
+	stopHooks  []LifecycleHook
 }
 
 func NewContext() *DefaultContext {
@@ -40,59 +91,269 @@ func NewContext() *DefaultContext {
 		components: make(map[string]Component),
 		endpoints:  make(map[string]Endpoint),
 		routes:     []*Route{},
+		failures:   NewFailureHistory(DefaultFailureHistorySize),
 	}
 }
-func (c *DefaultContext) Start() error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
 
-	if c.started {
-		return nil // idempotent
+// Init runs the OnInit hooks once. Start calls it implicitly.
+func (c *DefaultContext) Init() error {
+	return c.DoInit(func() error {
+		c.mu.Lock()
+		// ensure maps are initialized
+		if c.components == nil {
+			c.components = make(map[string]Component)
+		}
+		if c.endpoints == nil {
+			c.endpoints = make(map[string]Endpoint)
+		}
+		hooks := append([]LifecycleHook(nil), c.initHooks...)
+		c.mu.Unlock()
+
+		return c.runHooks(hooks)
+	})
+}
+
+// Start brings the context up: registered services in Order, then endpoints
+// and producers, then routes, and finally the OnStart hooks.
+func (c *DefaultContext) Start() error {
+	if err := c.Init(); err != nil {
+		return err
 	}
+	err := c.DoStart(func() error {
+		if c.IsEventEnabled(ContextStartingEvent) {
+			c.NotifyEvent(NewContextEvent(ContextStartingEvent, c))
+		}
+		for _, svc := range c.managedServices() {
+			if err := StartService(c, svc); err != nil {
+				return fmt.Errorf("failed to start service %T: %w", svc, err)
+			}
+		}
+		c.mu.Lock()
+		c.servicesStarted = true
+		c.startedAt = time.Now()
+		routes := append([]*Route(nil), c.routes...)
+		rc := c.routeController
+		c.mu.Unlock()
+
+		// Let a configured route controller decide how routes are started.
+		if rc != nil {
+			if err := rc.StartRoutes(c, routes); err != nil {
+				return err
+			}
+		} else {
+			// Start all routes sequentially. If any route fails to start, return error.
+			for _, r := range routes {
+				if r == nil {
+					continue
+				}
+				if err := r.Start(c); err != nil {
+					return fmt.Errorf("failed to start route %s: %w", r.ID, err)
+				}
+			}
+		}
 
-	// ensure maps are initialized
-	if c.components == nil {
-		c.components = make(map[string]Component)
+		c.mu.RLock()
+		hooks := append([]LifecycleHook(nil), c.startHooks...)
+		c.mu.RUnlock()
+		return c.runHooks(hooks)
+	})
+	if err == nil && c.IsEventEnabled(ContextStartedEvent) {
+		c.NotifyEvent(NewContextEvent(ContextStartedEvent, c))
 	}
-	if c.endpoints == nil {
-		c.endpoints = make(map[string]Endpoint)
+	return err
+}
+
+// Stop runs the OnStop hooks, then shuts down routes, producers, endpoints
+// and services in the reverse of their start order. Every part is stopped
+// even if an earlier one fails; the first error is returned.
+func (c *DefaultContext) Stop() error {
+	switch c.Status() {
+	case Initializing, Initialized, Stopped:
+		return nil // never started or already stopped
 	}
 
-	// Start all routes sequentially. If any route fails to start, return error.
-	for _, r := range c.routes {
-		if r == nil {
-			continue
+	err := c.DoStop(func() error {
+		if c.IsEventEnabled(ContextStoppingEvent) {
+			c.NotifyEvent(NewContextEvent(ContextStoppingEvent, c))
+		}
+
+		c.mu.RLock()
+		hooks := append([]LifecycleHook(nil), c.stopHooks...)
+		routes := append([]*Route(nil), c.routes...)
+		rc := c.routeController
+		c.mu.RUnlock()
+
+		firstErr := c.runHooks(hooks)
+
+		if rc != nil {
+			if err := rc.StopRoutes(c, routes); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		} else {
+			// Stop routes in reverse order to mirror typical shutdown semantics
+			for i := len(routes) - 1; i >= 0; i-- {
+				r := routes[i]
+				if r == nil {
+					continue
+				}
+				if err := r.Stop(c); err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("failed to stop route %s: %w", r.ID, err)
+				}
+			}
 		}
-		if err := r.Start(c); err != nil {
-			return fmt.Errorf("failed to start route %s: %w", r.ID, err)
+
+		c.mu.Lock()
+		c.servicesStarted = false
+		c.startedAt = time.Time{}
+		c.mu.Unlock()
+
+		svcs := c.managedServices()
+		for i := len(svcs) - 1; i >= 0; i-- {
+			if err := StopService(c, svcs[i]); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("failed to stop service %T: %w", svcs[i], err)
+			}
 		}
+		return firstErr
+	})
+	if c.IsEventEnabled(ContextStoppedEvent) {
+		c.NotifyEvent(NewContextEvent(ContextStoppedEvent, c))
 	}
+	return err
+}
 
-	c.started = true
-	return nil
+// Suspend pauses every started route while keeping services running.
+func (c *DefaultContext) Suspend() error {
+	return c.DoSuspend(func() error {
+		routes := c.Routes()
+		for i := len(routes) - 1; i >= 0; i-- {
+			if routes[i].Status() != Started {
+				continue
+			}
+			if err := routes[i].Suspend(c); err != nil {
+				return fmt.Errorf("failed to suspend route %s: %w", routes[i].ID, err)
+			}
+		}
+		return nil
+	})
 }
-func (c *DefaultContext) Stop() error {
+
+// Resume continues the routes paused by Suspend.
+func (c *DefaultContext) Resume() error {
+	return c.DoResume(func() error {
+		for _, r := range c.Routes() {
+			if r.Status() != Suspended {
+				continue
+			}
+			if err := r.Resume(c); err != nil {
+				return fmt.Errorf("failed to resume route %s: %w", r.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// OnInit registers a hook run once, before the context starts for the first time.
+func (c *DefaultContext) OnInit(h LifecycleHook) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.initHooks = append(c.initHooks, h)
+}
 
-	if !c.started {
-		return nil // already stopped
-	}
+// OnStart registers a hook run after all services and routes have started.
+func (c *DefaultContext) OnStart(h LifecycleHook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.startHooks = append(c.startHooks, h)
+}
 
-	// Stop routes in reverse order to mirror typical shutdown semantics
-	var firstErr error
-	for i := len(c.routes) - 1; i >= 0; i-- {
-		r := c.routes[i]
-		if r == nil {
-			continue
+// OnStop registers a hook run before anything is stopped.
+func (c *DefaultContext) OnStop(h LifecycleHook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopHooks = append(c.stopHooks, h)
+}
+
+func (c *DefaultContext) runHooks(hooks []LifecycleHook) error {
+	for _, h := range hooks {
+		if err := h(c); err != nil {
+			return err
 		}
-		if err := r.Stop(c); err != nil && firstErr == nil {
-			firstErr = fmt.Errorf("failed to stop route %s: %w", r.ID, err)
+	}
+	return nil
+}
+
+// AddService registers svc for lifecycle management. Producers are grouped
+// with the other producers; anything else is started before endpoints, in
+// Order. A service added to a running context is started immediately.
+func (c *DefaultContext) AddService(svc Service) error {
+	if svc == nil {
+		return nil
+	}
+	c.mu.Lock()
+	if _, ok := svc.(Producer); ok {
+		c.producers = append(c.producers, svc)
+	} else {
+		c.services = append(c.services, svc)
+	}
+	running := c.servicesStarted
+	c.mu.Unlock()
+
+	if running {
+		return StartService(c, svc)
+	}
+	return nil
+}
+
+// Services returns the user-registered services in start order.
+func (c *DefaultContext) Services() []Service {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return sortedServices(c.services)
+}
+
+// Routes returns the routes registered with the context.
+func (c *DefaultContext) Routes() []*Route {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]*Route, 0, len(c.routes))
+	for _, r := range c.routes {
+		if r != nil {
+			out = append(out, r)
 		}
 	}
+	return out
+}
+
+// managedServices returns every non-route service in start order.
+func (c *DefaultContext) managedServices() []Service {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := sortedServices(c.services)
+	out = append(out, c.endpointSvcs...)
+	return append(out, c.producers...)
+}
+
+func sortedServices(svcs []Service) []Service {
+	out := append([]Service(nil), svcs...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return serviceOrder(out[i]) < serviceOrder(out[j])
+	})
+	return out
+}
+
+// SetRouteController replaces the default start-all-or-fail behaviour, e.g.
+// with a SupervisingRouteController. It must be called before Start.
+func (c *DefaultContext) SetRouteController(rc RouteController) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.routeController = rc
+}
 
-	c.started = false
-	return firstErr
+// RouteController returns the configured route controller, or nil.
+func (c *DefaultContext) RouteController() RouteController {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.routeController
 }
 
 // SetLoader allows the user to decide how they want to load routes (DSL, YAML, etc.)
@@ -108,46 +369,122 @@ func (c *DefaultContext) AddRoutes(source interface{}) error {
 		return fmt.Errorf("loading failed: %w", err)
 	}
 
+	// Context-wide definitions such as interceptors apply to the routes below.
+	if src, ok := source.(ContextDefinitionSource); ok {
+		for _, cd := range src.GetContextDefinitions() {
+			if err := cd.Install(c); err != nil {
+				return fmt.Errorf("failed to install %T: %w", cd, err)
+			}
+		}
+	}
+
 	for _, def := range definitions {
-		// 2. Resolve the Input Endpoint (The "From" part)
-		inputEndpoint, err := c.GetEndpoint(def.InputURI)
+		runtimeRoute, err := c.compileRoute(def)
 		if err != nil {
 			return err
 		}
 
-		// 3. Compile the steps into a chain of Processors
-		var pipelineSteps []Processor
-		for _, stepDef := range def.Steps {
-			// Each definition (To, Choice, etc.) knows how to compile itself
-			proc, err := stepDef.Compile(c)
-			if err != nil {
+		c.mu.Lock()
+		c.routes = append(c.routes, runtimeRoute)
+		running := c.servicesStarted
+		rc := c.routeController
+		c.mu.Unlock()
+
+		if c.IsEventEnabled(RouteAddedEvent) {
+			c.NotifyEvent(NewRouteEvent(RouteAddedEvent, runtimeRoute))
+		}
+
+		// Routes added to a running context start right away, through the
+		// route controller if there is one.
+		if running && rc != nil {
+			if err := rc.StartRoute(c, runtimeRoute); err != nil {
 				return err
 			}
-			pipelineSteps = append(pipelineSteps, proc)
+		} else if running {
+			if err := runtimeRoute.Start(c); err != nil {
+				return fmt.Errorf("failed to start route %s: %w", runtimeRoute.ID, err)
+			}
 		}
+	}
+	return nil
+}
 
-		// 4. Wrap steps in a PipelineProcessor
-		pipeline := &PipelineProcessor{Children: pipelineSteps}
-
-		// 5. Create the Consumer (The entry point of the route)
-		// We pass the pipeline to the consumer so it knows where to send data.
-		consumer, err := inputEndpoint.CreateConsumer(pipeline)
-		if err != nil {
-			return err
+// RemoveRoute stops the route with the given ID and removes it from the context.
+func (c *DefaultContext) RemoveRoute(id string) error {
+	c.mu.Lock()
+	var route *Route
+	for i, r := range c.routes {
+		if r != nil && r.ID == id {
+			route = r
+			c.routes = append(c.routes[:i:i], c.routes[i+1:]...)
+			break
 		}
+	}
+	c.mu.Unlock()
 
-		// 6. Finalize the Runtime Route
-		runtimeRoute := &Route{
-			ID:       def.ID,
-			InputURI: def.InputURI,
-			Consumer: consumer,
-			Pipeline: pipeline,
-			context:  c,
+	if route == nil {
+		return fmt.Errorf("route not found: %s", id)
+	}
+	if err := route.Stop(c); err != nil {
+		return fmt.Errorf("failed to stop route %s: %w", id, err)
+	}
+	if c.IsEventEnabled(RouteRemovedEvent) {
+		c.NotifyEvent(NewRouteEvent(RouteRemovedEvent, route))
+	}
+	return nil
+}
+
+// Endpoints returns the resolved endpoints, each once, ordered by URI.
+// Endpoints shared between several URIs are listed under the first of them.
+func (c *DefaultContext) Endpoints() []Endpoint {
+	c.mu.RLock()
+	uris := make([]string, 0, len(c.endpoints))
+	for uri := range c.endpoints {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	seen := make(map[Endpoint]bool, len(uris))
+	out := make([]Endpoint, 0, len(uris))
+	for _, uri := range uris {
+		ep := c.endpoints[uri]
+		if !seen[ep] {
+			seen[ep] = true
+			out = append(out, ep)
 		}
+	}
+	c.mu.RUnlock()
+	return out
+}
 
-		c.routes = append(c.routes, runtimeRoute)
+// Uptime returns how long the context has been started, or zero when it is
+// not running.
+func (c *DefaultContext) Uptime() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.startedAt.IsZero() {
+		return 0
+	}
+	return time.Since(c.startedAt)
+}
 
-		return nil
+// Inflight returns the exchanges currently being routed.
+func (c *DefaultContext) Inflight() *InflightRepository {
+	return &c.inflight
+}
+
+// FailureHistory returns the most recent failed exchanges.
+func (c *DefaultContext) FailureHistory() *FailureHistory {
+	return c.failures
+}
+
+// Route returns the route with the given ID, or nil.
+func (c *DefaultContext) Route(id string) *Route {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, r := range c.routes {
+		if r != nil && r.ID == id {
+			return r
+		}
 	}
 	return nil
 }
@@ -158,6 +495,17 @@ func (c *DefaultContext) RegisterComponent(scheme string, component Component) {
 	c.components[scheme] = component
 }
 
+// Components returns the registered components keyed by scheme.
+func (c *DefaultContext) Components() map[string]Component {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make(map[string]Component, len(c.components))
+	for scheme, component := range c.components {
+		out[scheme] = component
+	}
+	return out
+}
+
 func (c *DefaultContext) GetComponent(scheme string) (Component, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -169,7 +517,20 @@ func (c *DefaultContext) GetComponent(scheme string) (Component, error) {
 }
 
 // GetEndpoint resolves a URI into a concrete Endpoint instance.
+// Property placeholders are resolved first and the URI is normalized, so
+// endpoints are cached by the resolved URI and URIs differing only in
+// parameter order or encoding share an endpoint.
 func (c *DefaultContext) GetEndpoint(uri string) (Endpoint, error) {
+	resolved, err := c.ResolvePlaceholders(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve endpoint [%s]: %w", uri, err)
+	}
+	parsed, err := ParseURI(resolved)
+	if err != nil {
+		return nil, err
+	}
+	uri = parsed.String()
+
 	// 1. Thread-safe Cache Lookup
 	// We check if this exact URI has been resolved before to ensure we
 	// reuse the same Endpoint object (important for resource management).
@@ -180,17 +541,19 @@ func (c *DefaultContext) GetEndpoint(uri string) (Endpoint, error) {
 	}
 	c.mu.RUnlock()
 
-	// 2. Parse the URI to find the Scheme and Path
+	// 2. Turn the path and query parameters into options, resolving
+	// #bean references.
 	// Example: "kafka:my-topic?broker=localhost:9092" -> scheme is "kafka", path is "my-topic"
-	scheme, options, err := c.parseUriPathAndOptions(uri)
+	scheme := parsed.Scheme
+	options, err := endpointParams(parsed, c.Registry())
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to resolve endpoint [%s]: %w", c.MaskURI(uri), err)
 	}
 
 	// 3. Lookup the Component in the Registry
 	component, err := c.GetComponent(scheme)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve endpoint [%s]: %w", uri, err)
+		return nil, fmt.Errorf("failed to resolve endpoint [%s]: %w", c.MaskURI(uri), err)
 	}
 
 	// 4. Delegate Creation to the Component
@@ -207,55 +570,155 @@ func (c *DefaultContext) GetEndpoint(uri string) (Endpoint, error) {
 	}
 
 	// 5. Store in Cache and Return
+	// Endpoints with their own lifecycle are managed like other services.
 	c.mu.Lock()
+	if existing, ok := c.endpoints[uri]; ok {
+		c.mu.Unlock()
+		return existing, nil
+	}
 	c.endpoints[uri] = ep
+	svc, managed := ep.(Service)
+	if managed {
+		c.endpointSvcs = append(c.endpointSvcs, svc)
+	}
+	running := c.servicesStarted
 	c.mu.Unlock()
 
+	if managed && running {
+		if err := StartService(c, svc); err != nil {
+			return nil, fmt.Errorf("failed to start endpoint [%s]: %w", c.MaskURI(uri), err)
+		}
+	}
 	return ep, nil
 }
 
-// parseUriPathAndOptions handles the logic of extracting scheme, path, and query params.
-// It returns scheme, a map of options (including "path"), and any error.
-// The "path" key in options contains the resource identifier (e.g., topic name, file path).
-// Example: "file:inbox/file.txt?mode=read" -> scheme="file", options={"path": "inbox/file.txt", "mode": "read"}
-func (c *DefaultContext) parseUriPathAndOptions(rawUri string) (string, map[string]interface{}, error) {
-	// 1. Extract Scheme (e.g., file:, kafka:)
-	parts := strings.SplitN(rawUri, ":", 2)
-	if len(parts) < 2 {
-		return "", nil, fmt.Errorf("invalid URI: %s", rawUri)
+// CreateProducer resolves uri and creates a producer that the context starts
+// and stops together with the other managed services. Producers created
+// while a route is compiled belong to that route instead.
+func (c *DefaultContext) CreateProducer(uri string) (Producer, error) {
+	ep, err := c.GetEndpoint(uri)
+	if err != nil {
+		return nil, err
 	}
-	scheme := parts[0]
-
-	// 2. Use net/url to parse the "path?query" portion
-	u, err := url.Parse(parts[1])
+	prod, err := ep.CreateProducer()
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to parse URI details: %w", err)
+		return nil, fmt.Errorf("endpoint [%s] could not create producer: %w", c.MaskURI(uri), err)
+	}
+	if err := c.AddService(prod); err != nil {
+		return nil, err
 	}
+	// Interceptors only decorate; the context manages the real producer.
+	return c.applyProducerInterceptors(ep, prod)
+}
 
-	options := make(map[string]interface{})
+// compileRoute turns a RouteDefinition into a runtime Route. def is left
+// untouched: validation and hot reload compile the same definitions again.
+func (c *DefaultContext) compileRoute(def *RouteDefinition) (*Route, error) {
+	id := def.ID
+	if id == "" {
+		c.mu.Lock()
+		c.routeCounter++
+		id = fmt.Sprintf("route%d", c.routeCounter)
+		c.mu.Unlock()
+	}
+	resolved, err := c.ResolvePlaceholders(id)
+	if err != nil {
+		return nil, fmt.Errorf("route %s: %w", id, err)
+	}
+	id = resolved
+	if c.Route(id) != nil {
+		return nil, fmt.Errorf("duplicate route ID: %s", id)
+	}
+	inputURI, err := c.ResolvePlaceholders(def.InputURI)
+	if err != nil {
+		return nil, fmt.Errorf("route %s: %w", id, err)
+	}
 
-	// 3. Extract path (opaque or path component)
-	if u.Opaque != "" {
-		options["path"] = u.Opaque
-	} else if u.Path != "" {
-		options["path"] = u.Path
+	// 1. Resolve the Input Endpoint (The "From" part)
+	inputEndpoint, err := c.GetEndpoint(inputURI)
+	if err != nil {
+		return nil, err
 	}
 
-	// 4. Convert url.Values (map[string][]string) to map[string]interface{}
-	queryParams := u.Query()
-	for k, v := range queryParams {
-		if len(v) > 0 {
-			options[k] = v[0] // Take the first value for simplicity
+	// 2. Compile the steps into a chain of Processors
+	// Each definition (To, Choice, etc.) knows how to compile itself; the
+	// route compiler gives every step an ID.
+	stats := &RouteStats{}
+	compiler := newRouteCompiler(c, id, stats)
+	compiled := false
+	defer func() {
+		if !compiled {
+			compiler.discard()
 		}
+	}()
+	pipeline, err := CompileSteps(compiler, def.Steps)
+	if err != nil {
+		return nil, fmt.Errorf("route %s: %w", id, err)
+	}
+	wrapped, err := c.applyInterceptStrategies(StepInfo{RouteID: id, Kind: RouteStepKind, URI: inputURI}, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("route %s: %w", id, err)
+	}
+	entry := &RouteProcessor{
+		RouteID:     id,
+		EndpointURI: c.MaskURI(inputURI),
+		Pipeline:    wrapped,
+		Stats:       stats,
+		Inflight:    &c.inflight,
+		Failures:    c.failures,
 	}
 
-	return scheme, options, nil
-}
+	// 3. Create the Consumer (The entry point of the route)
+	// We pass the pipeline to the consumer so it knows where to send data.
+	consumer, err := inputEndpoint.CreateConsumer(entry)
+	if err != nil {
+		return nil, err
+	}
 
-func (c *DefaultContext) compileRoute(def *RouteDefinition) (*Route, error) {
-	// Logic to call def.Compile() and wire the Consumer
-	return &Route{}, nil
+	// 4. Finalize the Runtime Route
+	compiled = true
+	return &Route{
+		ID:         id,
+		InputURI:   inputURI,
+		Consumer:   consumer,
+		Pipeline:   entry,
+		Definition: def,
+		context:    c,
+		stats:      stats,
+		stepIDs:    compiler.stepIDs,
+		stepStats:  compiler.stats,
+		producers:  compiler.producers,
+		caches:     compiler.caches,
+	}, nil
 }
+
+// NewExchange creates an exchange with a unique ID.
 func (c *DefaultContext) NewExchange() *Exchange {
-	return &Exchange{}
+	ex := NewExchange()
+	ex.id = NewExchangeID()
+	if c.IsEventEnabled(ExchangeCreatedEvent) {
+		c.NotifyEvent(NewExchangeEvent(ExchangeCreatedEvent, ex, nil))
+	}
+	return ex
+}
+
+// AddEventNotifier registers n to receive engine events.
+func (c *DefaultContext) AddEventNotifier(n EventNotifier) {
+	c.events.add(n)
+}
+
+// RemoveEventNotifier unregisters n.
+func (c *DefaultContext) RemoveEventNotifier(n EventNotifier) {
+	c.events.remove(n)
+}
+
+// IsEventEnabled reports whether any registered notifier may want events of
+// type t. Emitters check it before building an event.
+func (c *DefaultContext) IsEventEnabled(t EventType) bool {
+	return c.events.isEnabled(t)
+}
+
+// NotifyEvent delivers event to the interested notifiers.
+func (c *DefaultContext) NotifyEvent(event Event) {
+	c.events.notify(event)
 }
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TypeConverter converts message bodies and other values between types.
+// Built in are strings and []byte to and from numbers and bools, numbers
+// between number types, and dereferencing pointers; further conversions
+// are added with AddConverter. It is safe for concurrent use.
+type TypeConverter struct {
+	mu         sync.RWMutex
+	converters map[[2]reflect.Type]func(interface{}) (interface{}, error)
+}
+
+func NewTypeConverter() *TypeConverter {
+	return &TypeConverter{converters: make(map[[2]reflect.Type]func(interface{}) (interface{}, error))}
+}
+
+// AddConverter adds a conversion from From to To to c, replacing a built-in
+// or earlier one.
+func AddConverter[From, To any](c *TypeConverter, fn func(From) (To, error)) {
+	key := [2]reflect.Type{typeOf[From](), typeOf[To]()}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.converters[key] = func(v interface{}) (interface{}, error) {
+		return fn(v.(From))
+	}
+}
+
+// Convert converts value to type to. A nil value converts to the zero value.
+func (c *TypeConverter) Convert(value interface{}, to reflect.Type) (interface{}, error) {
+	if value == nil {
+		return reflect.Zero(to).Interface(), nil
+	}
+	rv := reflect.ValueOf(value)
+	from := rv.Type()
+	if from.AssignableTo(to) {
+		return value, nil
+	}
+	c.mu.RLock()
+	fn := c.converters[[2]reflect.Type{from, to}]
+	c.mu.RUnlock()
+	if fn != nil {
+		return fn(value)
+	}
+	if from.Kind() == reflect.Pointer && from.Elem().AssignableTo(to) {
+		if rv.IsNil() {
+			return reflect.Zero(to).Interface(), nil
+		}
+		return rv.Elem().Interface(), nil
+	}
+
+	switch to.Kind() {
+	case reflect.String:
+		if s, ok := text(value); ok {
+			return reflect.ValueOf(s).Convert(to).Interface(), nil
+		}
+	case reflect.Slice:
+		if to.Elem().Kind() == reflect.Uint8 && from.Kind() == reflect.String {
+			return rv.Convert(to).Interface(), nil
+		}
+	}
+	if s, ok := value.(string); ok {
+		return parseText(s, to)
+	}
+	if b, ok := value.([]byte); ok {
+		return parseText(string(b), to)
+	}
+	if isNumberKind(from.Kind()) && isNumberKind(to.Kind()) {
+		return convertNumber(rv, to)
+	}
+	return nil, fmt.Errorf("cannot convert %T to %s", value, to)
+}
+
+// convertNumber converts rv to the number type to, failing where the value
+// would change: fractions and out of range values for integer types, and
+// overflows of float32.
+func convertNumber(rv reflect.Value, to reflect.Type) (interface{}, error) {
+	out := rv.Convert(to)
+	switch to.Kind() {
+	case reflect.Float64:
+	case reflect.Float32:
+		if math.IsInf(out.Float(), 0) && !(isFloatKind(rv.Kind()) && math.IsInf(rv.Float(), 0)) {
+			return nil, fmt.Errorf("cannot convert %v to %s: out of range", rv, to)
+		}
+	default:
+		if isFloatKind(rv.Kind()) && rv.Float() != math.Trunc(rv.Float()) {
+			return nil, fmt.Errorf("cannot convert %v to %s: not a whole number", rv, to)
+		}
+		if !out.Convert(rv.Type()).Equal(rv) || isNegative(out) != isNegative(rv) {
+			return nil, fmt.Errorf("cannot convert %v to %s: out of range", rv, to)
+		}
+	}
+	return out.Interface(), nil
+}
+
+func isNegative(v reflect.Value) bool {
+	switch {
+	case isFloatKind(v.Kind()):
+		return v.Float() < 0
+	case v.CanInt():
+		return v.Int() < 0
+	}
+	return false
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// text returns the text form of strings, []byte, numbers, bools, errors and
+// fmt.Stringers.
+func text(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case error:
+		return v.Error(), true
+	case fmt.Stringer:
+		return v.String(), true
+	}
+	switch k := reflect.TypeOf(value).Kind(); {
+	case isNumberKind(k), k == reflect.Bool, k == reflect.String:
+		return fmt.Sprint(value), true
+	}
+	return "", false
+}
+
+// parseText parses s as a number or bool of type to.
+func parseText(s string, to reflect.Type) (interface{}, error) {
+	out := reflect.New(to).Elem()
+	s = strings.TrimSpace(s)
+	var err error
+	switch to.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(s, 10, to.Bits()); err == nil {
+			out.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		if n, err = strconv.ParseUint(s, 10, to.Bits()); err == nil {
+			out.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(s, to.Bits()); err == nil {
+			out.SetFloat(f)
+		}
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			out.SetBool(b)
+		}
+	default:
+		return nil, fmt.Errorf("cannot convert string to %s", to)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %q to %s", s, to)
+	}
+	return out.Interface(), nil
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// defaultTypeConverter serves contexts without a converter of their own.
+var defaultTypeConverter = NewTypeConverter()
+
+// TypeConverter returns the type converter of the context, creating one on
+// first use.
+func (c *DefaultContext) TypeConverter() *TypeConverter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.typeConverter == nil {
+		c.typeConverter = NewTypeConverter()
+	}
+	return c.typeConverter
+}
+
+// Convert converts value to type to with the type converter of ctx, or
+// the built-in conversions when ctx has none.
+func Convert(ctx interface{}, value interface{}, to reflect.Type) (interface{}, error) {
+	tc := defaultTypeConverter
+	if c, ok := ctx.(interface{ TypeConverter() *TypeConverter }); ok && c != nil {
+		tc = c.TypeConverter()
+	}
+	return tc.Convert(value, to)
+}
+
+// ConvertTo converts value to a T, see Convert.
+func ConvertTo[T any](ctx interface{}, value interface{}) (T, error) {
+	var zero T
+	v, err := Convert(ctx, value, typeOf[T]())
+	if err != nil || v == nil {
+		return zero, err
+	}
+	return v.(T), nil
+}
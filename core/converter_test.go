@@ -0,0 +1,80 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypeConverter_BuiltIn(t *testing.T) {
+	n := 5
+	cases := []struct {
+		value interface{}
+		to    reflect.Type
+		want  interface{}
+	}{
+		{"42", typeOf[int](), 42},
+		{[]byte(" 7 "), typeOf[uint8](), uint8(7)},
+		{"2.5", typeOf[float64](), 2.5},
+		{"true", typeOf[bool](), true},
+		{12, typeOf[string](), "12"},
+		{[]byte("hi"), typeOf[string](), "hi"},
+		{errors.New("boom"), typeOf[string](), "boom"},
+		{time.Second, typeOf[string](), "1s"},
+		{"hi", typeOf[[]byte](), []byte("hi")},
+		{int32(3), typeOf[float64](), 3.0},
+		{4.0, typeOf[int](), 4},
+		{int64(255), typeOf[uint8](), uint8(255)},
+		{uint64(7), typeOf[int64](), int64(7)},
+		{-2, typeOf[float32](), float32(-2)},
+		{&n, typeOf[int](), 5},
+		{nil, typeOf[int](), 0},
+		{"x", typeOf[interface{}](), "x"},
+	}
+	tc := NewTypeConverter()
+	for _, c := range cases {
+		got, err := tc.Convert(c.value, c.to)
+		if err != nil || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Convert(%#v, %s) = %#v, %v; want %#v", c.value, c.to, got, err, c.want)
+		}
+	}
+
+	for _, c := range []struct {
+		value interface{}
+		to    reflect.Type
+		msg   string
+	}{
+		{"many", typeOf[int](), `cannot convert "many" to int`},
+		{struct{}{}, typeOf[string](), "cannot convert struct {} to string"},
+		{"x", typeOf[time.Time](), "cannot convert string to time.Time"},
+		{3.9, typeOf[int](), "cannot convert 3.9 to int: not a whole number"},
+		{300, typeOf[uint8](), "cannot convert 300 to uint8: out of range"},
+		{-1, typeOf[uint](), "cannot convert -1 to uint: out of range"},
+		{uint64(math.MaxUint64), typeOf[int64](), "out of range"},
+		{1e300, typeOf[float32](), "out of range"},
+		{math.NaN(), typeOf[int](), "not a whole number"},
+		{1e20, typeOf[int64](), "out of range"},
+	} {
+		if _, err := tc.Convert(c.value, c.to); err == nil || !strings.Contains(err.Error(), c.msg) {
+			t.Errorf("Convert(%#v, %s): expected %q, got %v", c.value, c.to, c.msg, err)
+		}
+	}
+}
+
+func TestTypeConverter_Custom(t *testing.T) {
+	ctx := NewContext()
+	AddConverter(ctx.TypeConverter(), func(s string) (time.Time, error) {
+		return time.Parse(time.DateOnly, s)
+	})
+
+	got, err := ConvertTo[time.Time](ctx, "2024-03-01")
+	if err != nil || got.Month() != time.March {
+		t.Fatalf("expected the custom converter, got %v, %v", got, err)
+	}
+	if _, err := ConvertTo[time.Time](nil, "2024-03-01"); err == nil {
+		t.Error("expected contexts without the converter to fall back to the built-ins")
+	}
+}
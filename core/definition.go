@@ -1,10 +1,23 @@
 package core
 
+import (
+	"fmt"
+	"strings"
+)
+
 // CompileContext is the minimal context needed to compile definitions.
 // It decouples compilation from the full Context API.
 type CompileContext interface {
 	GetEndpoint(uri string) (Endpoint, error)
+	// CreateProducer resolves uri and creates a producer started and stopped
+	// with the route being compiled.
+	CreateProducer(uri string) (Producer, error)
 	NewExchange() *Exchange
+	// Registry holds the beans definitions refer to by name.
+	Registry() Registry
+	// NewProducerCache returns a cache for the producers of URIs computed at
+	// runtime, stopped with the route being compiled.
+	NewProducerCache(capacity int) (*ProducerCache, error)
 }
 
 // Compilable is a metadata node that knows how to turn itself into a Processor.
@@ -17,4 +30,51 @@ type RouteDefinition struct {
 	ID       string
 	InputURI string
 	Steps    []Compilable // The IR tree
+
+	// Location is where the route was declared, if the loader knows.
+	Location SourceLocation
+}
+
+// SourceLocation is where a definition was declared. Loaders reading files
+// fill it in so tooling can point at the offending line.
+type SourceLocation struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// String formats the location as file:line:column, leaving out unknown parts.
+func (l SourceLocation) String() string {
+	s := l.File
+	if l.Line > 0 {
+		s += fmt.Sprintf(":%d", l.Line)
+		if l.Column > 0 {
+			s += fmt.Sprintf(":%d", l.Column)
+		}
+	}
+	return strings.TrimPrefix(s, ":")
+}
+
+// Locatable is implemented by step definitions that can carry a source
+// location.
+type Locatable interface {
+	GetLocation() SourceLocation
+	SetLocation(loc SourceLocation)
+}
+
+// Branch is a named block of nested steps, such as a when clause.
+type Branch struct {
+	Label string
+	Steps []Compilable
+}
+
+// BranchingDefinition is implemented by definitions with nested steps so
+// tooling (route dumps, graphs, validation) can walk the whole tree.
+type BranchingDefinition interface {
+	Branches() []Branch
+}
+
+// EndpointAware is implemented by definitions that send to an endpoint.
+type EndpointAware interface {
+	EndpointURI() string
 }
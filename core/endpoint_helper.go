@@ -0,0 +1,85 @@
+package core
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	patternCacheMu sync.RWMutex
+	patternCache   = make(map[string]*regexp.Regexp)
+)
+
+// MatchPattern reports whether value matches pattern.
+// Patterns are tried in order as:
+//   - an exact match ("file:in.txt")
+//   - a wildcard match, where '*' matches any run of characters ("file:*", "route-?-*")
+//   - a regular expression anchored at both ends ("file:(in|out)\\.txt")
+//
+// An invalid regular expression never matches.
+func MatchPattern(value, pattern string) bool {
+	if value == pattern {
+		return true
+	}
+	if strings.ContainsAny(pattern, "*?") && matchWildcard(value, pattern) {
+		return true
+	}
+	re := compilePattern(pattern)
+	return re != nil && re.MatchString(value)
+}
+
+// MatchAnyPattern reports whether value matches at least one of patterns.
+func MatchAnyPattern(value string, patterns []string) bool {
+	for _, p := range patterns {
+		if MatchPattern(value, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcard implements '*' (any run) and '?' (any single character) matching.
+func matchWildcard(value, pattern string) bool {
+	v, p := 0, 0
+	star, mark := -1, 0
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			v++
+			p++
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, v
+			p++
+		case star != -1:
+			p = star + 1
+			mark++
+			v = mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+func compilePattern(pattern string) *regexp.Regexp {
+	patternCacheMu.RLock()
+	re, ok := patternCache[pattern]
+	patternCacheMu.RUnlock()
+	if ok {
+		return re
+	}
+
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		re = nil
+	}
+
+	patternCacheMu.Lock()
+	patternCache[pattern] = re
+	patternCacheMu.Unlock()
+	return re
+}
@@ -0,0 +1,263 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventType identifies the kind of an Event.
+type EventType int
+
+const (
+	ContextStartingEvent EventType = iota
+	ContextStartedEvent
+	ContextStoppingEvent
+	ContextStoppedEvent
+	RouteAddedEvent
+	RouteStartedEvent
+	RouteStoppedEvent
+	RouteRemovedEvent
+	ExchangeCreatedEvent
+	ExchangeCompletedEvent
+	ExchangeFailedEvent
+	ExchangeSendingEvent
+	ExchangeSentEvent
+	StepStartedEvent
+	StepCompletedEvent
+
+	eventTypeCount
+)
+
+var eventTypeNames = [...]string{
+	ContextStartingEvent:   "ContextStarting",
+	ContextStartedEvent:    "ContextStarted",
+	ContextStoppingEvent:   "ContextStopping",
+	ContextStoppedEvent:    "ContextStopped",
+	RouteAddedEvent:        "RouteAdded",
+	RouteStartedEvent:      "RouteStarted",
+	RouteStoppedEvent:      "RouteStopped",
+	RouteRemovedEvent:      "RouteRemoved",
+	ExchangeCreatedEvent:   "ExchangeCreated",
+	ExchangeCompletedEvent: "ExchangeCompleted",
+	ExchangeFailedEvent:    "ExchangeFailed",
+	ExchangeSendingEvent:   "ExchangeSending",
+	ExchangeSentEvent:      "ExchangeSent",
+	StepStartedEvent:       "StepStarted",
+	StepCompletedEvent:     "StepCompleted",
+}
+
+func (t EventType) String() string {
+	if t >= 0 && t < eventTypeCount {
+		return eventTypeNames[t]
+	}
+	return fmt.Sprintf("EventType(%d)", int(t))
+}
+
+// Event is something observable that happened inside the engine.
+type Event interface {
+	Type() EventType
+	Timestamp() time.Time
+}
+
+type eventBase struct {
+	typ EventType
+	at  time.Time
+}
+
+func newEventBase(t EventType) eventBase {
+	return eventBase{typ: t, at: time.Now()}
+}
+
+func (e eventBase) Type() EventType {
+	return e.typ
+}
+
+func (e eventBase) Timestamp() time.Time {
+	return e.at
+}
+
+// ContextEvent reports a context lifecycle transition.
+type ContextEvent struct {
+	eventBase
+	Context Context
+}
+
+// RouteEvent reports a route being added, started, stopped or removed.
+type RouteEvent struct {
+	eventBase
+	Route *Route
+}
+
+// ExchangeEvent reports an exchange being created, completed or failed.
+// Err is set for failures.
+type ExchangeEvent struct {
+	eventBase
+	Exchange *Exchange
+	Err      error
+}
+
+// ExchangeSendEvent reports an exchange being sent to an endpoint. Elapsed
+// and Err are only set on ExchangeSentEvent.
+type ExchangeSendEvent struct {
+	eventBase
+	Exchange    *Exchange
+	EndpointURI string
+	Elapsed     time.Duration
+	Err         error
+}
+
+// StepEvent reports a route step starting or completing. Elapsed and Err
+// are only set on StepCompletedEvent.
+type StepEvent struct {
+	eventBase
+	Exchange *Exchange
+	RouteID  string
+	StepID   string
+	Elapsed  time.Duration
+	Err      error
+}
+
+// NewContextEvent creates a ContextEvent of type t.
+func NewContextEvent(t EventType, ctx Context) *ContextEvent {
+	return &ContextEvent{eventBase: newEventBase(t), Context: ctx}
+}
+
+// NewRouteEvent creates a RouteEvent of type t.
+func NewRouteEvent(t EventType, r *Route) *RouteEvent {
+	return &RouteEvent{eventBase: newEventBase(t), Route: r}
+}
+
+// NewExchangeEvent creates an ExchangeEvent of type t.
+func NewExchangeEvent(t EventType, exchange *Exchange, err error) *ExchangeEvent {
+	return &ExchangeEvent{eventBase: newEventBase(t), Exchange: exchange, Err: err}
+}
+
+// NewExchangeSendEvent creates an ExchangeSendEvent of type t.
+func NewExchangeSendEvent(t EventType, exchange *Exchange, uri string, elapsed time.Duration, err error) *ExchangeSendEvent {
+	return &ExchangeSendEvent{eventBase: newEventBase(t), Exchange: exchange, EndpointURI: uri, Elapsed: elapsed, Err: err}
+}
+
+// NewStepEvent creates a StepEvent of type t.
+func NewStepEvent(t EventType, exchange *Exchange, routeID, stepID string, elapsed time.Duration, err error) *StepEvent {
+	return &StepEvent{eventBase: newEventBase(t), Exchange: exchange, RouteID: routeID, StepID: stepID, Elapsed: elapsed, Err: err}
+}
+
+// EventNotifier receives engine events. IsEnabled is consulted for every
+// event before Notify; errors from Notify are ignored by the engine so a
+// broken notifier can never fail an exchange.
+type EventNotifier interface {
+	Notify(event Event) error
+	IsEnabled(event Event) bool
+}
+
+// EventTypeFilter is an optional interface for notifiers that only care
+// about some event types. The context uses it to skip building events
+// nobody listens to.
+type EventTypeFilter interface {
+	AcceptsType(t EventType) bool
+}
+
+// EventNotifierFunc adapts a function to an EventNotifier limited to Types
+// (every type when Types is empty).
+type EventNotifierFunc struct {
+	Fn    func(event Event) error
+	Types []EventType
+}
+
+// NewEventNotifier adapts fn to an EventNotifier receiving only types.
+func NewEventNotifier(fn func(event Event) error, types ...EventType) *EventNotifierFunc {
+	return &EventNotifierFunc{Fn: fn, Types: types}
+}
+
+func (n *EventNotifierFunc) Notify(event Event) error {
+	return n.Fn(event)
+}
+
+func (n *EventNotifierFunc) IsEnabled(event Event) bool {
+	return n.AcceptsType(event.Type())
+}
+
+func (n *EventNotifierFunc) AcceptsType(t EventType) bool {
+	if len(n.Types) == 0 {
+		return true
+	}
+	for _, want := range n.Types {
+		if want == t {
+			return true
+		}
+	}
+	return false
+}
+
+// eventSupport holds the registered notifiers. The enabled mask has one bit
+// per EventType so emitters can check interest with a single atomic load.
+type eventSupport struct {
+	mu        sync.Mutex
+	notifiers atomic.Pointer[[]EventNotifier]
+	enabled   atomic.Uint64
+}
+
+func (s *eventSupport) add(n EventNotifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := append(s.snapshot(), n)
+	s.notifiers.Store(&list)
+	s.recompute(list)
+}
+
+func (s *eventSupport) remove(n EventNotifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []EventNotifier
+	for _, existing := range s.snapshot() {
+		if existing != n {
+			list = append(list, existing)
+		}
+	}
+	s.notifiers.Store(&list)
+	s.recompute(list)
+}
+
+func (s *eventSupport) snapshot() []EventNotifier {
+	if p := s.notifiers.Load(); p != nil {
+		return append([]EventNotifier(nil), (*p)...)
+	}
+	return nil
+}
+
+func (s *eventSupport) recompute(list []EventNotifier) {
+	var mask uint64
+	for _, n := range list {
+		f, ok := n.(EventTypeFilter)
+		for t := EventType(0); t < eventTypeCount; t++ {
+			if !ok || f.AcceptsType(t) {
+				mask |= 1 << uint(t)
+			}
+		}
+	}
+	s.enabled.Store(mask)
+}
+
+func (s *eventSupport) isEnabled(t EventType) bool {
+	return s.enabled.Load()&(1<<uint(t)) != 0
+}
+
+func (s *eventSupport) notify(event Event) {
+	p := s.notifiers.Load()
+	if p == nil {
+		return
+	}
+	for _, n := range *p {
+		if n.IsEnabled(event) {
+			_ = n.Notify(event)
+		}
+	}
+}
+
+// EventEnabled reports whether ctx has a notifier interested in t. It is
+// safe to call with a nil ctx, as processors tested in isolation often are.
+func EventEnabled(ctx Context, t EventType) bool {
+	return ctx != nil && ctx.IsEventEnabled(t)
+}
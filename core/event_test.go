@@ -0,0 +1,181 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type eventCollector struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (c *eventCollector) notify(e Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, e)
+	return nil
+}
+
+func (c *eventCollector) types() []EventType {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]EventType, 0, len(c.events))
+	for _, e := range c.events {
+		out = append(out, e.Type())
+	}
+	return out
+}
+
+// stubDefinition compiles to a processor that optionally fails.
+type stubDefinition struct {
+	ID  string
+	Err error
+}
+
+func (d *stubDefinition) GetID() string     { return d.ID }
+func (d *stubDefinition) ShortName() string { return "stub" }
+
+func (d *stubDefinition) Compile(ctx CompileContext) (Processor, error) {
+	return &MockProcessor{ProcessErr: d.Err}, nil
+}
+
+type stubLoader struct {
+	defs []*RouteDefinition
+}
+
+func (l *stubLoader) Load(source interface{}) ([]*RouteDefinition, error) {
+	return l.defs, nil
+}
+
+func TestEventType_String(t *testing.T) {
+	if ExchangeSentEvent.String() != "ExchangeSent" {
+		t.Errorf("unexpected name %s", ExchangeSentEvent)
+	}
+	if EventType(99).String() != "EventType(99)" {
+		t.Errorf("unexpected name for unknown type %s", EventType(99))
+	}
+}
+
+func TestEventSupport_MaskFollowsFilters(t *testing.T) {
+	ctx := NewContext()
+	if ctx.IsEventEnabled(ExchangeCreatedEvent) {
+		t.Fatalf("expected events to be disabled without notifiers")
+	}
+
+	routesOnly := NewEventNotifier(func(Event) error { return nil }, RouteAddedEvent, RouteStartedEvent)
+	ctx.AddEventNotifier(routesOnly)
+	if !ctx.IsEventEnabled(RouteAddedEvent) || ctx.IsEventEnabled(ExchangeCreatedEvent) {
+		t.Errorf("expected only route events to be enabled")
+	}
+
+	all := NewEventNotifier(func(Event) error { return nil })
+	ctx.AddEventNotifier(all)
+	if !ctx.IsEventEnabled(ExchangeCreatedEvent) {
+		t.Errorf("expected an unfiltered notifier to enable every type")
+	}
+
+	ctx.RemoveEventNotifier(all)
+	if ctx.IsEventEnabled(ExchangeCreatedEvent) {
+		t.Errorf("expected mask to shrink after removal")
+	}
+}
+
+func TestContext_LifecycleEvents(t *testing.T) {
+	rec := &eventCollector{}
+	ctx := NewContext()
+	ctx.RegisterComponent("rec", &recordingComponent{rec: &recorder{}})
+	ctx.AddEventNotifier(NewEventNotifier(rec.notify))
+	ctx.SetLoader(&stubLoader{defs: []*RouteDefinition{{ID: "r1", InputURI: "rec:in"}}})
+
+	if err := ctx.AddRoutes(nil); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	if err := ctx.RemoveRoute("r1"); err != nil {
+		t.Fatalf("RemoveRoute error: %v", err)
+	}
+	if err := ctx.Stop(); err != nil {
+		t.Fatalf("stop error: %v", err)
+	}
+
+	expected := []EventType{
+		RouteAddedEvent,
+		ContextStartingEvent,
+		RouteStartedEvent,
+		ContextStartedEvent,
+		RouteStoppedEvent,
+		RouteRemovedEvent,
+		ContextStoppingEvent,
+		ContextStoppedEvent,
+	}
+	if got := rec.types(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected events:\n got  %v\n want %v", got, expected)
+	}
+}
+
+func TestContext_ExchangeAndStepEvents(t *testing.T) {
+	rec := &eventCollector{}
+	ctx := NewContext()
+	ctx.RegisterComponent("rec", &recordingComponent{rec: &recorder{}})
+	ctx.SetLoader(&stubLoader{defs: []*RouteDefinition{{
+		InputURI: "rec:in",
+		Steps:    []Compilable{&stubDefinition{}, &stubDefinition{ID: "validate", Err: errors.New("invalid")}},
+	}}})
+	if err := ctx.AddRoutes(nil); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	ctx.AddEventNotifier(NewEventNotifier(rec.notify, ExchangeCreatedEvent, ExchangeFailedEvent, StepStartedEvent, StepCompletedEvent))
+
+	route := ctx.Routes()[0]
+	if route.ID != "route1" {
+		t.Errorf("expected generated route ID route1, got %s", route.ID)
+	}
+
+	ex := ctx.NewExchange()
+	if err := route.Pipeline.Process(ctx, ex); err == nil {
+		t.Fatalf("expected pipeline error")
+	}
+	if ex.FromRouteID() != "route1" {
+		t.Errorf("expected exchange to be tagged with route1, got %q", ex.FromRouteID())
+	}
+
+	expected := []EventType{
+		ExchangeCreatedEvent,
+		StepStartedEvent,
+		StepCompletedEvent,
+		StepStartedEvent,
+		StepCompletedEvent,
+		ExchangeFailedEvent,
+	}
+	if got := rec.types(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("unexpected events:\n got  %v\n want %v", got, expected)
+	}
+
+	first := rec.events[1].(*StepEvent)
+	second := rec.events[4].(*StepEvent)
+	if first.StepID != "stub1" || first.RouteID != "route1" {
+		t.Errorf("expected generated step ID stub1 in route1, got %s in %s", first.StepID, first.RouteID)
+	}
+	if second.StepID != "validate" || second.Err == nil {
+		t.Errorf("expected failed step validate, got %s (%v)", second.StepID, second.Err)
+	}
+	if failed := rec.events[5].(*ExchangeEvent); failed.Exchange != ex || failed.Err == nil {
+		t.Errorf("expected failure event for the exchange, got %+v", failed)
+	}
+}
+
+func TestContext_NewExchangeHasUniqueID(t *testing.T) {
+	ctx := NewContext()
+	a, b := ctx.NewExchange(), ctx.NewExchange()
+	if a.ID() == b.ID() {
+		t.Errorf("expected unique exchange IDs, got %s twice", a.ID())
+	}
+	if a.In() == nil || a.Properties() == nil {
+		t.Errorf("expected a fully initialized exchange")
+	}
+}
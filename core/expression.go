@@ -0,0 +1,29 @@
+package core
+
+// Expression computes a value from an exchange, e.g. a header to set or an
+// endpoint URI to send to.
+type Expression interface {
+	Evaluate(ctx Context, exchange *Exchange) (interface{}, error)
+}
+
+// ExpressionFunc adapts a function to an Expression.
+type ExpressionFunc func(ctx Context, exchange *Exchange) (interface{}, error)
+
+func (f ExpressionFunc) Evaluate(ctx Context, exchange *Exchange) (interface{}, error) {
+	return f(ctx, exchange)
+}
+
+// PredicateFunc adapts a function to a Predicate.
+type PredicateFunc func(ctx Context, exchange *Exchange) (bool, error)
+
+func (f PredicateFunc) Evaluate(ctx Context, exchange *Exchange) (bool, error) {
+	return f(ctx, exchange)
+}
+
+// StaticPredicate is implemented by predicates whose result does not depend
+// on the exchange, such as a simple predicate without functions. Tooling
+// uses it to find branches that always or never match.
+type StaticPredicate interface {
+	Predicate
+	StaticValue() (value bool, ok bool)
+}
@@ -0,0 +1,277 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GraphFormat selects the output of DumpRouteGraph.
+type GraphFormat string
+
+const (
+	GraphPlantUML GraphFormat = "plantuml" // activity diagram
+	GraphDOT      GraphFormat = "dot"      // Graphviz digraph
+	GraphMermaid  GraphFormat = "mermaid"  // flowchart
+)
+
+// DumpRouteGraph renders every route of the context in format. Nested
+// branches (choice, filter, ...) are drawn from BranchingDefinition, and a
+// step sending to an endpoint another route consumes from (e.g. direct: or
+// seda:) is linked to that route. With counts, steps are annotated with the
+// number of exchanges they have processed so far.
+func (c *DefaultContext) DumpRouteGraph(format GraphFormat, counts bool) (string, error) {
+	g := &routeGraph{counts: counts, inputs: make(map[string]*Route)}
+	g.routes = c.Routes()
+	for _, r := range g.routes {
+		g.inputs[endpointKey(r.InputURI)] = r
+	}
+
+	switch format {
+	case GraphDOT, GraphMermaid:
+		for _, r := range g.routes {
+			g.flatten(r)
+		}
+		if format == GraphDOT {
+			return g.renderDOT(), nil
+		}
+		return g.renderMermaid(), nil
+	case GraphPlantUML:
+		return g.renderPlantUML(), nil
+	}
+	return "", fmt.Errorf("unknown graph format %q (supported: %s, %s, %s)", format, GraphPlantUML, GraphDOT, GraphMermaid)
+}
+
+// endpointKey strips options and "//" so "seda://a?size=5" links to "seda:a".
+func endpointKey(uri string) string {
+	base, _, _ := strings.Cut(uri, "?")
+	if scheme, rest, ok := strings.Cut(base, ":"); ok {
+		return scheme + ":" + strings.TrimPrefix(rest, "//")
+	}
+	return base
+}
+
+type graphNode struct {
+	id, label string
+	shape     string // "start", "step" or "branch"
+}
+
+type graphEdge struct {
+	from, to, label string
+	link            bool // link to another route
+}
+
+type routeGraph struct {
+	counts bool
+	routes []*Route
+	inputs map[string]*Route
+
+	clusters [][]graphNode // nodes per route, in route order
+	edges    []graphEdge
+}
+
+// pendingEdge is an edge waiting for the next step to connect to.
+type pendingEdge struct {
+	from, label string
+}
+
+func nodeID(routeID, stepID string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, routeID+"_"+stepID)
+}
+
+func (g *routeGraph) startID(r *Route) string {
+	return nodeID(r.ID, "_from")
+}
+
+func (g *routeGraph) startLabel(r *Route) string {
+	label := "from " + MaskURI(r.context, r.InputURI)
+	if g.counts {
+		label += fmt.Sprintf(" [%d]", r.Statistics().ExchangesTotal)
+	}
+	return label
+}
+
+func (g *routeGraph) stepLabel(r *Route, s StepNode) string {
+	label := s.ID
+	if s.URI != "" {
+		label += ": " + s.URI
+	}
+	if g.counts {
+		label += fmt.Sprintf(" [%d]", r.StepStatistics(s.ID).ExchangesTotal)
+	}
+	return label
+}
+
+// linkTarget returns the route consuming from the endpoint s sends to.
+func (g *routeGraph) linkTarget(s StepNode) *Route {
+	if s.URI == "" {
+		return nil
+	}
+	return g.inputs[endpointKey(s.URI)]
+}
+
+// fallsThrough reports whether exchanges can pass a branching step without
+// entering any branch, i.e. it has no otherwise branch.
+func fallsThrough(s StepNode) bool {
+	for _, b := range s.Branches {
+		if b.Label == "otherwise" {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *routeGraph) flatten(r *Route) {
+	start := g.startID(r)
+	nodes := []graphNode{{id: start, label: g.startLabel(r), shape: "start"}}
+	g.clusters = append(g.clusters, nodes)
+	g.flattenSteps(r, r.Dump().Steps, []pendingEdge{{from: start}})
+}
+
+func (g *routeGraph) addNode(n graphNode) {
+	last := len(g.clusters) - 1
+	g.clusters[last] = append(g.clusters[last], n)
+}
+
+func (g *routeGraph) flattenSteps(r *Route, steps []StepNode, prevs []pendingEdge) []pendingEdge {
+	for _, s := range steps {
+		id := nodeID(r.ID, s.ID)
+		shape := "step"
+		if len(s.Branches) > 0 {
+			shape = "branch"
+		}
+		g.addNode(graphNode{id: id, label: g.stepLabel(r, s), shape: shape})
+		for _, p := range prevs {
+			g.edges = append(g.edges, graphEdge{from: p.from, to: id, label: p.label})
+		}
+		if target := g.linkTarget(s); target != nil {
+			g.edges = append(g.edges, graphEdge{from: id, to: g.startID(target), link: true})
+		}
+
+		if len(s.Branches) == 0 {
+			prevs = []pendingEdge{{from: id}}
+			continue
+		}
+		var exits []pendingEdge
+		for _, b := range s.Branches {
+			exits = append(exits, g.flattenSteps(r, b.Steps, []pendingEdge{{from: id, label: b.Label}})...)
+		}
+		if fallsThrough(s) {
+			exits = append(exits, pendingEdge{from: id})
+		}
+		prevs = exits
+	}
+	return prevs
+}
+
+func (g *routeGraph) renderDOT() string {
+	var sb strings.Builder
+	quote := func(s string) string { return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"` }
+	sb.WriteString("digraph routes {\n  rankdir=TB;\n  node [shape=box, style=rounded];\n")
+	for i, nodes := range g.clusters {
+		r := g.routes[i]
+		fmt.Fprintf(&sb, "  subgraph %s {\n    label=%s;\n", quote("cluster_"+r.ID), quote("route "+r.ID))
+		for _, n := range nodes {
+			attrs := "label=" + quote(n.label)
+			switch n.shape {
+			case "start":
+				attrs += ", shape=oval"
+			case "branch":
+				attrs += ", shape=diamond, style=solid"
+			}
+			fmt.Fprintf(&sb, "    %s [%s];\n", n.id, attrs)
+		}
+		sb.WriteString("  }\n")
+	}
+	for _, e := range g.edges {
+		var attrs []string
+		if e.label != "" {
+			attrs = append(attrs, "label="+quote(e.label))
+		}
+		if e.link {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(&sb, "  %s -> %s [%s];\n", e.from, e.to, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(&sb, "  %s -> %s;\n", e.from, e.to)
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (g *routeGraph) renderMermaid() string {
+	var sb strings.Builder
+	quote := func(s string) string { return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"` }
+	sb.WriteString("flowchart TD\n")
+	for i, nodes := range g.clusters {
+		r := g.routes[i]
+		fmt.Fprintf(&sb, "  subgraph %s[%s]\n", nodeID("route", r.ID), quote("route "+r.ID))
+		for _, n := range nodes {
+			switch n.shape {
+			case "start":
+				fmt.Fprintf(&sb, "    %s([%s])\n", n.id, quote(n.label))
+			case "branch":
+				fmt.Fprintf(&sb, "    %s{%s}\n", n.id, quote(n.label))
+			default:
+				fmt.Fprintf(&sb, "    %s[%s]\n", n.id, quote(n.label))
+			}
+		}
+		sb.WriteString("  end\n")
+	}
+	for _, e := range g.edges {
+		arrow := "-->"
+		if e.link {
+			arrow = "-.->"
+		}
+		if e.label != "" {
+			fmt.Fprintf(&sb, "  %s %s|%s| %s\n", e.from, arrow, quote(e.label), e.to)
+		} else {
+			fmt.Fprintf(&sb, "  %s %s %s\n", e.from, arrow, e.to)
+		}
+	}
+	return sb.String()
+}
+
+// renderPlantUML draws one activity flow per route. Links to other routes
+// are shown as notes, since activity diagrams have no cross-flow arrows.
+func (g *routeGraph) renderPlantUML() string {
+	var sb strings.Builder
+	sb.WriteString("@startuml\n")
+	for _, r := range g.routes {
+		fmt.Fprintf(&sb, "partition \"route %s\" {\nstart\n:%s;\n", r.ID, g.startLabel(r))
+		g.plantUMLSteps(&sb, r, r.Dump().Steps, "")
+		sb.WriteString("stop\n}\n")
+	}
+	sb.WriteString("@enduml\n")
+	return sb.String()
+}
+
+func (g *routeGraph) plantUMLSteps(sb *strings.Builder, r *Route, steps []StepNode, indent string) {
+	for _, s := range steps {
+		label := g.stepLabel(r, s)
+		switch {
+		case len(s.Branches) == 0:
+			fmt.Fprintf(sb, "%s:%s;\n", indent, label)
+		case len(s.Branches) == 1 && fallsThrough(s):
+			fmt.Fprintf(sb, "%sif (%s) then (%s)\n", indent, label, s.Branches[0].Label)
+			g.plantUMLSteps(sb, r, s.Branches[0].Steps, indent+"  ")
+			fmt.Fprintf(sb, "%sendif\n", indent)
+		default:
+			fmt.Fprintf(sb, "%sswitch (%s)\n", indent, label)
+			for _, b := range s.Branches {
+				fmt.Fprintf(sb, "%scase (%s)\n", indent, b.Label)
+				g.plantUMLSteps(sb, r, b.Steps, indent+"  ")
+			}
+			fmt.Fprintf(sb, "%sendswitch\n", indent)
+		}
+		if target := g.linkTarget(s); target != nil {
+			fmt.Fprintf(sb, "%snote right: continues in route %s\n", indent, target.ID)
+		}
+	}
+}
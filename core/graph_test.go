@@ -0,0 +1,129 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+// stubSend is a "to" step that does nothing but report its URI.
+type stubSend struct {
+	URI string
+}
+
+func (d *stubSend) ShortName() string   { return "to" }
+func (d *stubSend) EndpointURI() string { return d.URI }
+
+func (d *stubSend) Compile(ctx CompileContext) (Processor, error) {
+	return &MockProcessor{}, nil
+}
+
+// stubChoice compiles its branches so their steps get IDs, and always runs
+// the first one.
+type stubChoice struct {
+	branches []Branch
+}
+
+func (d *stubChoice) ShortName() string  { return "choice" }
+func (d *stubChoice) Branches() []Branch { return d.branches }
+
+func (d *stubChoice) Compile(ctx CompileContext) (Processor, error) {
+	var first Processor = &MockProcessor{}
+	for i, b := range d.branches {
+		pipeline, err := CompileSteps(ctx, b.Steps)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			first = pipeline
+		}
+	}
+	return first, nil
+}
+
+func newGraphContext(t *testing.T) *DefaultContext {
+	t.Helper()
+	ctx := NewContext()
+	ctx.RegisterComponent("rec", &recordingComponent{rec: &recorder{}})
+	ctx.SetLoader(&stubLoader{defs: []*RouteDefinition{
+		{ID: "orders", InputURI: "rec:orders", Steps: []Compilable{
+			&stubChoice{branches: []Branch{
+				{Label: "when", Steps: []Compilable{&stubSend{URI: "rec://audit?size=5&token=abc"}}},
+				{Label: "otherwise", Steps: []Compilable{&stubDefinition{ID: "drop"}}},
+			}},
+			&stubSend{URI: "rec:out"},
+		}},
+		{ID: "audit", InputURI: "rec:audit", Steps: []Compilable{&stubDefinition{}}},
+	}})
+	if err := ctx.AddRoutes(nil); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	return ctx
+}
+
+func TestDumpRouteGraph_DOT(t *testing.T) {
+	ctx := newGraphContext(t)
+	ctx.Route("orders").Pipeline.Process(ctx, ctx.NewExchange())
+
+	out, err := ctx.DumpRouteGraph(GraphDOT, true)
+	if err != nil {
+		t.Fatalf("dump error: %v", err)
+	}
+	for _, want := range []string{
+		`subgraph "cluster_orders"`,
+		`orders__from [label="from rec:orders [1]", shape=oval];`,
+		`orders_choice1 [label="choice1 [1]", shape=diamond, style=solid];`,
+		`orders_choice1 -> orders_to1 [label="when"];`,
+		`orders_choice1 -> orders_drop [label="otherwise"];`,
+		`orders_to1 -> orders_to2;`,
+		`orders_drop -> orders_to2;`,
+		`orders_to1 -> audit__from [style=dashed];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected DOT to contain %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "orders_choice1 -> orders_to2") {
+		t.Errorf("expected no fall-through edge for a choice with otherwise\n%s", out)
+	}
+}
+
+func TestRoute_DumpMasksSecrets(t *testing.T) {
+	ctx := newGraphContext(t)
+	dump := ctx.Route("orders").Dump()
+	if uri := dump.Steps[0].Branches[0].Steps[0].URI; uri != "rec:audit?size=5&token=xxxxxx" {
+		t.Errorf("expected the token to be masked, got %q", uri)
+	}
+}
+
+func TestDumpRouteGraph_MermaidAndPlantUML(t *testing.T) {
+	ctx := newGraphContext(t)
+
+	mermaid, _ := ctx.DumpRouteGraph(GraphMermaid, false)
+	for _, want := range []string{
+		"flowchart TD",
+		`subgraph route_orders["route orders"]`,
+		`orders_choice1{"choice1"}`,
+		`orders_choice1 -->|"when"| orders_to1`,
+		"orders_to1 -.-> audit__from",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("expected Mermaid to contain %q\n%s", want, mermaid)
+		}
+	}
+
+	puml, _ := ctx.DumpRouteGraph(GraphPlantUML, false)
+	for _, want := range []string{
+		"@startuml",
+		"partition \"route orders\" {\nstart\n:from rec:orders;",
+		"switch (choice1)\ncase (when)\n  :to1: rec:audit?size=5&token=xxxxxx;\n  note right: continues in route audit\ncase (otherwise)\n  :drop;\nendswitch\n:to2: rec:out;",
+		"@enduml",
+	} {
+		if !strings.Contains(puml, want) {
+			t.Errorf("expected PlantUML to contain %q\n%s", want, puml)
+		}
+	}
+
+	if _, err := ctx.DumpRouteGraph("svg", false); err == nil {
+		t.Errorf("expected error for an unknown format")
+	}
+}
@@ -0,0 +1,299 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// HealthState is the outcome of a health check.
+type HealthState string
+
+const (
+	HealthUp      HealthState = "UP"
+	HealthDown    HealthState = "DOWN"
+	HealthUnknown HealthState = "UNKNOWN"
+)
+
+// HealthKind says which probes a check takes part in.
+type HealthKind int
+
+const (
+	LivenessCheck HealthKind = 1 << iota
+	ReadinessCheck
+
+	LivenessAndReadiness = LivenessCheck | ReadinessCheck
+)
+
+// HealthResult is the result of one check. Details are free-form and end up
+// in the JSON served by the health handler.
+type HealthResult struct {
+	ID      string                 `json:"id"`
+	State   HealthState            `json:"status"`
+	Message string                 `json:"message,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// HealthReport aggregates the results of a probe. It is DOWN when any check
+// is DOWN; UNKNOWN results do not fail the probe.
+type HealthReport struct {
+	State  HealthState    `json:"status"`
+	Checks []HealthResult `json:"checks"`
+}
+
+// HealthCheck is a single named check.
+type HealthCheck interface {
+	ID() string
+	Kind() HealthKind
+	Call(ctx Context) HealthResult
+}
+
+// HealthCheckProvider is implemented by components that contribute checks.
+// They are picked up from the registered components on every probe.
+type HealthCheckProvider interface {
+	HealthChecks() []HealthCheck
+}
+
+// HealthAware is implemented by consumers that can report their own health,
+// e.g. whether the resource they read from is reachable. A nil error is UP.
+type HealthAware interface {
+	Health(ctx Context) error
+}
+
+// HealthCheckFunc adapts a function to a HealthCheck.
+type HealthCheckFunc struct {
+	CheckID   string
+	CheckKind HealthKind
+	Fn        func(ctx Context) HealthResult
+}
+
+// NewHealthCheck adapts fn to a HealthCheck with the given ID and kind.
+func NewHealthCheck(id string, kind HealthKind, fn func(ctx Context) HealthResult) *HealthCheckFunc {
+	return &HealthCheckFunc{CheckID: id, CheckKind: kind, Fn: fn}
+}
+
+func (c *HealthCheckFunc) ID() string       { return c.CheckID }
+func (c *HealthCheckFunc) Kind() HealthKind { return c.CheckKind }
+
+func (c *HealthCheckFunc) Call(ctx Context) HealthResult {
+	r := c.Fn(ctx)
+	if r.ID == "" {
+		r.ID = c.CheckID
+	}
+	if r.State == "" {
+		r.State = HealthUnknown
+	}
+	return r
+}
+
+// HealthCheckRegistry holds the checks of a context. New registries contain
+// the built-in checks "context", "context-started", "routes" and
+// "consumers".
+type HealthCheckRegistry struct {
+	mu      sync.RWMutex
+	context *DefaultContext
+	checks  []HealthCheck
+}
+
+func newHealthCheckRegistry(c *DefaultContext) *HealthCheckRegistry {
+	r := &HealthCheckRegistry{context: c}
+	r.checks = []HealthCheck{
+		NewHealthCheck("context", LivenessCheck, contextHealth),
+		NewHealthCheck("context-started", ReadinessCheck, contextStartedHealth),
+		NewHealthCheck("routes", ReadinessCheck, routesHealth),
+		NewHealthCheck("consumers", ReadinessCheck, consumersHealth),
+	}
+	return r
+}
+
+// HealthCheckRegistry returns the context's health check registry.
+func (c *DefaultContext) HealthCheckRegistry() *HealthCheckRegistry {
+	c.healthOnce.Do(func() {
+		c.health = newHealthCheckRegistry(c)
+	})
+	return c.health
+}
+
+// Register adds check, replacing a check with the same ID.
+func (r *HealthCheckRegistry) Register(check HealthCheck) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.checks {
+		if existing.ID() == check.ID() {
+			r.checks[i] = check
+			return
+		}
+	}
+	r.checks = append(r.checks, check)
+}
+
+// Unregister removes the check with the given ID.
+func (r *HealthCheckRegistry) Unregister(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.checks {
+		if existing.ID() == id {
+			r.checks = append(r.checks[:i], r.checks[i+1:]...)
+			return
+		}
+	}
+}
+
+// Checks returns the registered checks followed by those provided by
+// components, ordered by ID within each group.
+func (r *HealthCheckRegistry) Checks() []HealthCheck {
+	r.mu.RLock()
+	out := append([]HealthCheck(nil), r.checks...)
+	r.mu.RUnlock()
+
+	components := r.context.Components()
+	schemes := make([]string, 0, len(components))
+	for scheme := range components {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	for _, scheme := range schemes {
+		if p, ok := components[scheme].(HealthCheckProvider); ok {
+			out = append(out, p.HealthChecks()...)
+		}
+	}
+	return out
+}
+
+// Liveness runs the liveness checks.
+func (r *HealthCheckRegistry) Liveness() HealthReport {
+	return r.run(LivenessCheck)
+}
+
+// Readiness runs the readiness checks.
+func (r *HealthCheckRegistry) Readiness() HealthReport {
+	return r.run(ReadinessCheck)
+}
+
+// Check runs the check with the given ID.
+func (r *HealthCheckRegistry) Check(id string) (HealthResult, bool) {
+	for _, check := range r.Checks() {
+		if check.ID() == id {
+			return callHealthCheck(r.context, check), true
+		}
+	}
+	return HealthResult{}, false
+}
+
+func (r *HealthCheckRegistry) run(kind HealthKind) HealthReport {
+	report := HealthReport{State: HealthUp, Checks: []HealthResult{}}
+	for _, check := range r.Checks() {
+		if check.Kind()&kind == 0 {
+			continue
+		}
+		result := callHealthCheck(r.context, check)
+		if result.State == HealthDown {
+			report.State = HealthDown
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+// callHealthCheck runs check, turning a panic into a DOWN result.
+func callHealthCheck(ctx Context, check HealthCheck) (result HealthResult) {
+	defer func() {
+		if p := recover(); p != nil {
+			result = HealthResult{ID: check.ID(), State: HealthDown, Message: fmt.Sprintf("check panicked: %v", p)}
+		}
+	}()
+	return check.Call(ctx)
+}
+
+// contextHealth is UP while the context is starting or running, and DOWN
+// once it failed or stopped.
+func contextHealth(ctx Context) HealthResult {
+	status := ctx.Status()
+	r := HealthResult{State: HealthUp, Details: map[string]interface{}{"status": status.String()}}
+	switch status {
+	case Failed, Stopping, Stopped:
+		r.State = HealthDown
+		r.Message = "context is " + status.String()
+	case Started:
+	default:
+		r.Message = "context is " + status.String()
+	}
+	return r
+}
+
+// contextStartedHealth is DOWN unless the context is Started, so a context
+// is not ready while it is still starting.
+func contextStartedHealth(ctx Context) HealthResult {
+	status := ctx.Status()
+	r := HealthResult{State: HealthUp, Details: map[string]interface{}{"status": status.String()}}
+	if status != Started {
+		r.State = HealthDown
+		r.Message = "context is " + status.String()
+	}
+	return r
+}
+
+// routesContext is implemented by contexts that expose their routes.
+type routesContext interface {
+	Routes() []*Route
+}
+
+// routesHealth is DOWN when any route is not Started. Supervised routes that
+// are backing off report their restart state.
+func routesHealth(ctx Context) HealthResult {
+	c, ok := ctx.(routesContext)
+	if !ok {
+		return HealthResult{State: HealthUnknown, Message: fmt.Sprintf("routes of %T are not available", ctx)}
+	}
+	r := HealthResult{State: HealthUp, Details: map[string]interface{}{}}
+	var supervisor *SupervisingRouteController
+	if rc, ok := ctx.(interface{ RouteController() RouteController }); ok {
+		supervisor, _ = rc.RouteController().(*SupervisingRouteController)
+	}
+
+	var down []string
+	for _, route := range c.Routes() {
+		status := route.Status().String()
+		if supervisor != nil {
+			if st, ok := supervisor.RestartState(route.ID); ok && st.Status != SupervisedRouteStarted {
+				status = string(st.Status)
+			}
+		}
+		r.Details[route.ID] = status
+		if route.Status() != Started {
+			down = append(down, route.ID)
+		}
+	}
+	if len(down) > 0 {
+		r.State = HealthDown
+		r.Message = fmt.Sprintf("routes not started: %v", down)
+	}
+	return r
+}
+
+// consumersHealth asks every route consumer implementing HealthAware.
+func consumersHealth(ctx Context) HealthResult {
+	c, ok := ctx.(routesContext)
+	if !ok {
+		return HealthResult{State: HealthUnknown, Message: fmt.Sprintf("routes of %T are not available", ctx)}
+	}
+	r := HealthResult{State: HealthUp, Details: map[string]interface{}{}}
+	var down []string
+	for _, route := range c.Routes() {
+		h, ok := route.Consumer.(HealthAware)
+		if !ok {
+			continue
+		}
+		if err := h.Health(ctx); err != nil {
+			r.Details[route.ID] = err.Error()
+			down = append(down, route.ID)
+		} else {
+			r.Details[route.ID] = string(HealthUp)
+		}
+	}
+	if len(down) > 0 {
+		r.State = HealthDown
+		r.Message = fmt.Sprintf("unhealthy consumers in routes: %v", down)
+	}
+	return r
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+var errUnreachable = errors.New("unreachable")
+
+// healthComponent contributes a check through HealthCheckProvider.
+type healthComponent struct {
+	recordingComponent
+	state HealthState
+}
+
+func (c *healthComponent) HealthChecks() []HealthCheck {
+	return []HealthCheck{NewHealthCheck("component:rec", ReadinessCheck, func(ctx Context) HealthResult {
+		return HealthResult{State: c.state}
+	})}
+}
+
+// healthyConsumer implements HealthAware.
+type healthyConsumer struct {
+	MockConsumer
+	err error
+}
+
+func (c *healthyConsumer) Health(ctx Context) error {
+	return c.err
+}
+
+func resultFor(report HealthReport, id string) (HealthResult, bool) {
+	for _, r := range report.Checks {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return HealthResult{}, false
+}
+
+func TestHealthCheckRegistry_BuiltInChecks(t *testing.T) {
+	ctx := NewContext()
+	consumer := &healthyConsumer{}
+	ctx.routes = append(ctx.routes, &Route{ID: "r1", Consumer: consumer})
+	reg := ctx.HealthCheckRegistry()
+
+	if got := reg.Readiness().State; got != HealthDown {
+		t.Errorf("expected not ready before start, got %s", got)
+	}
+	if got := reg.Liveness().State; got != HealthUp {
+		t.Errorf("expected live before start, got %s", got)
+	}
+
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	if report := reg.Readiness(); report.State != HealthUp {
+		t.Errorf("expected ready after start, got %+v", report)
+	}
+
+	consumer.err = errUnreachable
+	report := reg.Readiness()
+	if r, _ := resultFor(report, "consumers"); report.State != HealthDown || r.Details["r1"] != errUnreachable.Error() {
+		t.Errorf("expected unhealthy consumer to fail readiness, got %+v", report)
+	}
+	if reg.Liveness().State != HealthUp {
+		t.Errorf("expected consumer health not to affect liveness")
+	}
+
+	ctx.Stop()
+	if r, _ := resultFor(reg.Readiness(), "context-started"); r.State != HealthDown {
+		t.Errorf("expected context-started check DOWN after stop, got %+v", r)
+	}
+	if r, _ := resultFor(reg.Liveness(), "context"); r.State != HealthDown {
+		t.Errorf("expected context check DOWN after stop, got %+v", r)
+	}
+}
+
+func TestHealthCheckRegistry_CustomAndComponentChecks(t *testing.T) {
+	ctx := NewContext()
+	comp := &healthComponent{state: HealthDown}
+	ctx.RegisterComponent("rec", comp)
+	ctx.Start()
+	defer ctx.Stop()
+
+	reg := ctx.HealthCheckRegistry()
+	reg.Register(NewHealthCheck("db", LivenessCheck, func(ctx Context) HealthResult {
+		panic("connection pool gone")
+	}))
+
+	if r, ok := resultFor(reg.Readiness(), "component:rec"); !ok || r.State != HealthDown {
+		t.Errorf("expected component check in readiness, got %+v", r)
+	}
+	if _, ok := resultFor(reg.Readiness(), "db"); ok {
+		t.Errorf("expected liveness-only check to be excluded from readiness")
+	}
+	if r, _ := reg.Check("db"); r.State != HealthDown {
+		t.Errorf("expected panicking check to be DOWN, got %+v", r)
+	}
+
+	reg.Unregister("db")
+	comp.state = HealthUp
+	if report := reg.Liveness(); report.State != HealthUp {
+		t.Errorf("expected live after unregistering, got %+v", report)
+	}
+}
+
+func TestHealthCheckRegistry_NotReadyUntilStarted(t *testing.T) {
+	ctx := NewContext()
+	reg := ctx.HealthCheckRegistry()
+	if r, _ := resultFor(reg.Readiness(), "context-started"); reg.Readiness().State != HealthDown || r.State != HealthDown {
+		t.Errorf("expected a context without routes not to be ready before start, got %+v", reg.Readiness())
+	}
+	if reg.Liveness().State != HealthUp {
+		t.Errorf("expected a context that is not started yet to be live")
+	}
+}
+
+// bareContext is a Context that does not expose its routes.
+type bareContext struct {
+	Context
+}
+
+func TestHealthCheckRegistry_OtherContexts(t *testing.T) {
+	ctx := bareContext{NewContext()}
+	for _, fn := range []func(Context) HealthResult{routesHealth, consumersHealth} {
+		if r := fn(ctx); r.State != HealthUnknown {
+			t.Errorf("expected UNKNOWN for a context without routes, got %+v", r)
+		}
+	}
+}
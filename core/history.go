@@ -0,0 +1,69 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MessageHistoryEntry records one step an exchange went through.
+type MessageHistoryEntry struct {
+	RouteID     string        `json:"routeId"`
+	StepID      string        `json:"stepId"`
+	EndpointURI string        `json:"endpointUri,omitempty"`
+	Start       time.Time     `json:"start"`
+	Duration    time.Duration `json:"duration"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// SetMessageHistory turns recording of message history on or off for
+// routes added afterwards, so call it before AddRoutes.
+func (c *DefaultContext) SetMessageHistory(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.messageHistory = enabled
+}
+
+// IsMessageHistory reports whether message history is recorded.
+func (c *DefaultContext) IsMessageHistory() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.messageHistory
+}
+
+// MessageHistory returns a copy of the steps the exchange has visited, in
+// the order they started. It is empty unless message history is enabled.
+func (e *Exchange) MessageHistory() []MessageHistoryEntry {
+	return append([]MessageHistoryEntry(nil), e.history...)
+}
+
+// beginHistory appends an entry for a starting step and returns its index.
+func (e *Exchange) beginHistory(routeID, stepID, uri string) int {
+	e.history = append(e.history, MessageHistoryEntry{RouteID: routeID, StepID: stepID, EndpointURI: uri, Start: time.Now()})
+	return len(e.history) - 1
+}
+
+func (e *Exchange) endHistory(i int, err error) {
+	if i >= len(e.history) {
+		return
+	}
+	entry := &e.history[i]
+	entry.Duration = time.Since(entry.Start)
+	if err != nil {
+		entry.Error = err.Error()
+	}
+}
+
+// FormatMessageHistory renders the history of exchange as a table for logs,
+// or "" when there is none.
+func FormatMessageHistory(exchange *Exchange) string {
+	if len(exchange.history) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-20s %-20s %-40s %12s  %s\n", "Route", "Step", "Endpoint", "Elapsed", "Error")
+	for _, h := range exchange.history {
+		fmt.Fprintf(&sb, "%-20s %-20s %-40s %12s  %s\n", h.RouteID, h.StepID, h.EndpointURI, h.Duration, h.Error)
+	}
+	return sb.String()
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func newHistoryContext(t *testing.T, enabled bool) *DefaultContext {
+	t.Helper()
+	ctx := NewContext()
+	ctx.SetMessageHistory(enabled)
+	ctx.RegisterComponent("rec", &recordingComponent{rec: &recorder{}})
+	ctx.SetLoader(&stubLoader{defs: []*RouteDefinition{
+		{ID: "orders", InputURI: "rec:orders", Steps: []Compilable{
+			&stubChoice{branches: []Branch{{Label: "when", Steps: []Compilable{&stubSend{URI: "rec:audit?password=secret"}}}}},
+			&stubDefinition{ID: "validate", Err: NewTestError("invalid order")},
+			&stubSend{URI: "rec:never"},
+		}},
+	}})
+	if err := ctx.AddRoutes(nil); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	return ctx
+}
+
+func TestMessageHistory_RecordsVisitedSteps(t *testing.T) {
+	ctx := newHistoryContext(t, true)
+	ex := ctx.NewExchange()
+	if err := ctx.Route("orders").Pipeline.Process(ctx, ex); err == nil {
+		t.Fatalf("expected the route to fail")
+	}
+
+	history := ex.MessageHistory()
+	var steps []string
+	for _, h := range history {
+		steps = append(steps, h.RouteID+"/"+h.StepID)
+	}
+	if got := strings.Join(steps, ","); got != "orders/choice1,orders/to1,orders/validate" {
+		t.Fatalf("unexpected history %s", got)
+	}
+	if history[0].EndpointURI != "rec:orders" || history[1].EndpointURI != "rec:audit?password=xxxxxx" {
+		t.Errorf("unexpected endpoints %+v", history)
+	}
+	if history[2].Error != "invalid order" || history[1].Error != "" {
+		t.Errorf("expected only the failing step to carry the error, got %+v", history)
+	}
+	if history[0].Duration < history[1].Duration {
+		t.Errorf("expected parent duration to cover the nested step")
+	}
+
+	clone := ex.Clone()
+	if len(clone.MessageHistory()) != 3 {
+		t.Errorf("expected clone to carry the history")
+	}
+
+	failures := ctx.FailureHistory().Recent()
+	if len(failures) != 1 || len(failures[0].History) != 3 {
+		t.Errorf("expected failure record with history, got %+v", failures)
+	}
+	if out := FormatMessageHistory(ex); !strings.Contains(out, "validate") || !strings.Contains(out, "invalid order") {
+		t.Errorf("unexpected formatted history:\n%s", out)
+	}
+}
+
+func TestMessageHistory_DisabledByDefault(t *testing.T) {
+	ctx := newHistoryContext(t, false)
+	ex := ctx.NewExchange()
+	ctx.Route("orders").Pipeline.Process(ctx, ex)
+	if len(ex.MessageHistory()) != 0 || FormatMessageHistory(ex) != "" {
+		t.Errorf("expected no history when disabled, got %+v", ex.MessageHistory())
+	}
+}
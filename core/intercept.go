@@ -0,0 +1,85 @@
+package core
+
+// RouteStepKind is the StepInfo.Kind used for a route's entry point, i.e.
+// the processor the consumer feeds exchanges into.
+const RouteStepKind = "route"
+
+// StepInfo describes a step to intercept strategies as routes are compiled.
+type StepInfo struct {
+	RouteID    string
+	StepID     string     // empty for the route entry
+	Kind       string     // definition short name, or RouteStepKind
+	URI        string     // route input URI for the route entry
+	Definition Compilable // nil for the route entry
+}
+
+// InterceptStrategy wraps processors while routes are compiled. It is called
+// once for every step and once per route with Kind RouteStepKind around the
+// whole pipeline. Returning target unchanged opts out.
+type InterceptStrategy interface {
+	WrapProcessor(ctx Context, info StepInfo, target Processor) (Processor, error)
+}
+
+// ProducerInterceptor wraps producers created through CreateProducer.
+type ProducerInterceptor interface {
+	WrapProducer(ctx Context, endpoint Endpoint, producer Producer) (Producer, error)
+}
+
+// ContextDefinition is a definition that applies to the whole context, such
+// as an interceptor, rather than to a single route.
+type ContextDefinition interface {
+	Install(ctx Context) error
+}
+
+// ContextDefinitionSource is implemented by route sources (e.g. DSL builders)
+// that also declare context definitions. AddRoutes installs them before
+// compiling the source's routes, so they apply to those routes and to routes
+// added later, but not to routes that already exist.
+type ContextDefinitionSource interface {
+	GetContextDefinitions() []ContextDefinition
+}
+
+// AddInterceptStrategy registers s for routes compiled from now on.
+// Strategies registered first end up outermost.
+func (c *DefaultContext) AddInterceptStrategy(s InterceptStrategy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interceptStrategies = append(c.interceptStrategies, s)
+}
+
+// AddProducerInterceptor registers p for producers created from now on.
+func (c *DefaultContext) AddProducerInterceptor(p ProducerInterceptor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.producerInterceptors = append(c.producerInterceptors, p)
+}
+
+// applyInterceptStrategies wraps target with every registered strategy.
+func (c *DefaultContext) applyInterceptStrategies(info StepInfo, target Processor) (Processor, error) {
+	c.mu.RLock()
+	strategies := append([]InterceptStrategy(nil), c.interceptStrategies...)
+	c.mu.RUnlock()
+
+	var err error
+	for i := len(strategies) - 1; i >= 0; i-- {
+		if target, err = strategies[i].WrapProcessor(c, info, target); err != nil {
+			return nil, err
+		}
+	}
+	return target, nil
+}
+
+// applyProducerInterceptors wraps producer with every registered interceptor.
+func (c *DefaultContext) applyProducerInterceptors(ep Endpoint, producer Producer) (Producer, error) {
+	c.mu.RLock()
+	interceptors := append([]ProducerInterceptor(nil), c.producerInterceptors...)
+	c.mu.RUnlock()
+
+	var err error
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		if producer, err = interceptors[i].WrapProducer(c, ep, producer); err != nil {
+			return nil, err
+		}
+	}
+	return producer, nil
+}
@@ -0,0 +1,224 @@
+package core
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// BreadcrumbIDHeader carries an ID that follows a message across routes and
+// hops that keep headers, such as seda:. It defaults to the ID of the
+// exchange that entered the first route.
+const BreadcrumbIDHeader = "breadcrumbId"
+
+// MDC field names attached to exchange log entries.
+const (
+	LogFieldLogger       = "logger"
+	LogFieldRouteID      = "route_id"
+	LogFieldStepID       = "step_id"
+	LogFieldExchangeID   = "exchange_id"
+	LogFieldBreadcrumbID = "breadcrumb_id"
+	LogFieldEndpointURI  = "endpoint_uri"
+	LogFieldHistory      = "history"
+)
+
+// MaskedValue replaces the values of sensitive headers, properties and
+// endpoint parameters in logs.
+const MaskedValue = "xxxxxx"
+
+// sensitiveKeys are matched case-insensitively as substrings of names.
+var sensitiveKeys = []string{
+	"authorization", "password", "passwd", "passphrase", "secret", "token",
+	"apikey", "api-key", "api_key", "cookie", "credential", "private-key",
+}
+
+// IsSensitive reports whether a header, property or endpoint parameter
+// named name should be masked when logged.
+func IsSensitive(name string) bool {
+	lower := strings.ToLower(name)
+	for _, key := range sensitiveKeys {
+		if strings.Contains(lower, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// RouteLogCategory is the category whose level applies to exchange logs
+// written while the exchange is in the given route, e.g. "route.orders".
+func RouteLogCategory(routeID string) string {
+	return "route." + routeID
+}
+
+type logLevel struct {
+	pattern string
+	level   logrus.Level
+}
+
+// logSupport owns the context logger. Levels can be overridden per category
+// (e.g. "component.file", "route.orders" or a pattern such as "component.*");
+// one logger per distinct level shares the base logger's output, formatter
+// and hooks.
+type logSupport struct {
+	mu      sync.RWMutex
+	base    *logrus.Logger
+	levels  []logLevel
+	byLevel map[logrus.Level]*logrus.Logger
+}
+
+func (l *logSupport) setLogger(base *logrus.Logger) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.base = base
+	l.byLevel = nil
+}
+
+func (l *logSupport) setLevel(pattern string, level logrus.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range l.levels {
+		if l.levels[i].pattern == pattern {
+			l.levels[i].level = level
+			return
+		}
+	}
+	l.levels = append(l.levels, logLevel{pattern: pattern, level: level})
+}
+
+// levelFor returns the configured level for the first of categories that
+// has one: an exact match wins over the first matching pattern.
+func (l *logSupport) levelFor(categories ...string) (logrus.Level, bool) {
+	for _, category := range categories {
+		if category == "" {
+			continue
+		}
+		for _, lv := range l.levels {
+			if lv.pattern == category {
+				return lv.level, true
+			}
+		}
+		for _, lv := range l.levels {
+			if MatchPattern(category, lv.pattern) {
+				return lv.level, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (l *logSupport) logger(categories ...string) *logrus.Logger {
+	l.mu.RLock()
+	base := l.base
+	level, ok := l.levelFor(categories...)
+	cached := l.byLevel[level]
+	l.mu.RUnlock()
+
+	if base == nil {
+		l.mu.Lock()
+		if l.base == nil {
+			l.base = logrus.New()
+		}
+		base = l.base
+		l.mu.Unlock()
+	}
+	if !ok || level == base.GetLevel() {
+		return base
+	}
+	if cached != nil {
+		return cached
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.byLevel == nil {
+		l.byLevel = make(map[logrus.Level]*logrus.Logger)
+	}
+	if cached = l.byLevel[level]; cached == nil {
+		cached = &logrus.Logger{
+			Out:          base.Out,
+			Hooks:        base.Hooks,
+			Formatter:    base.Formatter,
+			ReportCaller: base.ReportCaller,
+			Level:        level,
+			ExitFunc:     base.ExitFunc,
+		}
+		l.byLevel[level] = cached
+	}
+	return cached
+}
+
+// SetLogger replaces the logger used by the context, its components and
+// processors. Category levels set with SetLogLevel still apply.
+func (c *DefaultContext) SetLogger(l *logrus.Logger) {
+	c.logs.setLogger(l)
+}
+
+// SetLogLevel overrides the level for categories matching pattern (exact,
+// wildcard or regex, see MatchPattern). Use RouteLogCategory to target the
+// exchange logs of a route.
+func (c *DefaultContext) SetLogLevel(pattern string, level logrus.Level) {
+	c.logs.setLevel(pattern, level)
+}
+
+// Logger returns the logger for category, tagged with the category name.
+func (c *DefaultContext) Logger(category string) *logrus.Entry {
+	return logrus.NewEntry(c.logs.logger(category)).WithField(LogFieldLogger, category)
+}
+
+// exchangeLogger returns the logger for category with the exchange's MDC
+// fields. A level set for the exchange's current route takes precedence.
+func (c *DefaultContext) exchangeLogger(category string, exchange *Exchange) *logrus.Entry {
+	var routeCategory string
+	if id := exchange.CurrentRouteID(); id != "" {
+		routeCategory = RouteLogCategory(id)
+	}
+	entry := logrus.NewEntry(c.logs.logger(routeCategory, category)).WithField(LogFieldLogger, category)
+	return entry.WithFields(LogFields(exchange))
+}
+
+// LogFields returns the MDC fields of exchange: its ID, breadcrumb ID and,
+// while it is being routed, the current route, step and route endpoint.
+func LogFields(exchange *Exchange) logrus.Fields {
+	fields := logrus.Fields{LogFieldExchangeID: exchange.ID()}
+	if id, ok := exchange.In().Header(BreadcrumbIDHeader).(string); ok && id != "" {
+		fields[LogFieldBreadcrumbID] = id
+	}
+	if id := exchange.CurrentRouteID(); id != "" {
+		fields[LogFieldRouteID] = id
+	}
+	if id := exchange.CurrentStepID(); id != "" {
+		fields[LogFieldStepID] = id
+	}
+	if uri := exchange.CurrentEndpointURI(); uri != "" {
+		fields[LogFieldEndpointURI] = uri
+	}
+	return fields
+}
+
+// Log returns the logger for category from ctx, falling back to the logrus
+// standard logger when ctx is nil (e.g. components used without a context).
+func Log(ctx Context, category string) *logrus.Entry {
+	if ctx == nil {
+		return logrus.NewEntry(logrus.StandardLogger()).WithField(LogFieldLogger, category)
+	}
+	return ctx.Logger(category)
+}
+
+// LogExchange is Log with the MDC fields of exchange attached.
+func LogExchange(ctx Context, category string, exchange *Exchange) *logrus.Entry {
+	if dc, ok := ctx.(*DefaultContext); ok && dc != nil {
+		return dc.exchangeLogger(category, exchange)
+	}
+	return Log(ctx, category).WithFields(LogFields(exchange))
+}
+
+// LogExchangeFailure logs err for exchange at error level, including its
+// message history when one was recorded.
+func LogExchangeFailure(ctx Context, category string, exchange *Exchange, err error, msg string) {
+	entry := LogExchange(ctx, category, exchange).WithError(err)
+	if history := FormatMessageHistory(exchange); history != "" {
+		entry = entry.WithField(LogFieldHistory, history)
+	}
+	entry.Error(msg)
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// logStepDefinition compiles to a processor that logs at the given level.
+type logStepDefinition struct {
+	ID    string
+	Level logrus.Level
+}
+
+func (d *logStepDefinition) GetID() string     { return d.ID }
+func (d *logStepDefinition) ShortName() string { return "logStep" }
+
+func (d *logStepDefinition) Compile(ctx CompileContext) (Processor, error) {
+	return &logStepProcessor{level: d.Level}, nil
+}
+
+type logStepProcessor struct {
+	level logrus.Level
+}
+
+func (p *logStepProcessor) Process(ctx Context, exchange *Exchange) error {
+	LogExchange(ctx, "test.step", exchange).Log(p.level, "step ran")
+	return nil
+}
+
+func newLoggingContext(t *testing.T, defs ...*RouteDefinition) (*DefaultContext, *test.Hook) {
+	t.Helper()
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.InfoLevel)
+
+	ctx := NewContext()
+	ctx.SetLogger(logger)
+	ctx.RegisterComponent("rec", &recordingComponent{rec: &recorder{}})
+	ctx.SetLoader(&stubLoader{defs: defs})
+	if err := ctx.AddRoutes(nil); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	return ctx, hook
+}
+
+func TestLogExchange_AttachesMDCFields(t *testing.T) {
+	ctx, hook := newLoggingContext(t, &RouteDefinition{
+		ID: "orders", InputURI: "rec:in",
+		Steps: []Compilable{&logStepDefinition{ID: "audit", Level: logrus.InfoLevel}},
+	})
+
+	ex := ctx.NewExchange()
+	if err := ctx.Route("orders").Pipeline.Process(ctx, ex); err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatalf("expected a log entry")
+	}
+	want := logrus.Fields{
+		LogFieldLogger:       "test.step",
+		LogFieldRouteID:      "orders",
+		LogFieldStepID:       "audit",
+		LogFieldExchangeID:   ex.ID(),
+		LogFieldBreadcrumbID: ex.ID(),
+		LogFieldEndpointURI:  "rec:in",
+	}
+	for k, v := range want {
+		if entry.Data[k] != v {
+			t.Errorf("field %s: expected %v, got %v", k, v, entry.Data[k])
+		}
+	}
+	if ex.CurrentRouteID() != "" || ex.CurrentStepID() != "" {
+		t.Errorf("expected route and step to be cleared after processing")
+	}
+}
+
+func TestLogLevels_PerCategoryAndRoute(t *testing.T) {
+	ctx, hook := newLoggingContext(t,
+		&RouteDefinition{ID: "quiet", InputURI: "rec:a", Steps: []Compilable{&logStepDefinition{Level: logrus.DebugLevel}}},
+		&RouteDefinition{ID: "verbose", InputURI: "rec:b", Steps: []Compilable{&logStepDefinition{Level: logrus.DebugLevel}}},
+	)
+
+	ctx.Route("quiet").Pipeline.Process(ctx, ctx.NewExchange())
+	if len(hook.AllEntries()) != 0 {
+		t.Fatalf("expected debug to be filtered at the default level")
+	}
+
+	ctx.SetLogLevel(RouteLogCategory("verbose"), logrus.DebugLevel)
+	ctx.Route("quiet").Pipeline.Process(ctx, ctx.NewExchange())
+	ctx.Route("verbose").Pipeline.Process(ctx, ctx.NewExchange())
+	if n := len(hook.AllEntries()); n != 1 {
+		t.Fatalf("expected only the verbose route to log, got %d entries", n)
+	}
+
+	ctx.SetLogLevel("test.*", logrus.DebugLevel)
+	ctx.Route("quiet").Pipeline.Process(ctx, ctx.NewExchange())
+	if n := len(hook.AllEntries()); n != 2 {
+		t.Errorf("expected the category level to enable debug, got %d entries", n)
+	}
+
+	ctx.SetLogLevel(RouteLogCategory("quiet"), logrus.ErrorLevel)
+	ctx.Route("quiet").Pipeline.Process(ctx, ctx.NewExchange())
+	if n := len(hook.AllEntries()); n != 2 {
+		t.Errorf("expected the route level to take precedence, got %d entries", n)
+	}
+}
+
+func TestLog_NilContextUsesStandardLogger(t *testing.T) {
+	if entry := Log(nil, "component.file"); entry.Logger != logrus.StandardLogger() {
+		t.Errorf("expected the standard logger without a context")
+	}
+}
@@ -1,5 +1,12 @@
 package core
 
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+)
+
 type Message struct {
 	body    interface{}
 	headers map[string]interface{}
@@ -25,13 +32,25 @@ func (m *Message) SetHeader(key string, value interface{}) {
 func (m *Message) Header(key string) interface{} {
 	return m.headers[key]
 }
+func (m *Message) RemoveHeader(key string) {
+	delete(m.headers, key)
+}
 
 type Exchange struct {
-	id         string
-	in         *Message
-	out        *Message
-	err        error
-	properties map[string]interface{}
+	id          string
+	in          *Message
+	out         *Message
+	err         error
+	properties  map[string]interface{}
+	fromRouteID string
+
+	// Where the exchange currently is, for logging and diagnostics.
+	currentRouteID     string
+	currentStepID      string
+	currentEndpointURI string
+	failedStepID       string
+
+	history []MessageHistoryEntry
 }
 
 func NewExchange() *Exchange {
@@ -42,9 +61,54 @@ func NewExchange() *Exchange {
 		properties: make(map[string]interface{}),
 	}
 }
+
+// exchangeIDPrefix makes exchange IDs unique across processes.
+var (
+	exchangeIDPrefix  = newExchangeIDPrefix()
+	exchangeIDCounter atomic.Uint64
+)
+
+func newExchangeIDPrefix() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "camelgo"
+	}
+	return hex.EncodeToString(b)
+}
+
+// NewExchangeID returns a process-unique exchange ID.
+func NewExchangeID() string {
+	return fmt.Sprintf("%s-%d", exchangeIDPrefix, exchangeIDCounter.Add(1))
+}
+
 func (e *Exchange) ID() string {
 	return e.id
 }
+
+// FromRouteID returns the ID of the route that first received the exchange.
+func (e *Exchange) FromRouteID() string {
+	return e.fromRouteID
+}
+func (e *Exchange) SetFromRouteID(id string) {
+	e.fromRouteID = id
+}
+
+// CurrentRouteID returns the ID of the route processing the exchange, or ""
+// outside of a route.
+func (e *Exchange) CurrentRouteID() string {
+	return e.currentRouteID
+}
+
+// CurrentStepID returns the ID of the step processing the exchange.
+func (e *Exchange) CurrentStepID() string {
+	return e.currentStepID
+}
+
+// CurrentEndpointURI returns the input URI of the route processing the
+// exchange.
+func (e *Exchange) CurrentEndpointURI() string {
+	return e.currentEndpointURI
+}
 func (e *Exchange) In() *Message {
 	return e.in
 }
@@ -66,24 +130,52 @@ func (e *Exchange) SetProperty(key string, value interface{}) {
 func (e *Exchange) GetProperty(key string) interface{} {
 	return e.properties[key]
 }
+func (e *Exchange) RemoveProperty(key string) {
+	delete(e.properties, key)
+}
 func (e *Exchange) SetError(err error) {
 	e.err = err
 }
 func (e *Exchange) Error() error {
 	return e.err
 }
+
+// Clone returns a copy of e with an ID of its own, <e's ID>-<n>, so copies
+// sent on concurrently can be told apart. The breadcrumb ID header, set to
+// e's ID when e has none, ties the copy to e.
 func (e *Exchange) Clone() Exchange {
 	clone := &Exchange{
-		id:         e.id + "_clone",
-		in:         &Message{body: e.In().Body(), headers: mapCloner(e.In().Headers())},
-		out:        &Message{body: e.Out().Body(), headers: mapCloner(e.Out().Headers())},
-		properties: make(map[string]interface{}),
+		id:          fmt.Sprintf("%s-%d", e.id, exchangeIDCounter.Add(1)),
+		in:          &Message{body: e.In().Body(), headers: mapCloner(e.In().Headers())},
+		out:         &Message{body: e.Out().Body(), headers: mapCloner(e.Out().Headers())},
+		properties:  make(map[string]interface{}),
+		fromRouteID: e.fromRouteID,
+
+		currentRouteID:     e.currentRouteID,
+		currentStepID:      e.currentStepID,
+		currentEndpointURI: e.currentEndpointURI,
+
+		history: append([]MessageHistoryEntry(nil), e.history...),
 	}
 	for k, v := range e.properties {
 		clone.properties[k] = v
 	}
+	if clone.in.Header(BreadcrumbIDHeader) == nil {
+		clone.in.SetHeader(BreadcrumbIDHeader, e.id)
+	}
 	return *clone
 }
+
+// CopyResults makes the messages, properties and error of source those of
+// e, keeping e's ID and history, e.g. to adopt the aggregated result of
+// copies sent to several endpoints.
+func (e *Exchange) CopyResults(source *Exchange) {
+	if source == e {
+		return
+	}
+	e.in, e.out, e.err = source.in, source.out, source.err
+	e.properties = mapCloner(source.properties)
+}
 func mapCloner(original map[string]interface{}) map[string]interface{} {
 	clone := make(map[string]interface{})
 	for k, v := range original {
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -288,9 +289,16 @@ func TestExchange_Clone(t *testing.T) {
 
 	cloned := original.Clone()
 
-	// Check ID has "_clone" suffix
-	if cloned.ID() != "original_clone" {
-		t.Errorf("expected 'original_clone', got %s", cloned.ID())
+	// Check the clone has an ID of its own, tied to the original by the
+	// breadcrumb
+	if !strings.HasPrefix(cloned.ID(), "original-") {
+		t.Errorf("expected an ID starting with 'original-', got %s", cloned.ID())
+	}
+	if again := original.Clone(); again.ID() == cloned.ID() {
+		t.Errorf("expected every clone to get a unique ID, got %s twice", cloned.ID())
+	}
+	if cloned.In().Header(BreadcrumbIDHeader) != "original" {
+		t.Errorf("expected the breadcrumb to be the original ID, got %v", cloned.In().Header(BreadcrumbIDHeader))
 	}
 
 	// Check message bodies are copied
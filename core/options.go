@@ -0,0 +1,353 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Endpoint options are bound from EndpointConfig.Params onto a struct whose
+// fields are tagged, e.g.
+//
+//	type sedaOptions struct {
+//		Name    string        `option:"path" required:"true" description:"The queue name."`
+//		Size    int           `option:"size" default:"1000" min:"1"`
+//		Level   string        `option:"level" default:"info" enum:"debug,info,warn"`
+//		Timeout time.Duration `option:"timeout" default:"30s"`
+//		Tags    []string      `option:"tags"`
+//		Pool    poolOptions   `prefix:"pool."`
+//	}
+//
+// The tags are:
+//
+//   - option: the parameter name; "path" is the part of the URI after the
+//     scheme. Fields without it are ignored unless they have a prefix tag.
+//   - default: the value used when the parameter is missing.
+//   - required: "true" if the parameter must be given and not be empty.
+//   - enum: the comma separated values a string option accepts, matched
+//     ignoring case.
+//   - min: the smallest value an int option accepts.
+//   - secret: "true" if the value must not be shown, e.g. a password.
+//   - description: documentation shown by the catalog.
+//   - prefix: on a struct field, binds its options with names prefixed.
+//
+// Supported field types are string, bool, the int types, time.Duration
+// (a plain number is milliseconds) and []string (comma separated, or the
+// parameter given several times). Fields of other types are bean options,
+// set from a #name reference to a registry entry of a matching type.
+
+// OptionError is a problem with one endpoint option.
+type OptionError struct {
+	Option string
+	Msg    string
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("option %s: %s", e.Option, e.Msg)
+}
+
+// OptionErrors holds every problem found while binding options, so a URI
+// with several mistakes is reported once.
+type OptionErrors []*OptionError
+
+func (e OptionErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e OptionErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// BindOptions sets the fields of the struct target points to from params,
+// applying defaults and rejecting unknown parameters. All problems are
+// returned together as OptionErrors.
+func BindOptions(params map[string]interface{}, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("options target must be a pointer to a struct, got %T", target)
+	}
+	fields, err := optionFields(v.Elem().Type(), "", nil)
+	if err != nil {
+		return err
+	}
+
+	var errs OptionErrors
+	known := make([]string, 0, len(fields))
+	for _, f := range fields {
+		known = append(known, f.desc.Name)
+		raw, given := params[f.desc.Name]
+		if given && (raw == nil || raw == "") {
+			// An empty value, such as "key: ~" in YAML, is as if not given.
+			given = false
+		}
+		if !given {
+			if f.desc.Required {
+				errs = append(errs, &OptionError{Option: f.desc.Name, Msg: "is required"})
+				continue
+			}
+			if f.desc.Default == "" {
+				continue
+			}
+			raw = f.desc.Default
+		}
+		if msg := f.set(v.Elem().FieldByIndex(f.index), raw); msg != "" {
+			errs = append(errs, &OptionError{Option: f.desc.Name, Msg: msg})
+		}
+	}
+
+	var unknown []string
+	for name := range params {
+		if !contains(known, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		msg := "unknown option"
+		if s := suggest(name, known); s != "" {
+			msg += fmt.Sprintf(", did you mean %s?", s)
+		}
+		errs = append(errs, &OptionError{Option: name, Msg: msg})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// DescribeOptions returns the descriptors of the options of the struct
+// options, or points to, in field order. It panics if the struct has an
+// option of an unsupported type, as that is a programming error.
+func DescribeOptions(options interface{}) []OptionDescriptor {
+	t := reflect.TypeOf(options)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	fields, err := optionFields(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	out := make([]OptionDescriptor, len(fields))
+	for i, f := range fields {
+		out[i] = f.desc
+	}
+	return out
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+type optionField struct {
+	desc  OptionDescriptor
+	min   *int64
+	index []int
+}
+
+func optionFields(t reflect.Type, prefix string, index []int) ([]optionField, error) {
+	var out []optionField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		idx := append(append([]int(nil), index...), i)
+		if p, ok := sf.Tag.Lookup("prefix"); ok && sf.Type.Kind() == reflect.Struct {
+			nested, err := optionFields(sf.Type, prefix+p, idx)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, nested...)
+			continue
+		}
+		name, ok := sf.Tag.Lookup("option")
+		if !ok {
+			continue
+		}
+		f := optionField{index: idx, desc: OptionDescriptor{
+			Name:        prefix + name,
+			Kind:        QueryOption,
+			Default:     sf.Tag.Get("default"),
+			Required:    sf.Tag.Get("required") == "true",
+			Secret:      sf.Tag.Get("secret") == "true",
+			Description: sf.Tag.Get("description"),
+		}}
+		if f.desc.Name == "path" {
+			f.desc.Kind = PathOption
+		}
+		switch {
+		case sf.Type == durationType:
+			f.desc.Type = DurationOption
+		case sf.Type.Kind() == reflect.String:
+			f.desc.Type = StringOption
+			if enum := sf.Tag.Get("enum"); enum != "" {
+				f.desc.Type = EnumOption
+				f.desc.Enum = strings.Split(enum, ",")
+			}
+		case sf.Type.Kind() == reflect.Bool:
+			f.desc.Type = BoolOption
+		case isInt(sf.Type.Kind()):
+			f.desc.Type = IntOption
+			if m, ok := sf.Tag.Lookup("min"); ok {
+				n, err := strconv.ParseInt(m, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("option %s: invalid min %q", f.desc.Name, m)
+				}
+				f.min = &n
+			}
+		case sf.Type.Kind() == reflect.Slice && sf.Type.Elem().Kind() == reflect.String:
+			f.desc.Type = ListOption
+		case isBeanKind(sf.Type.Kind()):
+			f.desc.Type = BeanOption
+		default:
+			return nil, fmt.Errorf("option %s: unsupported type %s", f.desc.Name, sf.Type)
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// set stores raw in field, converting strings as needed, and returns a
+// message describing why it could not.
+func (f optionField) set(field reflect.Value, raw interface{}) string {
+	rv := reflect.ValueOf(raw)
+	if values, ok := raw.([]string); ok && f.desc.Type != ListOption {
+		return fmt.Sprintf("must be given once, got %d values", len(values))
+	}
+	if rv.Type() != reflect.TypeOf("") && rv.Type().AssignableTo(field.Type()) && f.desc.Type != ListOption {
+		field.Set(rv)
+		return f.check(field)
+	}
+	if f.desc.Type == BeanOption {
+		if _, isString := raw.(string); isString {
+			return fmt.Sprintf("must be a reference to a %s in the registry, e.g. #name", field.Type())
+		}
+		return fmt.Sprintf("must be a %s, got a %T", field.Type(), raw)
+	}
+	s := fmt.Sprint(raw)
+	if values, ok := raw.([]string); ok {
+		s = strings.Join(values, ",")
+	}
+	switch f.desc.Type {
+	case StringOption:
+		field.SetString(s)
+	case EnumOption:
+		for _, e := range f.desc.Enum {
+			if strings.EqualFold(s, e) {
+				field.SetString(e)
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of %s, got %q", strings.Join(f.desc.Enum, ", "), s)
+	case BoolOption:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Sprintf("must be true or false, got %q", s)
+		}
+		field.SetBool(b)
+	case IntOption:
+		n, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Sprintf("must be an integer, got %q", s)
+		}
+		field.SetInt(n)
+	case DurationOption:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			ms, msErr := strconv.ParseInt(s, 10, 64)
+			if msErr != nil {
+				return fmt.Sprintf("must be a duration such as 500ms or 5s, got %q", s)
+			}
+			d = time.Duration(ms) * time.Millisecond
+		}
+		field.SetInt(int64(d))
+	case ListOption:
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	}
+	return f.check(field)
+}
+
+// check validates a value that has been set.
+func (f optionField) check(field reflect.Value) string {
+	if f.min != nil && field.Int() < *f.min {
+		return fmt.Sprintf("must be at least %d, got %d", *f.min, field.Int())
+	}
+	if f.desc.Type == EnumOption && !contains(f.desc.Enum, field.String()) {
+		return fmt.Sprintf("must be one of %s, got %q", strings.Join(f.desc.Enum, ", "), field.String())
+	}
+	return ""
+}
+
+func isInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// isBeanKind reports whether values of kind can only come from the registry.
+func isBeanKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Interface, reflect.Pointer, reflect.Func, reflect.Map, reflect.Chan, reflect.Struct:
+		return true
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns the known name closest to name, if it is close enough to
+// be a likely typo.
+func suggest(name string, known []string) string {
+	best, bestDist := "", 3
+	for _, k := range known {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+		if d := editDistance(strings.ToLower(name), strings.ToLower(k)); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type poolOptions struct {
+	Size int `option:"size" default:"4" min:"1"`
+}
+
+type testOptions struct {
+	Name     string        `option:"path" required:"true" description:"The name."`
+	Count    int           `option:"count" default:"10" min:"1"`
+	Enabled  bool          `option:"enabled" default:"true"`
+	Timeout  time.Duration `option:"timeout" default:"5s"`
+	Level    string        `option:"level" default:"info" enum:"debug,info,warn"`
+	Tags     []string      `option:"tags"`
+	Password string        `option:"password" secret:"true"`
+	Pool     poolOptions   `prefix:"pool."`
+	internal string
+}
+
+func TestBindOptions(t *testing.T) {
+	var opts testOptions
+	err := BindOptions(map[string]interface{}{
+		"path":      "orders",
+		"count":     "3",
+		"enabled":   "false",
+		"timeout":   "250",
+		"level":     "WARN",
+		"tags":      "a, b,,c",
+		"pool.size": 8,
+	}, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := testOptions{Name: "orders", Count: 3, Timeout: 250 * time.Millisecond, Level: "warn", Tags: []string{"a", "b", "c"}, Pool: poolOptions{Size: 8}}
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("expected %+v, got %+v", want, opts)
+	}
+}
+
+func TestBindOptions_Defaults(t *testing.T) {
+	var opts testOptions
+	if err := BindOptions(map[string]interface{}{"path": "orders", "count": nil, "password": nil}, &opts); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Count != 10 || opts.Password != "" || !opts.Enabled || opts.Timeout != 5*time.Second || opts.Level != "info" || opts.Pool.Size != 4 || opts.Tags != nil {
+		t.Errorf("expected defaults, got %+v", opts)
+	}
+}
+
+func TestBindOptions_Errors(t *testing.T) {
+	var opts testOptions
+	err := BindOptions(map[string]interface{}{
+		"count":     "0",
+		"enabled":   "maybe",
+		"timeout":   "soon",
+		"level":     "loud",
+		"cuont":     "1",
+		"pool.szie": "1",
+		"colour":    "red",
+	}, &opts)
+
+	var errs OptionErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected OptionErrors, got %v", err)
+	}
+	want := []string{
+		"option path: is required",
+		"option count: must be at least 1, got 0",
+		`option enabled: must be true or false, got "maybe"`,
+		`option timeout: must be a duration such as 500ms or 5s, got "soon"`,
+		`option level: must be one of debug, info, warn, got "loud"`,
+		"option colour: unknown option",
+		"option cuont: unknown option, did you mean count?",
+		"option pool.szie: unknown option, did you mean pool.size?",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), err)
+	}
+	for i, w := range want {
+		if errs[i].Error() != w {
+			t.Errorf("error %d: expected %q, got %q", i, w, errs[i])
+		}
+	}
+	var one *OptionError
+	if !errors.As(err, &one) || one.Option != "path" {
+		t.Errorf("expected the errors to unwrap to OptionError, got %v", one)
+	}
+	if !strings.Contains(err.Error(), "; ") {
+		t.Errorf("expected the errors on one line, got %q", err)
+	}
+}
+
+func TestBindOptions_InvalidTarget(t *testing.T) {
+	if err := BindOptions(nil, testOptions{}); err == nil {
+		t.Error("expected a non-pointer target to fail")
+	}
+	var bad struct {
+		Ratio float64 `option:"ratio"`
+	}
+	if err := BindOptions(nil, &bad); err == nil {
+		t.Error("expected an unsupported field type to fail")
+	}
+}
+
+func TestDescribeOptions(t *testing.T) {
+	descs := DescribeOptions(&testOptions{})
+	names := make([]string, len(descs))
+	for i, d := range descs {
+		names[i] = d.Name
+	}
+	if got := strings.Join(names, ","); got != "path,count,enabled,timeout,level,tags,password,pool.size" {
+		t.Fatalf("unexpected options %s", got)
+	}
+	path, level, pass := descs[0], descs[4], descs[6]
+	if path.Kind != PathOption || !path.Required || path.Description != "The name." {
+		t.Errorf("unexpected path descriptor %+v", path)
+	}
+	if level.Type != EnumOption || level.Default != "info" || len(level.Enum) != 3 {
+		t.Errorf("unexpected level descriptor %+v", level)
+	}
+	if !pass.Secret || descs[3].Type != DurationOption || descs[5].Type != ListOption || descs[1].Type != IntOption {
+		t.Errorf("unexpected descriptors %+v", descs)
+	}
+}
@@ -22,3 +22,10 @@ func (p *PipelineProcessor) Process(ctx Context, exchange *Exchange) error {
 	}
 	return nil
 }
+
+// ProcessorFunc adapts a function to a Processor.
+type ProcessorFunc func(ctx Context, exchange *Exchange) error
+
+func (f ProcessorFunc) Process(ctx Context, exchange *Exchange) error {
+	return f(ctx, exchange)
+}
@@ -0,0 +1,231 @@
+package core
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// DefaultProducerCacheSize is the capacity of producer caches created with
+// a capacity of zero or less.
+const DefaultProducerCacheSize = 1000
+
+// ProducerCacheStatistics is a snapshot of a producer cache's counters.
+type ProducerCacheStatistics struct {
+	Size      int   `json:"size"`
+	Capacity  int   `json:"capacity"`
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Evictions int64 `json:"evictions"`
+}
+
+// ProducerCache keeps the producers of endpoint URIs computed at runtime,
+// as by toD and recipient lists. Producers are created and started on first
+// use. When the cache is full the least recently used producer is evicted
+// and stopped once no exchange is using it any more; stopping the cache
+// evicts all of them. It is safe for concurrent use.
+type ProducerCache struct {
+	ServiceSupport
+
+	ctx      *DefaultContext
+	capacity int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // values are *cachedProducer
+	lru     *list.List               // most recently used first
+	stats   ProducerCacheStatistics
+}
+
+type cachedProducer struct {
+	uri      string
+	producer Producer // as created; the cache starts and stops it
+	wrapped  Producer // with the producer interceptors applied
+	users    int
+	evicted  bool // no longer cached; stopped when users drops to 0
+}
+
+// NewProducerCache returns a cache of at most capacity producers that the
+// context stops together with its other services. Caches created while a
+// route is compiled are stopped with that route instead.
+func (c *DefaultContext) NewProducerCache(capacity int) (*ProducerCache, error) {
+	pc := newProducerCache(c, capacity)
+	if err := c.AddService(pc); err != nil {
+		return nil, err
+	}
+	return pc, nil
+}
+
+func newProducerCache(c *DefaultContext, capacity int) *ProducerCache {
+	if capacity <= 0 {
+		capacity = DefaultProducerCacheSize
+	}
+	return &ProducerCache{
+		ctx:      c,
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+func (pc *ProducerCache) Start(ctx Context) error {
+	return pc.DoStart(nil)
+}
+
+// Stop forgets every cached producer and stops those not in use. A producer
+// still sending an exchange is stopped when its last user releases it.
+func (pc *ProducerCache) Stop(ctx Context) error {
+	return pc.DoStop(func() error {
+		pc.mu.Lock()
+		var idle []*cachedProducer
+		for el := pc.lru.Front(); el != nil; el = el.Next() {
+			e := el.Value.(*cachedProducer)
+			e.evicted = true
+			if e.users == 0 {
+				idle = append(idle, e)
+			}
+		}
+		pc.entries = make(map[string]*list.Element)
+		pc.lru.Init()
+		pc.mu.Unlock()
+
+		var firstErr error
+		for _, e := range idle {
+			if err := StopService(pc.ctx, e.producer); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("failed to stop producer [%s]: %w", pc.ctx.MaskURI(e.uri), err)
+			}
+		}
+		return firstErr
+	})
+}
+
+// Acquire returns the started producer of uri. Call release when the
+// exchange has been sent; a producer evicted meanwhile is stopped then.
+// A stopped cache refuses to hand out producers.
+func (pc *ProducerCache) Acquire(uri string) (prod Producer, release func(), err error) {
+	if pc.stopped() {
+		return nil, nil, fmt.Errorf("producer cache is stopped; cannot send to [%s]", pc.ctx.MaskURI(uri))
+	}
+	resolved, err := pc.ctx.ResolvePlaceholders(uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := NormalizeURI(resolved)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pc.mu.Lock()
+	if e := pc.use(key); e != nil {
+		pc.stats.Hits++
+		pc.mu.Unlock()
+		return e.wrapped, pc.releaser(e), nil
+	}
+	pc.mu.Unlock()
+
+	created, err := pc.create(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pc.mu.Lock()
+	if pc.stopped() {
+		// Stop may already have emptied the cache and would miss created.
+		pc.mu.Unlock()
+		pc.stop(created)
+		return nil, nil, fmt.Errorf("producer cache is stopped; cannot send to [%s]", pc.ctx.MaskURI(key))
+	}
+	if e := pc.use(key); e != nil {
+		// Another exchange created the producer meanwhile.
+		pc.stats.Hits++
+		pc.mu.Unlock()
+		pc.stop(created)
+		return e.wrapped, pc.releaser(e), nil
+	}
+	pc.stats.Misses++
+	created.users = 1
+	pc.entries[key] = pc.lru.PushFront(created)
+	var idle []*cachedProducer
+	for pc.lru.Len() > pc.capacity {
+		el := pc.lru.Back()
+		old := el.Value.(*cachedProducer)
+		pc.lru.Remove(el)
+		delete(pc.entries, old.uri)
+		pc.stats.Evictions++
+		old.evicted = true
+		if old.users == 0 {
+			idle = append(idle, old)
+		}
+	}
+	pc.mu.Unlock()
+
+	for _, old := range idle {
+		pc.stop(old)
+	}
+	return created.wrapped, pc.releaser(created), nil
+}
+
+func (pc *ProducerCache) stopped() bool {
+	st := pc.Status()
+	return st == Stopping || st == Stopped
+}
+
+// use marks the cached producer of key as used and returns it, or nil.
+// pc.mu must be held.
+func (pc *ProducerCache) use(key string) *cachedProducer {
+	el, ok := pc.entries[key]
+	if !ok {
+		return nil
+	}
+	pc.lru.MoveToFront(el)
+	e := el.Value.(*cachedProducer)
+	e.users++
+	return e
+}
+
+func (pc *ProducerCache) create(uri string) (*cachedProducer, error) {
+	ep, err := pc.ctx.GetEndpoint(uri)
+	if err != nil {
+		return nil, err
+	}
+	prod, err := ep.CreateProducer()
+	if err != nil {
+		return nil, fmt.Errorf("endpoint [%s] could not create producer: %w", pc.ctx.MaskURI(uri), err)
+	}
+	if err := StartService(pc.ctx, prod); err != nil {
+		return nil, fmt.Errorf("failed to start producer [%s]: %w", pc.ctx.MaskURI(uri), err)
+	}
+	e := &cachedProducer{uri: uri, producer: prod}
+	if e.wrapped, err = pc.ctx.applyProducerInterceptors(ep, prod); err != nil {
+		pc.stop(e)
+		return nil, err
+	}
+	return e, nil
+}
+
+func (pc *ProducerCache) releaser(e *cachedProducer) func() {
+	return func() {
+		pc.mu.Lock()
+		e.users--
+		stop := e.evicted && e.users == 0
+		pc.mu.Unlock()
+		if stop {
+			pc.stop(e)
+		}
+	}
+}
+
+func (pc *ProducerCache) stop(e *cachedProducer) {
+	if err := StopService(pc.ctx, e.producer); err != nil {
+		pc.ctx.Logger("producer.cache").WithError(err).Warnf("failed to stop producer [%s]", pc.ctx.MaskURI(e.uri))
+	}
+}
+
+// Statistics returns a snapshot of the cache's counters.
+func (pc *ProducerCache) Statistics() ProducerCacheStatistics {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	st := pc.stats
+	st.Size = pc.lru.Len()
+	st.Capacity = pc.capacity
+	return st
+}
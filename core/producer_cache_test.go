@@ -0,0 +1,147 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProducerCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	rec := &recorder{}
+	ctx := NewContext()
+	ctx.RegisterComponent("rec", &recordingComponent{rec: rec})
+	cache, err := ctx.NewProducerCache(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	use := func(uri string) {
+		t.Helper()
+		if _, release, err := cache.Acquire(uri); err != nil {
+			t.Fatalf("Acquire(%s): %v", uri, err)
+		} else {
+			release()
+		}
+	}
+	use("rec:a")
+	use("rec:b")
+	use("rec:a?") // normalizes to rec:a, a hit making b the oldest
+	use("rec:c")  // evicts b
+	use("rec:a")
+	want := []string{"start:producer:rec:a", "start:producer:rec:b", "start:producer:rec:c", "stop:producer:rec:b"}
+	if !reflect.DeepEqual(rec.calls, want) {
+		t.Fatalf("unexpected lifecycle calls:\n got %v\nwant %v", rec.calls, want)
+	}
+
+	// A producer in use when evicted is stopped once released.
+	_, release, err := cache.Acquire("rec:c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	use("rec:d") // evicts a
+	use("rec:e") // evicts c, still in use
+	if last := rec.calls[len(rec.calls)-1]; last != "start:producer:rec:e" {
+		t.Fatalf("expected c to stay started while in use, got %v", rec.calls)
+	}
+	release()
+	if last := rec.calls[len(rec.calls)-1]; last != "stop:producer:rec:c" {
+		t.Fatalf("expected c to stop when released, got %v", rec.calls)
+	}
+
+	st := cache.Statistics()
+	if st.Size != 2 || st.Capacity != 2 || st.Hits != 3 || st.Misses != 5 || st.Evictions != 3 {
+		t.Errorf("unexpected statistics %+v", st)
+	}
+
+	rec.calls = nil
+	if err := cache.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.calls) != 2 || cache.Statistics().Size != 0 {
+		t.Errorf("expected stopping the cache to stop d and e, got %v", rec.calls)
+	}
+	rec.calls = nil
+	if _, _, err := cache.Acquire("rec:a"); err == nil || len(rec.calls) != 0 {
+		t.Errorf("expected a stopped cache to refuse producers, got %v (%v)", err, rec.calls)
+	}
+}
+
+// cachingDefinition compiles to a no-op but keeps the producer cache it was
+// given, as toD does.
+type cachingDefinition struct {
+	cache *ProducerCache
+}
+
+func (d *cachingDefinition) Compile(ctx CompileContext) (Processor, error) {
+	cache, err := ctx.NewProducerCache(0)
+	if err != nil {
+		return nil, err
+	}
+	d.cache = cache
+	return &MockProcessor{}, nil
+}
+
+func TestProducerCache_StopWaitsForUsers(t *testing.T) {
+	rec := &recorder{}
+	ctx := NewContext()
+	ctx.RegisterComponent("rec", &recordingComponent{rec: rec})
+	cache, err := ctx.NewProducerCache(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, release, err := cache.Acquire("rec:busy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, idle, err := cache.Acquire("rec:idle"); err != nil {
+		t.Fatal(err)
+	} else {
+		idle()
+	}
+
+	rec.calls = nil
+	if err := cache.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"stop:producer:rec:idle"}; !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("expected only the idle producer to stop, got %v", rec.calls)
+	}
+	release()
+	if want := []string{"stop:producer:rec:idle", "stop:producer:rec:busy"}; !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("expected the busy producer to stop once released, got %v", rec.calls)
+	}
+}
+
+func TestProducerCache_StoppedWithItsRoute(t *testing.T) {
+	rec := &recorder{}
+	ctx := NewContext()
+	ctx.RegisterComponent("rec", &recordingComponent{rec: rec})
+	def := &cachingDefinition{}
+	ctx.SetLoader(&stubLoader{defs: []*RouteDefinition{{ID: "r1", InputURI: "rec:in", Steps: []Compilable{def}}}})
+	if err := ctx.AddRoutes(nil); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	defer ctx.Stop()
+	if len(ctx.Services()) != 0 {
+		t.Errorf("expected the route's cache not to be a context service, got %v", ctx.Services())
+	}
+
+	_, release, err := def.cache.Acquire("rec:out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	rec.calls = nil
+	if err := ctx.RemoveRoute("r1"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"stop:consumer:rec:in", "stop:producer:rec:out"}
+	if !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("expected removing the route to stop its cached producers:\n got %v\nwant %v", rec.calls, want)
+	}
+	if _, _, err := def.cache.Acquire("rec:out"); err == nil {
+		t.Error("expected the cache of a removed route to refuse producers")
+	}
+}
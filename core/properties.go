@@ -0,0 +1,42 @@
+package core
+
+// PlaceholderResolver replaces {{key}} property placeholders in text.
+type PlaceholderResolver interface {
+	ResolvePlaceholders(text string) (string, error)
+}
+
+// SetPropertiesResolver sets the resolver of property placeholders in
+// endpoint URIs, route IDs and expressions.
+func (c *DefaultContext) SetPropertiesResolver(r PlaceholderResolver) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.properties = r
+}
+
+// PropertiesResolver returns the resolver set with SetPropertiesResolver,
+// or nil.
+func (c *DefaultContext) PropertiesResolver() PlaceholderResolver {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.properties
+}
+
+// ResolvePlaceholders resolves the property placeholders in text. Without a
+// properties resolver text is returned unchanged.
+func (c *DefaultContext) ResolvePlaceholders(text string) (string, error) {
+	r := c.PropertiesResolver()
+	if r == nil {
+		return text, nil
+	}
+	return r.ResolvePlaceholders(text)
+}
+
+// ResolvePlaceholders resolves the property placeholders in text with ctx,
+// which may be a Context or a CompileContext. Contexts that cannot resolve
+// placeholders return text unchanged.
+func ResolvePlaceholders(ctx interface{}, text string) (string, error) {
+	if r, ok := ctx.(PlaceholderResolver); ok && r != nil {
+		return r.ResolvePlaceholders(text)
+	}
+	return text, nil
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry holds named objects (beans) that routes and endpoint options
+// refer to by name, e.g. #dataSource in an endpoint URI or
+// "process: {ref: myProcessor}" in a YAML route.
+type Registry interface {
+	Bind(name string, value interface{})
+	Lookup(name string) (interface{}, bool)
+	// Names returns the bound names in order.
+	Names() []string
+}
+
+// Lookup returns the bean bound under name as a T.
+func Lookup[T any](r Registry, name string) (T, error) {
+	var zero T
+	v, ok := r.Lookup(name)
+	if !ok {
+		return zero, fmt.Errorf("no bean named %q in the registry", name)
+	}
+	t, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("bean %q is a %T, not a %s", name, v, typeOf[T]())
+	}
+	return t, nil
+}
+
+// FindByType returns the beans that are a T, keyed by name.
+func FindByType[T any](r Registry) map[string]T {
+	out := make(map[string]T)
+	for _, name := range r.Names() {
+		v, _ := r.Lookup(name)
+		if t, ok := v.(T); ok {
+			out[name] = t
+		}
+	}
+	return out
+}
+
+// MapRegistry is the default Registry, backed by a map. It is safe for
+// concurrent use.
+type MapRegistry struct {
+	mu    sync.RWMutex
+	beans map[string]interface{}
+}
+
+func NewMapRegistry() *MapRegistry {
+	return &MapRegistry{beans: make(map[string]interface{})}
+}
+
+// Bind binds value under name, replacing any previous binding.
+func (r *MapRegistry) Bind(name string, value interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.beans[name] = value
+}
+
+// Lookup returns the value bound under name.
+func (r *MapRegistry) Lookup(name string) (interface{}, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	v, ok := r.beans[name]
+	return v, ok
+}
+
+// Names returns the bound names, sorted.
+func (r *MapRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.beans))
+	for name := range r.beans {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Registry returns the registry of the context, creating a MapRegistry on
+// first use.
+func (c *DefaultContext) Registry() Registry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.registry == nil {
+		c.registry = NewMapRegistry()
+	}
+	return c.registry
+}
+
+// SetRegistry replaces the registry of the context.
+func (c *DefaultContext) SetRegistry(r Registry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.registry = r
+}
@@ -0,0 +1,37 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry_LookupAndFindByType(t *testing.T) {
+	ctx := NewContext()
+	r := ctx.Registry()
+	if ctx.Registry() != r {
+		t.Fatal("expected the context to keep its registry")
+	}
+	first, second := &MockProcessor{}, &MockProcessor{}
+	r.Bind("b", second)
+	r.Bind("a", first)
+	r.Bind("name", "orders")
+
+	p, err := Lookup[Processor](r, "a")
+	if err != nil || p != first {
+		t.Fatalf("expected the bound processor, got %v, %v", p, err)
+	}
+	if _, err := Lookup[Processor](r, "missing"); err == nil || !strings.Contains(err.Error(), `no bean named "missing"`) {
+		t.Errorf("expected a missing bean error, got %v", err)
+	}
+	if _, err := Lookup[Processor](r, "name"); err == nil || !strings.Contains(err.Error(), `bean "name" is a string, not a core.Processor`) {
+		t.Errorf("expected a type error, got %v", err)
+	}
+
+	procs := FindByType[Processor](r)
+	if len(procs) != 2 || procs["a"] != first || procs["b"] != second {
+		t.Errorf("expected the two processors, got %v", procs)
+	}
+	if names := r.Names(); strings.Join(names, ",") != "a,b,name" {
+		t.Errorf("expected sorted names, got %v", names)
+	}
+}
@@ -5,6 +5,8 @@ package core
 // Route is the "Live" version of a RouteDefinition.
 // It is created during the 'Reification' (Compilation) phase.
 type Route struct {
+	ServiceSupport
+
 	ID       string
 	InputURI string
 
@@ -14,22 +16,125 @@ type Route struct {
 	// Pipeline is the top-level Processor that contains all the logic
 	Pipeline Processor
 
+	// Definition is the blueprint the route was compiled from.
+	Definition *RouteDefinition
+
 	// Reference back to the context for resource access
 	context Context
+
+	stats     *RouteStats
+	stepIDs   map[Compilable]string
+	stepStats map[string]*StepStats
+	producers []Producer       // for the route's static endpoints
+	caches    []*ProducerCache // for toD, recipient lists and routing slips
 }
 
-// Start activates the consumer to begin receiving messages.
-func (r *Route) Start(ctx Context) error {
-	if r.Consumer == nil {
-		return nil // Or return an error if a route must have a consumer
+// Statistics returns a snapshot of the route's counters.
+func (r *Route) Statistics() RouteStatistics {
+	return r.stats.Snapshot()
+}
+
+// StepStatistics returns a snapshot of the counters of step id.
+func (r *Route) StepStatistics(id string) StepStatistics {
+	return r.stepStats[id].Snapshot()
+}
+
+// ResetStatistics clears the route's and its steps' counters.
+func (r *Route) ResetStatistics() {
+	if r.stats != nil {
+		r.stats.Reset()
 	}
-	return r.Consumer.Start(ctx)
+	for _, s := range r.stepStats {
+		s.reset()
+	}
+}
+
+// Start starts the route's producers, then activates the consumer to begin
+// receiving messages.
+func (r *Route) Start(ctx Context) error {
+	return r.DoStart(func() error {
+		for _, prod := range r.producers {
+			if err := StartService(ctx, prod); err != nil {
+				return err
+			}
+		}
+		for _, pc := range r.caches {
+			if err := pc.Start(ctx); err != nil {
+				return err
+			}
+		}
+		if r.Consumer != nil {
+			if err := r.Consumer.Start(ctx); err != nil {
+				return err
+			}
+		}
+		if EventEnabled(ctx, RouteStartedEvent) {
+			ctx.NotifyEvent(NewRouteEvent(RouteStartedEvent, r))
+		}
+		return nil
+	})
 }
 
-// Stop gracefully shuts down the consumer.
+// Stop gracefully shuts down the consumer, then the route's producers,
+// including those cached for endpoints computed at runtime. Every producer
+// is stopped even if an earlier one fails; the first error is returned.
 func (r *Route) Stop(ctx Context) error {
-	if r.Consumer == nil {
+	return r.DoStop(func() error {
+		if r.Consumer != nil {
+			if err := r.Consumer.Stop(ctx); err != nil {
+				return err
+			}
+		}
+		if err := r.stopProducers(ctx); err != nil {
+			return err
+		}
+		if EventEnabled(ctx, RouteStoppedEvent) {
+			ctx.NotifyEvent(NewRouteEvent(RouteStoppedEvent, r))
+		}
 		return nil
+	})
+}
+
+func (r *Route) stopProducers(ctx Context) error {
+	var firstErr error
+	for _, pc := range r.caches {
+		if err := pc.Stop(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
-	return r.Consumer.Stop(ctx)
+	for i := len(r.producers) - 1; i >= 0; i-- {
+		if err := StopService(ctx, r.producers[i]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Suspend pauses the route. Consumers implementing Suspendable are paused in
+// place; other consumers are stopped and started again on Resume, so
+// consumers that would redeliver messages when restarted, such as the file
+// consumer, must implement Suspendable.
+func (r *Route) Suspend(ctx Context) error {
+	return r.DoSuspend(func() error {
+		if r.Consumer == nil {
+			return nil
+		}
+		if s, ok := r.Consumer.(Suspendable); ok {
+			return s.Suspend(ctx)
+		}
+		return r.Consumer.Stop(ctx)
+	})
+}
+
+// Resume continues a suspended route.
+func (r *Route) Resume(ctx Context) error {
+	return r.DoResume(func() error {
+		if r.Consumer == nil {
+			return nil
+		}
+		if s, ok := r.Consumer.(Suspendable); ok {
+			return s.Resume(ctx)
+		}
+		return r.Consumer.Start(ctx)
+	})
 }
@@ -0,0 +1,348 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RouteController decides how the context starts and stops its routes.
+// When no controller is set, DefaultContext starts routes sequentially and
+// aborts on the first failure.
+type RouteController interface {
+	StartRoutes(ctx Context, routes []*Route) error
+	// StartRoute starts a route added while the context is running.
+	StartRoute(ctx Context, route *Route) error
+	StopRoutes(ctx Context, routes []*Route) error
+}
+
+// FailureNotifier is implemented by consumers that can fail after Start has
+// returned (e.g. a read loop hitting an I/O error). The channel yields the
+// error that stopped the consumer.
+type FailureNotifier interface {
+	Failures() <-chan error
+}
+
+// BackOff describes an exponential retry schedule.
+type BackOff struct {
+	Delay       time.Duration // delay before the first retry
+	MaxDelay    time.Duration // upper bound for any single delay; 0 means unbounded
+	Multiplier  float64       // growth factor applied after each attempt
+	MaxAttempts int           // failed attempts before giving up; 0 means retry forever
+}
+
+// NextDelay returns the delay to wait before the given retry attempt (1-based).
+func (b BackOff) NextDelay(attempt int) time.Duration {
+	delay := b.Delay
+	for i := 1; i < attempt; i++ {
+		if b.Multiplier <= 1 {
+			break
+		}
+		delay = time.Duration(float64(delay) * b.Multiplier)
+		if b.MaxDelay > 0 && delay >= b.MaxDelay {
+			break
+		}
+	}
+	if b.MaxDelay > 0 && delay > b.MaxDelay {
+		delay = b.MaxDelay
+	}
+	return delay
+}
+
+// Exhausted reports whether no further attempt should be made after attempt.
+func (b BackOff) Exhausted(attempt int) bool {
+	return b.MaxAttempts > 0 && attempt >= b.MaxAttempts
+}
+
+// SupervisedRouteStatus is the supervisor's view of a single route.
+type SupervisedRouteStatus string
+
+const (
+	SupervisedRouteStarting   SupervisedRouteStatus = "Starting"
+	SupervisedRouteStarted    SupervisedRouteStatus = "Started"
+	SupervisedRouteBackingOff SupervisedRouteStatus = "BackingOff"
+	SupervisedRouteExhausted  SupervisedRouteStatus = "Exhausted"
+	SupervisedRouteStopped    SupervisedRouteStatus = "Stopped"
+)
+
+// RouteRestartState is a snapshot of the restart bookkeeping for one route.
+type RouteRestartState struct {
+	RouteID     string
+	Supervised  bool
+	Status      SupervisedRouteStatus
+	Attempts    int       // failed attempts since the last successful start
+	Restarts    int       // successful restarts after a failure
+	LastError   error     // most recent start failure or consumer crash
+	NextAttempt time.Time // zero unless Status is BackingOff
+}
+
+// SupervisingRouteController starts every route independently and keeps
+// retrying routes that fail to start, or whose consumer crashes later, using
+// an exponential back-off. A failing supervised route never aborts context
+// startup. Routes matching ExcludeRoutes (or not matching a non-empty
+// IncludeRoutes) are started directly and fail the context like the default
+// behaviour. Patterns use MatchPattern against the route ID.
+type SupervisingRouteController struct {
+	BackOff       BackOff
+	IncludeRoutes []string
+	ExcludeRoutes []string
+
+	mu     sync.Mutex
+	states map[string]*RouteRestartState
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewSupervisingRouteController returns a controller retrying after 2s,
+// doubling up to one minute, forever.
+func NewSupervisingRouteController() *SupervisingRouteController {
+	return &SupervisingRouteController{
+		BackOff: BackOff{
+			Delay:      2 * time.Second,
+			MaxDelay:   time.Minute,
+			Multiplier: 2,
+		},
+	}
+}
+
+// IsSupervised reports whether the route with the given ID is supervised.
+func (s *SupervisingRouteController) IsSupervised(routeID string) bool {
+	if len(s.IncludeRoutes) > 0 && !MatchAnyPattern(routeID, s.IncludeRoutes) {
+		return false
+	}
+	return !MatchAnyPattern(routeID, s.ExcludeRoutes)
+}
+
+// StartRoutes starts unsupervised routes first, failing fast, then makes one
+// synchronous attempt per supervised route and schedules retries for those
+// that failed.
+func (s *SupervisingRouteController) StartRoutes(ctx Context, routes []*Route) error {
+	done := make(chan struct{})
+	s.mu.Lock()
+	s.states = make(map[string]*RouteRestartState)
+	s.done = done
+	s.mu.Unlock()
+
+	var supervised []*Route
+	for _, r := range routes {
+		if r == nil {
+			continue
+		}
+		if !s.IsSupervised(r.ID) {
+			if err := s.startUnsupervised(ctx, r); err != nil {
+				return err
+			}
+			continue
+		}
+		supervised = append(supervised, r)
+	}
+
+	for _, r := range supervised {
+		s.wg.Add(1)
+		s.startSupervised(ctx, r, done)
+	}
+	return nil
+}
+
+// StartRoute starts a route added while the context is running, such as a
+// reloaded one, and supervises it like the routes given to StartRoutes.
+func (s *SupervisingRouteController) StartRoute(ctx Context, r *Route) error {
+	s.mu.Lock()
+	if s.states == nil {
+		s.states = make(map[string]*RouteRestartState)
+	}
+	done := s.done
+	supervised := done != nil && s.IsSupervised(r.ID)
+	if supervised {
+		// Added under s.mu so StopRoutes, which clears s.done first, waits for it.
+		s.wg.Add(1)
+	}
+	s.mu.Unlock()
+
+	if !supervised {
+		return s.startUnsupervised(ctx, r)
+	}
+	s.startSupervised(ctx, r, done)
+	return nil
+}
+
+// startUnsupervised starts r directly, failing like the default behaviour.
+func (s *SupervisingRouteController) startUnsupervised(ctx Context, r *Route) error {
+	s.setState(r.ID, &RouteRestartState{RouteID: r.ID, Status: SupervisedRouteStarting})
+	if err := r.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start route %s: %w", r.ID, err)
+	}
+	s.update(r.ID, func(st *RouteRestartState) { st.Status = SupervisedRouteStarted })
+	return nil
+}
+
+// startSupervised makes one synchronous attempt to start r and leaves
+// retrying and watching it to a goroutine. The caller has added it to s.wg.
+func (s *SupervisingRouteController) startSupervised(ctx Context, r *Route, done <-chan struct{}) {
+	st := &RouteRestartState{RouteID: r.ID, Supervised: true, Status: SupervisedRouteStarting}
+	s.setState(r.ID, st)
+
+	err := r.Start(ctx)
+	if err == nil {
+		s.update(r.ID, func(st *RouteRestartState) { st.Status = SupervisedRouteStarted })
+	} else {
+		s.update(r.ID, func(st *RouteRestartState) {
+			st.Attempts = 1
+			st.LastError = err
+		})
+	}
+	go s.supervise(ctx, r, err == nil, done)
+}
+
+// StopRoutes cancels pending restarts and stops routes in reverse order.
+func (s *SupervisingRouteController) StopRoutes(ctx Context, routes []*Route) error {
+	s.mu.Lock()
+	if s.done != nil {
+		close(s.done)
+		s.done = nil
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+
+	var firstErr error
+	for i := len(routes) - 1; i >= 0; i-- {
+		r := routes[i]
+		if r == nil {
+			continue
+		}
+		if err := r.Stop(ctx); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to stop route %s: %w", r.ID, err)
+		}
+		s.update(r.ID, func(st *RouteRestartState) {
+			st.Status = SupervisedRouteStopped
+			st.NextAttempt = time.Time{}
+		})
+	}
+	return firstErr
+}
+
+// RestartState returns the restart bookkeeping for a route.
+func (s *SupervisingRouteController) RestartState(routeID string) (RouteRestartState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.states[routeID]
+	if !ok {
+		return RouteRestartState{}, false
+	}
+	return *st, true
+}
+
+// RestartStates returns the restart bookkeeping of all known routes.
+func (s *SupervisingRouteController) RestartStates() []RouteRestartState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]RouteRestartState, 0, len(s.states))
+	for _, st := range s.states {
+		out = append(out, *st)
+	}
+	return out
+}
+
+// supervise retries a failed route and watches a started one for crashes
+// until the controller is stopped or the back-off is exhausted.
+func (s *SupervisingRouteController) supervise(ctx Context, r *Route, started bool, done <-chan struct{}) {
+	defer s.wg.Done()
+
+	for {
+		if started {
+			crashed, err := s.watch(r, done)
+			if !crashed {
+				return
+			}
+			_ = r.Stop(ctx)
+			s.update(r.ID, func(st *RouteRestartState) {
+				st.Attempts = 0
+				st.LastError = err
+			})
+		}
+
+		st, _ := s.RestartState(r.ID)
+		if s.BackOff.Exhausted(st.Attempts) {
+			s.update(r.ID, func(st *RouteRestartState) {
+				st.Status = SupervisedRouteExhausted
+				st.NextAttempt = time.Time{}
+			})
+			return
+		}
+
+		delay := s.BackOff.NextDelay(max(st.Attempts, 1))
+		s.update(r.ID, func(st *RouteRestartState) {
+			st.Status = SupervisedRouteBackingOff
+			st.NextAttempt = time.Now().Add(delay)
+		})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if removed(ctx, r) {
+			s.update(r.ID, func(st *RouteRestartState) {
+				st.Status = SupervisedRouteStopped
+				st.NextAttempt = time.Time{}
+			})
+			return
+		}
+		if err := r.Start(ctx); err != nil {
+			s.update(r.ID, func(st *RouteRestartState) {
+				st.Attempts++
+				st.LastError = err
+			})
+			started = false
+			continue
+		}
+
+		s.update(r.ID, func(st *RouteRestartState) {
+			if st.LastError != nil {
+				st.Restarts++
+			}
+			st.Status = SupervisedRouteStarted
+			st.Attempts = 0
+			st.NextAttempt = time.Time{}
+		})
+		started = true
+	}
+}
+
+// removed reports whether r is no longer one of the context's routes, as
+// after RemoveRoute or a reload, so it must not be restarted.
+func removed(ctx Context, r *Route) bool {
+	routes, ok := ctx.(interface{ Route(id string) *Route })
+	return ok && routes.Route(r.ID) != r
+}
+
+// watch blocks until the route's consumer reports a failure or done is closed.
+func (s *SupervisingRouteController) watch(r *Route, done <-chan struct{}) (bool, error) {
+	fn, ok := r.Consumer.(FailureNotifier)
+	if !ok {
+		return false, nil
+	}
+	select {
+	case err, open := <-fn.Failures():
+		return open, err
+	case <-done:
+		return false, nil
+	}
+}
+
+func (s *SupervisingRouteController) setState(routeID string, st *RouteRestartState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[routeID] = st
+}
+
+func (s *SupervisingRouteController) update(routeID string, fn func(st *RouteRestartState)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.states[routeID]; ok {
+		fn(st)
+	}
+}
@@ -0,0 +1,273 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyConsumer fails its first FailStarts starts and can be crashed on demand.
+type flakyConsumer struct {
+	mu         sync.Mutex
+	FailStarts int
+	starts     int
+	stops      int
+	failures   chan error
+}
+
+func newFlakyConsumer(failStarts int) *flakyConsumer {
+	return &flakyConsumer{FailStarts: failStarts, failures: make(chan error, 1)}
+}
+
+func (f *flakyConsumer) Start(ctx Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.starts++
+	if f.starts <= f.FailStarts {
+		return errors.New("not yet")
+	}
+	return nil
+}
+
+func (f *flakyConsumer) Stop(ctx Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stops++
+	return nil
+}
+
+func (f *flakyConsumer) Failures() <-chan error {
+	return f.failures
+}
+
+func (f *flakyConsumer) Starts() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.starts
+}
+
+func testController() *SupervisingRouteController {
+	rc := NewSupervisingRouteController()
+	rc.BackOff = BackOff{Delay: 5 * time.Millisecond, Multiplier: 2, MaxDelay: 20 * time.Millisecond}
+	return rc
+}
+
+func waitForStatus(t *testing.T, rc *SupervisingRouteController, routeID string, want SupervisedRouteStatus) RouteRestartState {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if st, ok := rc.RestartState(routeID); ok && st.Status == want {
+			return st
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	st, _ := rc.RestartState(routeID)
+	t.Fatalf("route %s: expected status %s, got %s", routeID, want, st.Status)
+	return st
+}
+
+func TestBackOff_NextDelay(t *testing.T) {
+	b := BackOff{Delay: time.Second, Multiplier: 2, MaxDelay: 5 * time.Second}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, want := range expected {
+		if got := b.NextDelay(i + 1); got != want {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, want, got)
+		}
+	}
+
+	if b.Exhausted(100) {
+		t.Errorf("expected unlimited attempts when MaxAttempts is 0")
+	}
+	b.MaxAttempts = 3
+	if b.Exhausted(2) || !b.Exhausted(3) {
+		t.Errorf("expected exhaustion at exactly 3 attempts")
+	}
+}
+
+func TestSupervisingRouteController_RetriesFailedStart(t *testing.T) {
+	ctx := NewContext()
+	rc := testController()
+	ctx.SetRouteController(rc)
+
+	flaky := newFlakyConsumer(2)
+	ctx.routes = []*Route{{ID: "flaky", Consumer: flaky}}
+
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("expected context to start despite failing route, got %v", err)
+	}
+	defer ctx.Stop()
+
+	st := waitForStatus(t, rc, "flaky", SupervisedRouteStarted)
+	if flaky.Starts() != 3 {
+		t.Errorf("expected 3 start attempts, got %d", flaky.Starts())
+	}
+	if st.Restarts != 1 || st.Attempts != 0 {
+		t.Errorf("unexpected restart state: %+v", st)
+	}
+}
+
+func TestSupervisingRouteController_ExhaustsAttempts(t *testing.T) {
+	ctx := NewContext()
+	rc := testController()
+	rc.BackOff.MaxAttempts = 3
+	ctx.SetRouteController(rc)
+
+	flaky := newFlakyConsumer(100)
+	healthy := &MockConsumer{}
+	ctx.routes = []*Route{{ID: "broken", Consumer: flaky}, {ID: "healthy", Consumer: healthy}}
+
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	defer ctx.Stop()
+
+	if !healthy.StartCalled {
+		t.Errorf("expected healthy route to be started independently")
+	}
+
+	st := waitForStatus(t, rc, "broken", SupervisedRouteExhausted)
+	if st.Attempts != 3 || flaky.Starts() != 3 {
+		t.Errorf("expected 3 attempts, got state %+v and %d starts", st, flaky.Starts())
+	}
+	if st.LastError == nil {
+		t.Errorf("expected last error to be recorded")
+	}
+}
+
+func TestSupervisingRouteController_RestartsCrashedConsumer(t *testing.T) {
+	ctx := NewContext()
+	rc := testController()
+	ctx.SetRouteController(rc)
+
+	flaky := newFlakyConsumer(0)
+	ctx.routes = []*Route{{ID: "crashy", Consumer: flaky}}
+
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	defer ctx.Stop()
+
+	flaky.failures <- errors.New("read loop died")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for flaky.Starts() < 2 && time.Now().Before(deadline) {
+		time.Sleep(2 * time.Millisecond)
+	}
+	st := waitForStatus(t, rc, "crashy", SupervisedRouteStarted)
+	if st.Restarts != 1 {
+		t.Errorf("expected one restart, got %+v", st)
+	}
+	if st.LastError == nil || st.LastError.Error() != "read loop died" {
+		t.Errorf("expected crash error to be recorded, got %v", st.LastError)
+	}
+}
+
+func TestSupervisingRouteController_ExcludedRouteFailsContext(t *testing.T) {
+	ctx := NewContext()
+	rc := testController()
+	rc.ExcludeRoutes = []string{"critical-*"}
+	ctx.SetRouteController(rc)
+
+	ctx.routes = []*Route{{ID: "critical-orders", Consumer: &MockConsumer{StartErr: NewTestError("boom")}}}
+
+	if err := ctx.Start(); err == nil {
+		t.Fatalf("expected unsupervised route failure to abort start")
+	}
+
+	st, ok := rc.RestartState("critical-orders")
+	if !ok || st.Supervised {
+		t.Errorf("expected route to be tracked as unsupervised, got %+v", st)
+	}
+}
+
+func TestSupervisingRouteController_IncludeExclude(t *testing.T) {
+	rc := NewSupervisingRouteController()
+	rc.IncludeRoutes = []string{"orders-*", "billing"}
+	rc.ExcludeRoutes = []string{"orders-legacy"}
+
+	cases := map[string]bool{
+		"orders-in":     true,
+		"billing":       true,
+		"orders-legacy": false,
+		"shipping":      false,
+	}
+	for id, want := range cases {
+		if got := rc.IsSupervised(id); got != want {
+			t.Errorf("IsSupervised(%q): expected %v, got %v", id, want, got)
+		}
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	cases := []struct {
+		value, pattern string
+		want           bool
+	}{
+		{"file:in.txt", "file:in.txt", true},
+		{"file:in.txt", "file:*", true},
+		{"file:out/a.txt", "file:out*", true},
+		{"direct:a", "file:*", false},
+		{"route-1-x", "route-?-*", true},
+		{"file:in.txt", "file:(in|out)\\.txt", true},
+		{"file:inx.txt", "file:(in|out)\\.txt", false},
+		{"anything", "([", false},
+	}
+	for _, c := range cases {
+		if got := MatchPattern(c.value, c.pattern); got != c.want {
+			t.Errorf("MatchPattern(%q, %q): expected %v, got %v", c.value, c.pattern, c.want, got)
+		}
+	}
+}
+
+func TestSupervisingRouteController_SupervisesRoutesAddedWhileRunning(t *testing.T) {
+	ctx := NewContext()
+	rc := testController()
+	ctx.SetRouteController(rc)
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("unexpected start error: %v", err)
+	}
+	defer ctx.Stop()
+
+	flaky := newFlakyConsumer(2)
+	ctx.RegisterComponent("flaky", &flakyComponent{consumer: flaky})
+	ctx.SetLoader(&stubLoader{defs: []*RouteDefinition{{ID: "late", InputURI: "flaky:in"}}})
+	if err := ctx.AddRoutes(nil); err != nil {
+		t.Fatalf("expected a failing supervised route not to fail AddRoutes, got %v", err)
+	}
+	if st, ok := rc.RestartState("late"); !ok || !st.Supervised || st.LastError == nil {
+		t.Errorf("expected the failed first start to be reported, got %+v", st)
+	}
+
+	st := waitForStatus(t, rc, "late", SupervisedRouteStarted)
+	if flaky.Starts() != 3 || st.Restarts != 1 {
+		t.Errorf("expected 3 start attempts and one restart, got %d and %+v", flaky.Starts(), st)
+	}
+}
+
+// flakyComponent hands out its consumer for every endpoint.
+type flakyComponent struct {
+	consumer *flakyConsumer
+}
+
+func (c *flakyComponent) GetScheme() string { return "flaky" }
+
+func (c *flakyComponent) CreateEndpoint(cfg EndpointConfig) (Endpoint, error) {
+	return &flakyEndpoint{uri: cfg.RawURI, consumer: c.consumer}, nil
+}
+
+type flakyEndpoint struct {
+	uri      string
+	consumer *flakyConsumer
+}
+
+func (e *flakyEndpoint) CreateProducer() (Producer, error) {
+	return nil, errors.New("flaky endpoints only consume")
+}
+
+func (e *flakyEndpoint) CreateConsumer(target Processor) (Consumer, error) {
+	return e.consumer, nil
+}
+
+func (e *flakyEndpoint) GetURI() string { return e.uri }
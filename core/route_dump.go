@@ -0,0 +1,60 @@
+package core
+
+// StepNode is a serializable view of one step of a route definition.
+type StepNode struct {
+	ID       string       `json:"id"`
+	Kind     string       `json:"kind"`
+	URI      string       `json:"uri,omitempty"`
+	Branches []BranchNode `json:"branches,omitempty"`
+}
+
+// BranchNode is a serializable view of a Branch.
+type BranchNode struct {
+	Label string     `json:"label"`
+	Steps []StepNode `json:"steps"`
+}
+
+// RouteDump is a serializable view of a route definition.
+type RouteDump struct {
+	ID       string     `json:"id"`
+	InputURI string     `json:"from"`
+	Steps    []StepNode `json:"steps"`
+}
+
+// Dump describes the route's definition with the step IDs assigned when it
+// was compiled. Secret endpoint options are masked.
+func (r *Route) Dump() RouteDump {
+	d := RouteDump{ID: r.ID, InputURI: MaskURI(r.context, r.InputURI), Steps: []StepNode{}}
+	if r.Definition != nil {
+		d.Steps = r.stepNodes(r.Definition.Steps)
+	}
+	return d
+}
+
+// StepID returns the ID def was given when the route was compiled.
+func (r *Route) StepID(def Compilable) string {
+	if id, ok := r.stepIDs[def]; ok {
+		return id
+	}
+	if d, ok := def.(Identifiable); ok {
+		return d.GetID()
+	}
+	return ""
+}
+
+func (r *Route) stepNodes(defs []Compilable) []StepNode {
+	nodes := make([]StepNode, 0, len(defs))
+	for _, def := range defs {
+		node := StepNode{ID: r.StepID(def), Kind: shortName(def)}
+		if e, ok := def.(EndpointAware); ok {
+			node.URI = MaskURI(r.context, e.EndpointURI())
+		}
+		if b, ok := def.(BranchingDefinition); ok {
+			for _, branch := range b.Branches() {
+				node.Branches = append(node.Branches, BranchNode{Label: branch.Label, Steps: r.stepNodes(branch.Steps)})
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -160,3 +161,41 @@ func (m *MockConsumer) Stop(ctx Context) error {
 	m.StopCalled = true
 	return m.StopErr
 }
+
+// envResolver resolves {{env}} to its value.
+type envResolver string
+
+func (r envResolver) ResolvePlaceholders(text string) (string, error) {
+	return strings.ReplaceAll(text, "{{env}}", string(r)), nil
+}
+
+func TestContext_CompileRouteLeavesDefinitionAlone(t *testing.T) {
+	ctx := NewContext()
+	ctx.RegisterComponent("rec", &recordingComponent{rec: &recorder{}})
+	ctx.SetPropertiesResolver(envResolver("dev"))
+	named := &RouteDefinition{ID: "orders-{{env}}", InputURI: "rec:in"}
+	generated := &RouteDefinition{InputURI: "rec:other"}
+	ctx.SetLoader(&stubLoader{defs: []*RouteDefinition{named, generated}})
+	if err := ctx.AddRoutes(nil); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if ctx.Route("orders-dev") == nil || ctx.Route("route1") == nil {
+		t.Fatalf("expected routes orders-dev and route1, got %v", ctx.Routes())
+	}
+	if named.ID != "orders-{{env}}" || generated.ID != "" {
+		t.Errorf("expected the definitions' IDs untouched, got %q and %q", named.ID, generated.ID)
+	}
+
+	// Compiled again with other properties, the placeholder resolves anew.
+	ctx.SetPropertiesResolver(envResolver("prod"))
+	if err := ctx.RemoveRoute("orders-dev"); err != nil {
+		t.Fatal(err)
+	}
+	ctx.SetLoader(&stubLoader{defs: []*RouteDefinition{named}})
+	if err := ctx.AddRoutes(nil); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if ctx.Route("orders-prod") == nil {
+		t.Errorf("expected route orders-prod, got %v", ctx.Routes())
+	}
+}
@@ -0,0 +1,234 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ServiceStatus is the lifecycle state shared by the context, routes,
+// endpoints, consumers and producers.
+type ServiceStatus int32
+
+const (
+	Initializing ServiceStatus = iota
+	Initialized
+	Starting
+	Started
+	Suspending
+	Suspended
+	Stopping
+	Stopped
+	Failed
+)
+
+var serviceStatusNames = [...]string{
+	Initializing: "Initializing",
+	Initialized:  "Initialized",
+	Starting:     "Starting",
+	Started:      "Started",
+	Suspending:   "Suspending",
+	Suspended:    "Suspended",
+	Stopping:     "Stopping",
+	Stopped:      "Stopped",
+	Failed:       "Failed",
+}
+
+func (s ServiceStatus) String() string {
+	if s >= 0 && int(s) < len(serviceStatusNames) {
+		return serviceStatusNames[s]
+	}
+	return fmt.Sprintf("ServiceStatus(%d)", int32(s))
+}
+
+// Service is the lifecycle contract for anything the context starts and stops.
+// Producers and consumers are services; endpoints may opt in by implementing it.
+type Service interface {
+	Start(ctx Context) error
+	Stop(ctx Context) error
+}
+
+// StatefulService exposes the current lifecycle status of a service.
+type StatefulService interface {
+	Status() ServiceStatus
+}
+
+// Suspendable services can pause work without releasing their resources.
+type Suspendable interface {
+	Suspend(ctx Context) error
+	Resume(ctx Context) error
+}
+
+// Ordered services are started in ascending Order and stopped in reverse.
+// Services that do not implement Ordered have order 0.
+type Ordered interface {
+	Order() int
+}
+
+// LifecycleHook is a callback the context runs at a lifecycle point.
+type LifecycleHook func(ctx Context) error
+
+// ServiceSupport implements the ServiceStatus state machine and is meant to be
+// embedded. The Do* methods serialize transitions and run the supplied
+// function at the right point; Status never blocks on a running transition.
+// The zero value is ready to use and starts in Initializing.
+type ServiceSupport struct {
+	lifecycle sync.Mutex
+	status    atomic.Int32
+}
+
+// Status returns the current lifecycle status.
+func (s *ServiceSupport) Status() ServiceStatus {
+	return ServiceStatus(s.status.Load())
+}
+
+// IsStarted reports whether the service is in the Started state.
+func (s *ServiceSupport) IsStarted() bool {
+	return s.Status() == Started
+}
+
+func (s *ServiceSupport) setStatus(st ServiceStatus) {
+	s.status.Store(int32(st))
+}
+
+// DoInit runs fn once, moving Initializing to Initialized.
+func (s *ServiceSupport) DoInit(fn func() error) error {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+	return s.doInit(fn)
+}
+
+func (s *ServiceSupport) doInit(fn func() error) error {
+	if s.Status() != Initializing {
+		return nil
+	}
+	if fn != nil {
+		if err := fn(); err != nil {
+			s.setStatus(Failed)
+			return err
+		}
+	}
+	s.setStatus(Initialized)
+	return nil
+}
+
+// DoStart moves the service through Starting to Started, running fn in
+// between. Starting an already started service is a no-op. A suspended
+// service must be resumed instead. On error the status becomes Failed.
+func (s *ServiceSupport) DoStart(fn func() error) error {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	switch s.Status() {
+	case Started:
+		return nil
+	case Suspended:
+		return fmt.Errorf("service is suspended; resume it instead")
+	case Initializing:
+		if err := s.doInit(nil); err != nil {
+			return err
+		}
+	}
+
+	s.setStatus(Starting)
+	if fn != nil {
+		if err := fn(); err != nil {
+			s.setStatus(Failed)
+			return err
+		}
+	}
+	s.setStatus(Started)
+	return nil
+}
+
+// DoStop moves the service through Stopping to Stopped, running fn in
+// between. Stopping an already stopped service is a no-op.
+func (s *ServiceSupport) DoStop(fn func() error) error {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	if s.Status() == Stopped {
+		return nil
+	}
+
+	s.setStatus(Stopping)
+	if fn != nil {
+		if err := fn(); err != nil {
+			s.setStatus(Failed)
+			return err
+		}
+	}
+	s.setStatus(Stopped)
+	return nil
+}
+
+// DoSuspend moves a started service through Suspending to Suspended.
+func (s *ServiceSupport) DoSuspend(fn func() error) error {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	switch s.Status() {
+	case Suspended:
+		return nil
+	case Started:
+	default:
+		return fmt.Errorf("cannot suspend service in state %s", s.Status())
+	}
+
+	s.setStatus(Suspending)
+	if fn != nil {
+		if err := fn(); err != nil {
+			s.setStatus(Failed)
+			return err
+		}
+	}
+	s.setStatus(Suspended)
+	return nil
+}
+
+// DoResume moves a suspended service back through Starting to Started.
+func (s *ServiceSupport) DoResume(fn func() error) error {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	switch s.Status() {
+	case Started:
+		return nil
+	case Suspended:
+	default:
+		return fmt.Errorf("cannot resume service in state %s", s.Status())
+	}
+
+	s.setStatus(Starting)
+	if fn != nil {
+		if err := fn(); err != nil {
+			s.setStatus(Failed)
+			return err
+		}
+	}
+	s.setStatus(Started)
+	return nil
+}
+
+// StartService starts svc if it is not already started.
+func StartService(ctx Context, svc Service) error {
+	if st, ok := svc.(StatefulService); ok && st.Status() == Started {
+		return nil
+	}
+	return svc.Start(ctx)
+}
+
+// StopService stops svc if it is not already stopped.
+func StopService(ctx Context, svc Service) error {
+	if st, ok := svc.(StatefulService); ok && st.Status() == Stopped {
+		return nil
+	}
+	return svc.Stop(ctx)
+}
+
+func serviceOrder(svc Service) int {
+	if o, ok := svc.(Ordered); ok {
+		return o.Order()
+	}
+	return 0
+}
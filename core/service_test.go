@@ -0,0 +1,286 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// recorder collects lifecycle calls across services in the order they happen.
+type recorder struct {
+	calls []string
+}
+
+type recordingService struct {
+	ServiceSupport
+	name  string
+	order int
+	rec   *recorder
+}
+
+func (s *recordingService) Start(ctx Context) error {
+	return s.DoStart(func() error {
+		s.rec.calls = append(s.rec.calls, "start:"+s.name)
+		return nil
+	})
+}
+
+func (s *recordingService) Stop(ctx Context) error {
+	return s.DoStop(func() error {
+		s.rec.calls = append(s.rec.calls, "stop:"+s.name)
+		return nil
+	})
+}
+
+func (s *recordingService) Order() int {
+	return s.order
+}
+
+type recordingProducer struct {
+	recordingService
+}
+
+func (p *recordingProducer) Process(ctx Context, exchange *Exchange) error {
+	return nil
+}
+
+type recordingEndpoint struct {
+	recordingService
+	uri string
+}
+
+func (e *recordingEndpoint) CreateProducer() (Producer, error) {
+	return &recordingProducer{recordingService{name: "producer:" + e.uri, rec: e.rec}}, nil
+}
+
+func (e *recordingEndpoint) CreateConsumer(target Processor) (Consumer, error) {
+	return &recordingService{name: "consumer:" + e.uri, rec: e.rec}, nil
+}
+
+func (e *recordingEndpoint) GetURI() string {
+	return e.uri
+}
+
+type recordingComponent struct {
+	rec *recorder
+}
+
+func (c *recordingComponent) GetScheme() string {
+	return "rec"
+}
+
+func (c *recordingComponent) CreateEndpoint(cfg EndpointConfig) (Endpoint, error) {
+	return &recordingEndpoint{recordingService{name: "endpoint:" + cfg.RawURI, rec: c.rec}, cfg.RawURI}, nil
+}
+
+func TestServiceStatus_String(t *testing.T) {
+	if Started.String() != "Started" || Failed.String() != "Failed" {
+		t.Errorf("unexpected status names: %s, %s", Started, Failed)
+	}
+	if ServiceStatus(42).String() != "ServiceStatus(42)" {
+		t.Errorf("unexpected name for unknown status: %s", ServiceStatus(42))
+	}
+}
+
+func TestServiceSupport_Transitions(t *testing.T) {
+	var s ServiceSupport
+	if s.Status() != Initializing {
+		t.Fatalf("expected zero value to be Initializing, got %s", s.Status())
+	}
+
+	if err := s.DoStart(nil); err != nil || s.Status() != Started {
+		t.Fatalf("expected Started, got %s (%v)", s.Status(), err)
+	}
+	if err := s.DoSuspend(nil); err != nil || s.Status() != Suspended {
+		t.Fatalf("expected Suspended, got %s (%v)", s.Status(), err)
+	}
+	if err := s.DoStart(nil); err == nil {
+		t.Errorf("expected start of a suspended service to fail")
+	}
+	if err := s.DoResume(nil); err != nil || s.Status() != Started {
+		t.Fatalf("expected Started after resume, got %s (%v)", s.Status(), err)
+	}
+	if err := s.DoStop(nil); err != nil || s.Status() != Stopped {
+		t.Fatalf("expected Stopped, got %s (%v)", s.Status(), err)
+	}
+	if err := s.DoSuspend(nil); err == nil {
+		t.Errorf("expected suspend of a stopped service to fail")
+	}
+
+	calls := 0
+	s.DoStop(func() error { calls++; return nil })
+	if calls != 0 {
+		t.Errorf("expected stop of a stopped service to be a no-op")
+	}
+
+	if err := s.DoStart(func() error { return errors.New("boom") }); err == nil || s.Status() != Failed {
+		t.Errorf("expected Failed after start error, got %s", s.Status())
+	}
+}
+
+func TestServiceSupport_InitRunsOnce(t *testing.T) {
+	var s ServiceSupport
+	calls := 0
+	init := func() error { calls++; return nil }
+
+	s.DoInit(init)
+	s.DoInit(init)
+	if calls != 1 || s.Status() != Initialized {
+		t.Errorf("expected one init call and Initialized, got %d and %s", calls, s.Status())
+	}
+}
+
+func TestContext_OrderedServiceLifecycle(t *testing.T) {
+	rec := &recorder{}
+	ctx := NewContext()
+	ctx.RegisterComponent("rec", &recordingComponent{rec: rec})
+
+	ctx.OnInit(func(Context) error { rec.calls = append(rec.calls, "hook:init"); return nil })
+	ctx.OnStart(func(Context) error { rec.calls = append(rec.calls, "hook:start"); return nil })
+	ctx.OnStop(func(Context) error { rec.calls = append(rec.calls, "hook:stop"); return nil })
+
+	ctx.AddService(&recordingService{name: "repository", order: 20, rec: rec})
+	ctx.AddService(&recordingService{name: "scheduler", order: 10, rec: rec})
+
+	if _, err := ctx.CreateProducer("rec:out"); err != nil {
+		t.Fatalf("CreateProducer error: %v", err)
+	}
+	ctx.routes = append(ctx.routes, &Route{ID: "r1", Consumer: &recordingService{name: "consumer:rec:in", rec: rec}})
+
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	if ctx.Status() != Started {
+		t.Errorf("expected context Started, got %s", ctx.Status())
+	}
+	if err := ctx.Stop(); err != nil {
+		t.Fatalf("stop error: %v", err)
+	}
+	if ctx.Status() != Stopped {
+		t.Errorf("expected context Stopped, got %s", ctx.Status())
+	}
+
+	expected := []string{
+		"hook:init",
+		"start:scheduler",
+		"start:repository",
+		"start:endpoint:rec:out",
+		"start:producer:rec:out",
+		"start:consumer:rec:in",
+		"hook:start",
+		"hook:stop",
+		"stop:consumer:rec:in",
+		"stop:producer:rec:out",
+		"stop:endpoint:rec:out",
+		"stop:repository",
+		"stop:scheduler",
+	}
+	if !reflect.DeepEqual(rec.calls, expected) {
+		t.Errorf("unexpected lifecycle order:\n got  %v\n want %v", rec.calls, expected)
+	}
+}
+
+func TestContext_ServiceAddedWhileRunningIsStarted(t *testing.T) {
+	rec := &recorder{}
+	ctx := NewContext()
+	ctx.RegisterComponent("rec", &recordingComponent{rec: rec})
+
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	defer ctx.Stop()
+
+	prod, err := ctx.CreateProducer("rec:late")
+	if err != nil {
+		t.Fatalf("CreateProducer error: %v", err)
+	}
+	if prod.(StatefulService).Status() != Started {
+		t.Errorf("expected producer created on a running context to be started")
+	}
+}
+
+// producingDefinition is a "to" step that creates its producer up front.
+type producingDefinition struct {
+	URI string
+}
+
+func (d *producingDefinition) Compile(ctx CompileContext) (Processor, error) {
+	return ctx.CreateProducer(d.URI)
+}
+
+func TestContext_RemoveRouteStopsItsProducers(t *testing.T) {
+	rec := &recorder{}
+	ctx := NewContext()
+	ctx.RegisterComponent("rec", &recordingComponent{rec: rec})
+	ctx.SetLoader(&stubLoader{defs: []*RouteDefinition{{ID: "r1", InputURI: "rec:in", Steps: []Compilable{&producingDefinition{URI: "rec:out"}}}}})
+	if err := ctx.AddRoutes(nil); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	defer ctx.Stop()
+
+	rec.calls = nil
+	if err := ctx.RemoveRoute("r1"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"stop:consumer:rec:in", "stop:producer:rec:out"}
+	if !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("expected removing the route to stop its producer:\n got %v\nwant %v", rec.calls, want)
+	}
+
+	// A route that fails to compile stops the producers it already created.
+	rec.calls = nil
+	ctx.SetLoader(&stubLoader{defs: []*RouteDefinition{{ID: "r2", InputURI: "rec:in", Steps: []Compilable{
+		&producingDefinition{URI: "rec:first"}, &producingDefinition{URI: "nope:x"},
+	}}}})
+	if err := ctx.AddRoutes(nil); err == nil {
+		t.Fatal("expected an unknown component to fail")
+	}
+	if want := []string{"start:endpoint:rec:first", "stop:producer:rec:first"}; !reflect.DeepEqual(rec.calls, want) {
+		t.Errorf("expected the failed route's producer to be stopped, got %v", rec.calls)
+	}
+}
+
+func TestContext_SuspendResumeRoutes(t *testing.T) {
+	ctx := NewContext()
+	consumer := &MockConsumer{}
+	route := &Route{ID: "r1", Consumer: consumer}
+	ctx.routes = append(ctx.routes, route)
+
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	if err := ctx.Suspend(); err != nil {
+		t.Fatalf("suspend error: %v", err)
+	}
+	if ctx.Status() != Suspended || route.Status() != Suspended {
+		t.Errorf("expected context and route Suspended, got %s and %s", ctx.Status(), route.Status())
+	}
+	if !consumer.StopCalled {
+		t.Errorf("expected non-suspendable consumer to be stopped")
+	}
+	if err := ctx.Resume(); err != nil {
+		t.Fatalf("resume error: %v", err)
+	}
+	if route.Status() != Started {
+		t.Errorf("expected route Started after resume, got %s", route.Status())
+	}
+	ctx.Stop()
+}
+
+func TestContext_FailedRouteMarksContextFailed(t *testing.T) {
+	ctx := NewContext()
+	ctx.routes = append(ctx.routes, &Route{ID: "bad", Consumer: &MockConsumer{StartErr: NewTestError("no file")}})
+
+	if err := ctx.Start(); err == nil {
+		t.Fatalf("expected start error")
+	}
+	if ctx.Status() != Failed {
+		t.Errorf("expected context Failed, got %s", ctx.Status())
+	}
+	if err := ctx.Stop(); err != nil {
+		t.Errorf("expected stop of a failed context to clean up, got %v", err)
+	}
+}
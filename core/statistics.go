@@ -0,0 +1,250 @@
+package core
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RouteStatistics is a snapshot of a route's counters. Every exchange the
+// route processes is counted, including those arriving from other routes.
+type RouteStatistics struct {
+	ExchangesTotal     int64         `json:"exchangesTotal"`
+	ExchangesFailed    int64         `json:"exchangesFailed"`
+	ExchangesInflight  int64         `json:"exchangesInflight"`
+	ExchangesFiltered  int64         `json:"exchangesFiltered"`
+	MinProcessingTime  time.Duration `json:"minProcessingTime"`
+	MaxProcessingTime  time.Duration `json:"maxProcessingTime"`
+	MeanProcessingTime time.Duration `json:"meanProcessingTime"`
+	LastProcessingTime time.Duration `json:"lastProcessingTime"`
+	LastExchangeAt     time.Time     `json:"lastExchangeAt,omitempty"`
+}
+
+// RouteStats accumulates the statistics of one route.
+type RouteStats struct {
+	mu        sync.Mutex
+	stats     RouteStatistics
+	totalTime time.Duration
+}
+
+func (s *RouteStats) begin() {
+	s.mu.Lock()
+	s.stats.ExchangesInflight++
+	s.mu.Unlock()
+}
+
+func (s *RouteStats) done(elapsed time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := &s.stats
+	st.ExchangesInflight--
+	st.ExchangesTotal++
+	if failed {
+		st.ExchangesFailed++
+	}
+	if st.ExchangesTotal == 1 || elapsed < st.MinProcessingTime {
+		st.MinProcessingTime = elapsed
+	}
+	if elapsed > st.MaxProcessingTime {
+		st.MaxProcessingTime = elapsed
+	}
+	s.totalTime += elapsed
+	st.MeanProcessingTime = s.totalTime / time.Duration(st.ExchangesTotal)
+	st.LastProcessingTime = elapsed
+	st.LastExchangeAt = time.Now()
+}
+
+// ExchangeFiltered counts an exchange a filter step did not let through.
+// It does nothing on a nil RouteStats.
+func (s *RouteStats) ExchangeFiltered() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.stats.ExchangesFiltered++
+	s.mu.Unlock()
+}
+
+// Snapshot returns the current statistics.
+func (s *RouteStats) Snapshot() RouteStatistics {
+	if s == nil {
+		return RouteStatistics{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Reset clears the counters, keeping the in-flight count.
+func (s *RouteStats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats = RouteStatistics{ExchangesInflight: s.stats.ExchangesInflight}
+	s.totalTime = 0
+}
+
+// StepStatistics is a snapshot of one step's counters.
+type StepStatistics struct {
+	ExchangesTotal  int64 `json:"exchangesTotal"`
+	ExchangesFailed int64 `json:"exchangesFailed"`
+}
+
+// StepStats counts the exchanges processed by one step.
+type StepStats struct {
+	total  atomic.Int64
+	failed atomic.Int64
+}
+
+func (s *StepStats) done(failed bool) {
+	s.total.Add(1)
+	if failed {
+		s.failed.Add(1)
+	}
+}
+
+func (s *StepStats) reset() {
+	s.total.Store(0)
+	s.failed.Store(0)
+}
+
+// Snapshot returns the current counters.
+func (s *StepStats) Snapshot() StepStatistics {
+	if s == nil {
+		return StepStatistics{}
+	}
+	return StepStatistics{ExchangesTotal: s.total.Load(), ExchangesFailed: s.failed.Load()}
+}
+
+// InflightExchange describes an exchange that is still being routed.
+type InflightExchange struct {
+	ExchangeID  string        `json:"exchangeId"`
+	FromRouteID string        `json:"fromRouteId"`
+	StartedAt   time.Time     `json:"startedAt"`
+	Elapsed     time.Duration `json:"elapsed"`
+}
+
+// InflightRepository tracks exchanges from the moment they enter their first
+// route until that route returns.
+type InflightRepository struct {
+	mu        sync.Mutex
+	exchanges map[*Exchange]InflightExchange
+}
+
+func (r *InflightRepository) add(exchange *Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.exchanges == nil {
+		r.exchanges = make(map[*Exchange]InflightExchange)
+	}
+	r.exchanges[exchange] = InflightExchange{
+		ExchangeID:  exchange.ID(),
+		FromRouteID: exchange.FromRouteID(),
+		StartedAt:   time.Now(),
+	}
+}
+
+func (r *InflightRepository) remove(exchange *Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.exchanges, exchange)
+}
+
+// Size returns the number of in-flight exchanges.
+func (r *InflightRepository) Size() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.exchanges)
+}
+
+// Browse returns the in-flight exchanges, longest running first.
+func (r *InflightRepository) Browse() []InflightExchange {
+	r.mu.Lock()
+	out := make([]InflightExchange, 0, len(r.exchanges))
+	for _, e := range r.exchanges {
+		e.Elapsed = time.Since(e.StartedAt)
+		out = append(out, e)
+	}
+	r.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out
+}
+
+// DefaultFailureHistorySize is how many failures a context remembers.
+const DefaultFailureHistorySize = 100
+
+// FailureRecord describes an exchange that failed.
+type FailureRecord struct {
+	ExchangeID   string    `json:"exchangeId"`
+	BreadcrumbID string    `json:"breadcrumbId,omitempty"`
+	RouteID      string    `json:"routeId"`
+	StepID       string    `json:"stepId,omitempty"`
+	Error        string    `json:"error"`
+	FailedAt     time.Time `json:"failedAt"`
+
+	History []MessageHistoryEntry `json:"history,omitempty"`
+}
+
+// FailureHistory keeps the most recent failures in a ring buffer.
+type FailureHistory struct {
+	mu      sync.Mutex
+	size    int
+	records []FailureRecord
+	next    int
+}
+
+// NewFailureHistory returns a history keeping the last size failures.
+func NewFailureHistory(size int) *FailureHistory {
+	if size < 1 {
+		size = DefaultFailureHistorySize
+	}
+	return &FailureHistory{size: size}
+}
+
+func (h *FailureHistory) record(exchange *Exchange, routeID string, err error) {
+	rec := FailureRecord{
+		ExchangeID: exchange.ID(),
+		RouteID:    routeID,
+		StepID:     exchange.failedStepID,
+		Error:      err.Error(),
+		FailedAt:   time.Now(),
+		History:    exchange.MessageHistory(),
+	}
+	if id, ok := exchange.In().Header(BreadcrumbIDHeader).(string); ok {
+		rec.BreadcrumbID = id
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.records) < h.size {
+		h.records = append(h.records, rec)
+		return
+	}
+	h.records[h.next] = rec
+	h.next = (h.next + 1) % h.size
+}
+
+// Recent returns the remembered failures, newest first.
+func (h *FailureHistory) Recent() []FailureRecord {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make([]FailureRecord, 0, len(h.records))
+	for i := len(h.records) - 1; i >= 0; i-- {
+		out = append(out, h.records[(h.next+i)%len(h.records)])
+	}
+	return out
+}
+
+// Clear forgets all failures.
+func (h *FailureHistory) Clear() {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = nil
+	h.next = 0
+}
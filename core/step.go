@@ -0,0 +1,274 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// Identifiable is implemented by definitions that carry a user-assigned step ID.
+type Identifiable interface {
+	GetID() string
+}
+
+// Describable is implemented by definitions that report their EIP short name
+// ("to", "choice", ...). It is used to generate step IDs such as "to1".
+type Describable interface {
+	ShortName() string
+}
+
+// StepProcessor wraps one compiled definition of a route, giving it an ID
+// and emitting step events around it.
+type StepProcessor struct {
+	RouteID   string
+	ID        string
+	Processor Processor
+	Stats     *StepStats // optional
+
+	// History records the step in the exchange's message history, with URI
+	// as the endpoint of steps that send to one.
+	History bool
+	URI     string
+}
+
+func (s *StepProcessor) Process(ctx Context, exchange *Exchange) error {
+	previous := exchange.currentStepID
+	exchange.currentStepID = s.ID
+	defer func() { exchange.currentStepID = previous }()
+
+	if EventEnabled(ctx, StepStartedEvent) {
+		ctx.NotifyEvent(NewStepEvent(StepStartedEvent, exchange, s.RouteID, s.ID, 0, nil))
+	}
+	start := time.Now()
+	entry := -1
+	if s.History {
+		uri := s.URI
+		if uri == "" {
+			uri = exchange.currentEndpointURI
+		}
+		entry = exchange.beginHistory(s.RouteID, s.ID, uri)
+	}
+
+	err := s.Processor.Process(ctx, exchange)
+	failed := err != nil || exchange.Error() != nil
+	if entry >= 0 {
+		failure := err
+		if failure == nil {
+			failure = exchange.Error()
+		}
+		exchange.endHistory(entry, failure)
+	}
+	if failed && exchange.failedStepID == "" {
+		exchange.failedStepID = s.ID
+	}
+	if s.Stats != nil {
+		s.Stats.done(failed)
+	}
+
+	if EventEnabled(ctx, StepCompletedEvent) {
+		failure := err
+		if failure == nil {
+			failure = exchange.Error()
+		}
+		ctx.NotifyEvent(NewStepEvent(StepCompletedEvent, exchange, s.RouteID, s.ID, time.Since(start), failure))
+	}
+	return err
+}
+
+// RouteProcessor is the entry point a route's consumer feeds exchanges into.
+// It tags the exchange with the route and reports completion or failure.
+type RouteProcessor struct {
+	RouteID     string
+	EndpointURI string
+	Pipeline    Processor
+
+	// Optional bookkeeping, set for routes compiled by a DefaultContext.
+	Stats    *RouteStats
+	Inflight *InflightRepository
+	Failures *FailureHistory
+}
+
+func (r *RouteProcessor) Process(ctx Context, exchange *Exchange) error {
+	if exchange.In().Header(BreadcrumbIDHeader) == nil {
+		exchange.In().SetHeader(BreadcrumbIDHeader, exchange.ID())
+	}
+	prevRoute, prevStep, prevURI := exchange.currentRouteID, exchange.currentStepID, exchange.currentEndpointURI
+	exchange.currentRouteID, exchange.currentStepID, exchange.currentEndpointURI = r.RouteID, "", r.EndpointURI
+	defer func() {
+		exchange.currentRouteID, exchange.currentStepID, exchange.currentEndpointURI = prevRoute, prevStep, prevURI
+	}()
+
+	// Only the route that received the exchange first reports its outcome;
+	// routes called along the way (e.g. via direct:) do not.
+	first := exchange.FromRouteID() == ""
+	if first {
+		exchange.SetFromRouteID(r.RouteID)
+		if r.Inflight != nil {
+			r.Inflight.add(exchange)
+			defer r.Inflight.remove(exchange)
+		}
+	}
+	if r.Stats != nil {
+		r.Stats.begin()
+	}
+	start := time.Now()
+
+	err := r.Pipeline.Process(ctx, exchange)
+
+	failure := err
+	if failure == nil {
+		failure = exchange.Error()
+	}
+	if r.Stats != nil {
+		r.Stats.done(time.Since(start), failure != nil)
+	}
+	if !first {
+		return err
+	}
+
+	if failure != nil {
+		if r.Failures != nil {
+			r.Failures.record(exchange, r.RouteID, failure)
+		}
+		if EventEnabled(ctx, ExchangeFailedEvent) {
+			ctx.NotifyEvent(NewExchangeEvent(ExchangeFailedEvent, exchange, failure))
+		}
+	} else if EventEnabled(ctx, ExchangeCompletedEvent) {
+		ctx.NotifyEvent(NewExchangeEvent(ExchangeCompletedEvent, exchange, nil))
+	}
+	return err
+}
+
+// CompileSteps compiles defs into a pipeline. When called while the context
+// compiles a route, each step is wrapped in a StepProcessor with a stable ID;
+// definitions with nested outputs (choice branches, ...) should use it too so
+// their children get IDs as well.
+func CompileSteps(ctx CompileContext, defs []Compilable) (*PipelineProcessor, error) {
+	rc, inRoute := ctx.(*routeCompiler)
+
+	pipeline := &PipelineProcessor{}
+	for _, def := range defs {
+		// IDs are assigned before compiling so nested steps number after
+		// their parent.
+		var stepID string
+		if inRoute {
+			stepID = rc.nextStepID(def)
+		}
+		proc, err := def.Compile(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if inRoute {
+			info := StepInfo{RouteID: rc.routeID, StepID: stepID, Kind: shortName(def), Definition: def}
+			if proc, err = rc.applyInterceptStrategies(info, proc); err != nil {
+				return nil, err
+			}
+			step := &StepProcessor{RouteID: rc.routeID, ID: stepID, Processor: proc, Stats: rc.stepStats(stepID), History: rc.IsMessageHistory()}
+			if e, ok := def.(EndpointAware); ok {
+				step.URI = rc.MaskURI(e.EndpointURI())
+			}
+			proc = step
+		}
+		pipeline.Children = append(pipeline.Children, proc)
+	}
+	return pipeline, nil
+}
+
+// routeCompiler is the CompileContext handed to definitions while a route is
+// compiled. It carries the route ID, the route's statistics, the
+// per-route step ID counters and the producers and producer caches the
+// route owns.
+type routeCompiler struct {
+	*DefaultContext
+	routeID    string
+	routeStats *RouteStats
+	counters   map[string]int
+	stepIDs    map[Compilable]string
+	stats      map[string]*StepStats
+	producers  []Producer
+	caches     []*ProducerCache
+}
+
+func newRouteCompiler(c *DefaultContext, routeID string, routeStats *RouteStats) *routeCompiler {
+	return &routeCompiler{
+		DefaultContext: c,
+		routeID:        routeID,
+		routeStats:     routeStats,
+		counters:       make(map[string]int),
+		stepIDs:        make(map[Compilable]string),
+		stats:          make(map[string]*StepStats),
+	}
+}
+
+// CreateProducer creates a producer owned by the route being compiled,
+// started and stopped with it.
+func (rc *routeCompiler) CreateProducer(uri string) (Producer, error) {
+	ep, err := rc.GetEndpoint(uri)
+	if err != nil {
+		return nil, err
+	}
+	prod, err := ep.CreateProducer()
+	if err != nil {
+		return nil, fmt.Errorf("endpoint [%s] could not create producer: %w", rc.MaskURI(uri), err)
+	}
+	rc.producers = append(rc.producers, prod)
+	return rc.applyProducerInterceptors(ep, prod)
+}
+
+// discard stops the producers created for a route that failed to compile.
+func (rc *routeCompiler) discard() {
+	for _, prod := range rc.producers {
+		if err := StopService(rc.DefaultContext, prod); err != nil {
+			rc.Logger("route").WithError(err).Warnf("failed to stop producer of route %s", rc.routeID)
+		}
+	}
+}
+
+// NewProducerCache returns a cache owned by the route being compiled, started
+// and stopped with it, so removing the route stops its producers.
+func (rc *routeCompiler) NewProducerCache(capacity int) (*ProducerCache, error) {
+	pc := newProducerCache(rc.DefaultContext, capacity)
+	rc.caches = append(rc.caches, pc)
+	return pc, nil
+}
+
+// CompilingRouteStats returns the statistics of the route being compiled
+// with ctx, so processors can add to them, or nil when ctx is not compiling
+// a route, as for interceptors.
+func CompilingRouteStats(ctx CompileContext) *RouteStats {
+	if rc, ok := ctx.(*routeCompiler); ok {
+		return rc.routeStats
+	}
+	return nil
+}
+
+// stepStats returns the counters for stepID, shared by steps with the same
+// user-assigned ID.
+func (rc *routeCompiler) stepStats(id string) *StepStats {
+	s, ok := rc.stats[id]
+	if !ok {
+		s = &StepStats{}
+		rc.stats[id] = s
+	}
+	return s
+}
+
+func (rc *routeCompiler) nextStepID(def Compilable) string {
+	id := ""
+	if d, ok := def.(Identifiable); ok && d.GetID() != "" {
+		id = d.GetID()
+	} else {
+		name := shortName(def)
+		rc.counters[name]++
+		id = fmt.Sprintf("%s%d", name, rc.counters[name])
+	}
+	rc.stepIDs[def] = id
+	return id
+}
+
+// shortName returns the EIP short name of def, or "step" if it has none.
+func shortName(def Compilable) string {
+	if d, ok := def.(Describable); ok && d.ShortName() != "" {
+		return d.ShortName()
+	}
+	return "step"
+}
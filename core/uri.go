@@ -0,0 +1,217 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// EndpointURI is a parsed endpoint URI: scheme:path?name=value&...
+//
+// Parameter values are percent-decoded, with + meaning a space. Values
+// written as RAW(...) or RAW{...} are taken literally, so passwords may
+// contain &, + or %. A parameter given more than once has several values.
+type EndpointURI struct {
+	Scheme string
+	Path   string
+	Params map[string][]string
+
+	// raw marks the parameters whose values were given as RAW(...).
+	raw map[string]bool
+}
+
+// ParseURI parses an endpoint URI. A leading // before the path is
+// dropped, so file://inbox and file:inbox are the same URI.
+func ParseURI(uri string) (*EndpointURI, error) {
+	scheme, rest, ok := strings.Cut(uri, ":")
+	if !ok || scheme == "" {
+		return nil, fmt.Errorf("invalid URI %q, expected scheme:path", uri)
+	}
+	u := &EndpointURI{Scheme: scheme, Params: make(map[string][]string), raw: make(map[string]bool)}
+
+	path, query, _ := strings.Cut(rest, "?")
+	path = strings.TrimPrefix(path, "//")
+	if p, err := url.PathUnescape(path); err == nil {
+		path = p
+	}
+	u.Path = path
+
+	for query != "" {
+		var pair string
+		pair, query = nextParam(query)
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URI %q: parameter %q: %w", uri, key, err)
+		}
+		if raw, ok := rawValue(value); ok {
+			u.Params[name] = append(u.Params[name], raw)
+			u.raw[name] = true
+			continue
+		}
+		v, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URI %q: parameter %s: %w", uri, name, err)
+		}
+		u.Params[name] = append(u.Params[name], v)
+	}
+	return u, nil
+}
+
+// nextParam splits off the first name=value pair of query. An & inside a
+// RAW(...) value does not end the pair.
+func nextParam(query string) (pair, rest string) {
+	if eq := strings.IndexAny(query, "=&"); eq >= 0 && query[eq] == '=' {
+		value := query[eq+1:]
+		for _, delims := range [][2]string{{"RAW(", ")"}, {"RAW{", "}"}} {
+			if !strings.HasPrefix(value, delims[0]) {
+				continue
+			}
+			if end := strings.Index(value, delims[1]+"&"); end >= 0 {
+				return query[:eq+1+end+1], value[end+2:]
+			}
+			return query, ""
+		}
+	}
+	pair, rest, _ = strings.Cut(query, "&")
+	return pair, rest
+}
+
+// rawValue returns the content of a RAW(...) or RAW{...} value.
+func rawValue(value string) (string, bool) {
+	if strings.HasPrefix(value, "RAW(") && strings.HasSuffix(value, ")") {
+		return value[4 : len(value)-1], true
+	}
+	if strings.HasPrefix(value, "RAW{") && strings.HasSuffix(value, "}") {
+		return value[4 : len(value)-1], true
+	}
+	return "", false
+}
+
+// IsRaw reports whether the parameter name was given as RAW(...).
+func (u *EndpointURI) IsRaw(name string) bool {
+	return u.raw[name]
+}
+
+// String returns the normalized URI: the path and values in canonical
+// encoding and the parameters sorted by name. URIs that differ only in
+// parameter order or encoding normalize to the same string.
+func (u *EndpointURI) String() string {
+	return u.format(nil)
+}
+
+// Masked is String with the values of parameters for which secret returns
+// true replaced by MaskedValue.
+func (u *EndpointURI) Masked(secret func(name string) bool) string {
+	return u.format(secret)
+}
+
+func (u *EndpointURI) format(secret func(name string) bool) string {
+	var sb strings.Builder
+	sb.WriteString(u.Scheme)
+	sb.WriteByte(':')
+	sb.WriteString((&url.URL{Path: u.Path}).EscapedPath())
+
+	names := make([]string, 0, len(u.Params))
+	for name := range u.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sep := byte('?')
+	for _, name := range names {
+		for _, v := range u.Params[name] {
+			sb.WriteByte(sep)
+			sep = '&'
+			sb.WriteString(url.QueryEscape(name))
+			sb.WriteByte('=')
+			switch {
+			case secret != nil && secret(name):
+				sb.WriteString(MaskedValue)
+			case u.raw[name]:
+				sb.WriteString("RAW(" + v + ")")
+			default:
+				sb.WriteString(url.QueryEscape(v))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// NormalizeURI returns the normalized form of uri, see EndpointURI.String.
+func NormalizeURI(uri string) (string, error) {
+	u, err := ParseURI(uri)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// uriMasker is implemented by contexts that know which endpoint options
+// of their components are secret.
+type uriMasker interface {
+	MaskURI(uri string) string
+}
+
+// MaskURI masks the secret parameter values of uri for logging. With a
+// context that knows the components' secret options those are masked too;
+// otherwise parameters with sensitive names, such as password or token, are.
+func MaskURI(ctx interface{}, uri string) string {
+	if m, ok := ctx.(uriMasker); ok && m != nil {
+		return m.MaskURI(uri)
+	}
+	u, err := ParseURI(uri)
+	if err != nil {
+		return uri
+	}
+	return u.Masked(IsSensitive)
+}
+
+// MaskURI masks the parameters of uri that its component declares secret
+// or that have sensitive names.
+func (c *DefaultContext) MaskURI(uri string) string {
+	u, err := ParseURI(uri)
+	if err != nil {
+		return uri
+	}
+	desc, _ := c.ComponentDescriptor(u.Scheme)
+	return u.Masked(func(name string) bool {
+		if o, ok := desc.Option(name); ok && o.Secret {
+			return true
+		}
+		return IsSensitive(name)
+	})
+}
+
+// endpointParams converts the parameters of u to EndpointConfig.Params:
+// "path" holds the path, parameters given once a string and parameters
+// given more than once a []string. Values of the form #name or #bean:name
+// are replaced by the bean bound under name in registry.
+func endpointParams(u *EndpointURI, registry Registry) (map[string]interface{}, error) {
+	params := make(map[string]interface{}, len(u.Params)+1)
+	if u.Path != "" {
+		params["path"] = u.Path
+	}
+	for name, values := range u.Params {
+		if len(values) > 1 {
+			params[name] = append([]string(nil), values...)
+			continue
+		}
+		value := values[0]
+		ref, isRef := strings.CutPrefix(value, "#")
+		if !isRef || u.IsRaw(name) {
+			params[name] = value
+			continue
+		}
+		ref = strings.TrimPrefix(ref, "bean:")
+		bean, ok := registry.Lookup(ref)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s: no bean named %q in the registry", name, ref)
+		}
+		params[name] = bean
+	}
+	return params, nil
+}
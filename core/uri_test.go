@@ -0,0 +1,123 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseURI(t *testing.T) {
+	u, err := ParseURI("ftp://host/in%20box?password=RAW(a&b+c%)&user=joe+doe&tag=a&tag=b&empty=&flag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme != "ftp" || u.Path != "host/in box" {
+		t.Errorf("unexpected scheme and path %q %q", u.Scheme, u.Path)
+	}
+	want := map[string][]string{
+		"password": {"a&b+c%"},
+		"user":     {"joe doe"},
+		"tag":      {"a", "b"},
+		"empty":    {""},
+		"flag":     {""},
+	}
+	if !reflect.DeepEqual(u.Params, want) {
+		t.Errorf("expected %v, got %v", want, u.Params)
+	}
+	if !u.IsRaw("password") || u.IsRaw("user") {
+		t.Error("expected only password to be raw")
+	}
+	if got := u.String(); got != "ftp:host/in%20box?empty=&flag=&password=RAW(a&b+c%)&tag=a&tag=b&user=joe+doe" {
+		t.Errorf("unexpected normalized URI %s", got)
+	}
+	if got := u.Masked(IsSensitive); !strings.Contains(got, "password=xxxxxx&") {
+		t.Errorf("expected the password to be masked, got %s", got)
+	}
+
+	raw, _ := ParseURI("x:y?a=RAW{p)q}&b=1")
+	if raw.Params["a"][0] != "p)q" || raw.Params["b"][0] != "1" {
+		t.Errorf("unexpected RAW{} parameters %v", raw.Params)
+	}
+	for _, bad := range []string{"noscheme", ":path", "x:y?a=%zz"} {
+		if _, err := ParseURI(bad); err == nil {
+			t.Errorf("expected %q to fail", bad)
+		}
+	}
+}
+
+func TestNormalizeURI(t *testing.T) {
+	a, _ := NormalizeURI("file:a?x=1&y=2")
+	b, _ := NormalizeURI("file://a?y=2&x=%31")
+	if a != b || a != "file:a?x=1&y=2" {
+		t.Errorf("expected equal normalized URIs, got %s and %s", a, b)
+	}
+}
+
+// optionsComponent binds its endpoint options and keeps the result.
+type optionsComponent struct {
+	recordingComponent
+	created int
+}
+
+type optionsEndpoint struct {
+	recordingEndpoint
+	opts optionsComponentOptions
+}
+
+type optionsComponentOptions struct {
+	Name      string    `option:"path"`
+	Tags      []string  `option:"tags"`
+	Key       string    `option:"key" secret:"true"`
+	Processor Processor `option:"processor"`
+}
+
+func (c *optionsComponent) CreateEndpoint(cfg EndpointConfig) (Endpoint, error) {
+	var opts optionsComponentOptions
+	if err := BindOptions(cfg.Params, &opts); err != nil {
+		return nil, err
+	}
+	c.created++
+	return &optionsEndpoint{recordingEndpoint{recordingService{rec: c.rec}, cfg.RawURI}, opts}, nil
+}
+
+func (c *optionsComponent) Descriptor() ComponentDescriptor {
+	return ComponentDescriptor{Options: DescribeOptions(optionsComponentOptions{})}
+}
+
+func TestGetEndpoint_URIs(t *testing.T) {
+	ctx := NewContext()
+	comp := &optionsComponent{recordingComponent: recordingComponent{rec: &recorder{}}}
+	ctx.RegisterComponent("opt", comp)
+	proc := &MockProcessor{}
+	ctx.Registry().Bind("myProcessor", proc)
+
+	a, err := ctx.GetEndpoint("opt:a?tags=x,y&tags=z&processor=#myProcessor&key=RAW(k&1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ctx.GetEndpoint("opt://a?key=RAW(k&1)&processor=%23myProcessor&tags=x,y&tags=z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b || comp.created != 1 {
+		t.Fatalf("expected equivalent URIs to share an endpoint, created %d", comp.created)
+	}
+	opts := a.(*optionsEndpoint).opts
+	if opts.Name != "a" || !reflect.DeepEqual(opts.Tags, []string{"x", "y", "z"}) || opts.Key != "k&1" || opts.Processor == nil {
+		t.Errorf("unexpected options %+v", opts)
+	}
+	if got := ctx.MaskURI(a.GetURI()); got != "opt:a?key=xxxxxx&processor=%23myProcessor&tags=x%2Cy&tags=z" {
+		t.Errorf("expected the secret option to be masked, got %s", got)
+	}
+
+	_, err = ctx.GetEndpoint("opt:b?processor=#missing&key=RAW(hidden)")
+	if err == nil || !strings.Contains(err.Error(), `no bean named "missing"`) || strings.Contains(err.Error(), "hidden") {
+		t.Errorf("expected a missing bean error without the secret, got %v", err)
+	}
+	if _, err := ctx.GetEndpoint("opt:b?key=1&key=2"); err == nil || !strings.Contains(err.Error(), "must be given once") {
+		t.Errorf("expected a repeated single-valued option to fail, got %v", err)
+	}
+	if _, err := ctx.GetEndpoint("opt:b?processor=myProcessor"); err == nil || !strings.Contains(err.Error(), "#name") {
+		t.Errorf("expected a bean option without # to fail, got %v", err)
+	}
+}
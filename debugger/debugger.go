@@ -0,0 +1,474 @@
+// Package debugger suspends exchanges at route steps so they can be
+// inspected, changed and stepped through while developing routes.
+package debugger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	camellog "github.com/sonyjop/camelgo/component/log"
+	"github.com/sonyjop/camelgo/core"
+)
+
+// ErrNotSuspended is returned when acting on an exchange that is not
+// suspended.
+var ErrNotSuspended = errors.New("exchange is not suspended")
+
+// Action tells a suspended exchange how to continue.
+type Action string
+
+const (
+	// Resume runs until the next breakpoint.
+	Resume Action = "resume"
+	// StepOver suspends at the next step at the same or an outer level,
+	// running nested pipelines (choice branches, ...) without stopping.
+	StepOver Action = "step-over"
+	// StepInto suspends at the very next step, including nested ones and
+	// steps of routes called through direct endpoints.
+	StepInto Action = "step-into"
+)
+
+// Breakpoint suspends exchanges entering matching steps. Empty fields match
+// everything; RouteID is a pattern as understood by core.MatchPattern.
+type Breakpoint struct {
+	ID        string         `json:"id"`
+	RouteID   string         `json:"routeId,omitempty"`
+	StepID    string         `json:"stepId,omitempty"`
+	Condition core.Predicate `json:"-"`
+
+	// Expression is the source of Condition when it was given as a simple
+	// expression, kept for display.
+	Expression string `json:"condition,omitempty"`
+}
+
+func (b *Breakpoint) matches(ctx core.Context, routeID, stepID string, exchange *core.Exchange) bool {
+	if b.RouteID != "" && !core.MatchPattern(routeID, b.RouteID) {
+		return false
+	}
+	if b.StepID != "" && b.StepID != stepID {
+		return false
+	}
+	if b.Condition == nil {
+		return true
+	}
+	ok, err := b.Condition.Evaluate(ctx, exchange)
+	return err == nil && ok
+}
+
+// Suspension describes an exchange waiting before a step.
+type Suspension struct {
+	ExchangeID   string                 `json:"exchangeId"`
+	RouteID      string                 `json:"routeId"`
+	StepID       string                 `json:"stepId"`
+	BreakpointID string                 `json:"breakpointId,omitempty"` // empty when stepping
+	Depth        int                    `json:"depth"`
+	Since        time.Time              `json:"since"`
+	BodyType     string                 `json:"bodyType,omitempty"`
+	Body         interface{}            `json:"body,omitempty"`
+	Headers      map[string]interface{} `json:"headers,omitempty"`
+	Properties   map[string]interface{} `json:"properties,omitempty"`
+}
+
+// stepping values of exchangeState.stepDepth.
+const (
+	notStepping = -1
+	anyDepth    = 0
+)
+
+// exchangeState follows an exchange while it is inside a debugged route:
+// how many routes it passes through (direct calls nest), how deeply its
+// current step is nested and where it should stop next when stepping.
+type exchangeState struct {
+	routes    int
+	depth     int
+	stepDepth int
+}
+
+type suspended struct {
+	exchange     *core.Exchange
+	routeID      string
+	stepID       string
+	breakpointID string
+	depth        int
+	since        time.Time
+	action       chan Action
+}
+
+// Debugger is an InterceptStrategy that suspends the goroutine running an
+// exchange before a step it has a breakpoint for, until a client continues
+// it. Breakpoints and stepping only apply while the debugger is enabled.
+// Stopping the context disables it and resumes every suspended exchange.
+//
+// Suspended exchanges are addressed by exchange ID. Copies of an exchange
+// may share its ID; they are kept apart and continued longest waiting
+// first.
+type Debugger struct {
+	enabled atomic.Bool
+
+	mu          sync.Mutex
+	seq         int
+	breakpoints []*Breakpoint
+	exchanges   map[*core.Exchange]*exchangeState
+	suspended   map[*core.Exchange]*suspended
+	waiters     []chan struct{}
+}
+
+// NewDebugger returns a disabled debugger without breakpoints.
+func NewDebugger() *Debugger {
+	return &Debugger{
+		exchanges: make(map[*core.Exchange]*exchangeState),
+		suspended: make(map[*core.Exchange]*suspended),
+	}
+}
+
+// Install registers the debugger on ctx. Only routes added afterwards can
+// be debugged, so call it before AddRoutes.
+func (d *Debugger) Install(ctx core.Context) {
+	ctx.AddInterceptStrategy(d)
+	ctx.AddEventNotifier(core.NewEventNotifier(func(core.Event) error {
+		d.SetEnabled(false)
+		return nil
+	}, core.ContextStoppingEvent))
+}
+
+// SetEnabled turns the debugger on or off. Turning it off resumes every
+// suspended exchange.
+func (d *Debugger) SetEnabled(enabled bool) {
+	d.enabled.Store(enabled)
+	if !enabled {
+		d.resumeAll()
+	}
+}
+
+// Enabled reports whether breakpoints are active.
+func (d *Debugger) Enabled() bool {
+	return d.enabled.Load()
+}
+
+// AddBreakpoint registers bp, assigning an ID if it has none, and returns
+// the ID.
+func (d *Debugger) AddBreakpoint(bp Breakpoint) string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if bp.ID == "" {
+		d.seq++
+		bp.ID = "bp" + strconv.Itoa(d.seq)
+	}
+	d.breakpoints = append(d.breakpoints, &bp)
+	return bp.ID
+}
+
+// RemoveBreakpoint removes breakpoint id and reports whether it existed.
+func (d *Debugger) RemoveBreakpoint(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, bp := range d.breakpoints {
+		if bp.ID == id {
+			d.breakpoints = append(d.breakpoints[:i:i], d.breakpoints[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Breakpoints returns the registered breakpoints.
+func (d *Debugger) Breakpoints() []Breakpoint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]Breakpoint, len(d.breakpoints))
+	for i, bp := range d.breakpoints {
+		out[i] = *bp
+	}
+	return out
+}
+
+// Suspended returns the suspended exchanges, longest waiting first.
+func (d *Debugger) Suspended() []Suspension {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]Suspension, 0, len(d.suspended))
+	for _, s := range d.suspended {
+		out = append(out, s.describe())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Since.Before(out[j].Since) })
+	return out
+}
+
+// Suspension returns the state of suspended exchange id.
+func (d *Debugger) Suspension(id string) (Suspension, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.find(id)
+	if s == nil {
+		return Suspension{}, false
+	}
+	return s.describe(), true
+}
+
+// find returns the longest waiting suspension of exchange id, or nil.
+// Callers hold d.mu.
+func (d *Debugger) find(id string) *suspended {
+	var found *suspended
+	for ex, s := range d.suspended {
+		if ex.ID() == id && (found == nil || s.since.Before(found.since)) {
+			found = s
+		}
+	}
+	return found
+}
+
+// WaitSuspended blocks until an exchange is suspended or timeout passes and
+// returns the longest waiting one.
+func (d *Debugger) WaitSuspended(timeout time.Duration) (Suspension, bool) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		d.mu.Lock()
+		if len(d.suspended) > 0 {
+			d.mu.Unlock()
+			if all := d.Suspended(); len(all) > 0 {
+				return all[0], true
+			}
+			continue
+		}
+		wake := make(chan struct{})
+		d.waiters = append(d.waiters, wake)
+		d.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-deadline.C:
+			return Suspension{}, false
+		}
+	}
+}
+
+// Modify runs fn on suspended exchange id. The exchange's own goroutine is
+// blocked meanwhile, so fn may change the body, headers and properties
+// freely.
+func (d *Debugger) Modify(id string, fn func(exchange *core.Exchange)) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.find(id)
+	if s == nil {
+		return fmt.Errorf("%w: %s", ErrNotSuspended, id)
+	}
+	fn(s.exchange)
+	return nil
+}
+
+// Continue releases suspended exchange id with action.
+func (d *Debugger) Continue(id string, action Action) error {
+	switch action {
+	case Resume, StepOver, StepInto:
+	default:
+		return fmt.Errorf("unknown debugger action %q", action)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := d.find(id)
+	if s == nil {
+		return fmt.Errorf("%w: %s", ErrNotSuspended, id)
+	}
+	delete(d.suspended, s.exchange)
+	s.action <- action
+	return nil
+}
+
+func (d *Debugger) resumeAll() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for ex, s := range d.suspended {
+		delete(d.suspended, ex)
+		s.action <- Resume
+	}
+	for _, st := range d.exchanges {
+		st.stepDepth = notStepping
+	}
+}
+
+func (d *Debugger) WrapProcessor(ctx core.Context, info core.StepInfo, target core.Processor) (core.Processor, error) {
+	if info.Kind == core.RouteStepKind {
+		return &routeScope{debugger: d, target: target}, nil
+	}
+	return &debugProcessor{debugger: d, routeID: info.RouteID, stepID: info.StepID, target: target}, nil
+}
+
+// state returns the state of exchange, creating it if needed. Callers hold
+// d.mu.
+func (d *Debugger) state(exchange *core.Exchange) *exchangeState {
+	st := d.exchanges[exchange]
+	if st == nil {
+		st = &exchangeState{stepDepth: notStepping}
+		d.exchanges[exchange] = st
+	}
+	return st
+}
+
+// release drops the state of exchange once it has left every route.
+// Callers hold d.mu.
+func (d *Debugger) release(exchange *core.Exchange, st *exchangeState) {
+	if st.routes <= 0 && st.depth <= 0 {
+		delete(d.exchanges, exchange)
+	}
+}
+
+// enter records exchange entering a step and returns the breakpoint to
+// suspend on: a breakpoint ID, "" when stepping, or ok false to run on.
+func (d *Debugger) enter(ctx core.Context, routeID, stepID string, exchange *core.Exchange) (depth int, breakpointID string, ok bool) {
+	d.mu.Lock()
+	st := d.state(exchange)
+	st.depth++
+	depth = st.depth
+	if st.stepDepth != notStepping && (st.stepDepth == anyDepth || depth <= st.stepDepth) {
+		st.stepDepth = notStepping
+		d.mu.Unlock()
+		return depth, "", true
+	}
+	bps := append([]*Breakpoint(nil), d.breakpoints...)
+	d.mu.Unlock()
+
+	// Conditions run without the lock; they may be arbitrary user code.
+	for _, bp := range bps {
+		if bp.matches(ctx, routeID, stepID, exchange) {
+			return depth, bp.ID, true
+		}
+	}
+	return depth, "", false
+}
+
+func (d *Debugger) exit(exchange *core.Exchange) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if st := d.exchanges[exchange]; st != nil {
+		st.depth--
+		d.release(exchange, st)
+	}
+}
+
+// suspend blocks until a client continues the exchange, then arranges the
+// next stop for stepping actions.
+func (d *Debugger) suspend(s *suspended) {
+	d.mu.Lock()
+	if !d.Enabled() {
+		d.mu.Unlock()
+		return
+	}
+	d.suspended[s.exchange] = s
+	for _, wake := range d.waiters {
+		close(wake)
+	}
+	d.waiters = nil
+	d.mu.Unlock()
+
+	action := <-s.action
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if st := d.exchanges[s.exchange]; st != nil {
+		switch action {
+		case StepOver:
+			st.stepDepth = s.depth
+		case StepInto:
+			st.stepDepth = anyDepth
+		default:
+			st.stepDepth = notStepping
+		}
+	}
+}
+
+func (s *suspended) describe() Suspension {
+	ex := s.exchange
+	out := Suspension{
+		ExchangeID:   ex.ID(),
+		RouteID:      s.routeID,
+		StepID:       s.stepID,
+		BreakpointID: s.breakpointID,
+		Depth:        s.depth,
+		Since:        s.since,
+		Body:         ex.In().Body(),
+		Headers:      maskedCopy(ex.In().Headers()),
+		Properties:   maskedCopy(ex.Properties()),
+	}
+	if out.Body != nil {
+		out.BodyType = fmt.Sprintf("%T", out.Body)
+	}
+	return out
+}
+
+// routeScope keeps an exchange's state for as long as it is inside a route,
+// so stepping carries over from one top-level step to the next.
+type routeScope struct {
+	debugger *Debugger
+	target   core.Processor
+}
+
+func (p *routeScope) Process(ctx core.Context, exchange *core.Exchange) error {
+	d := p.debugger
+	if !d.Enabled() {
+		return p.target.Process(ctx, exchange)
+	}
+	d.mu.Lock()
+	d.state(exchange).routes++
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if st := d.exchanges[exchange]; st != nil {
+			st.routes--
+			d.release(exchange, st)
+		}
+	}()
+	return p.target.Process(ctx, exchange)
+}
+
+type debugProcessor struct {
+	debugger *Debugger
+	routeID  string
+	stepID   string
+	target   core.Processor
+}
+
+func (p *debugProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	d := p.debugger
+	if !d.Enabled() {
+		return p.target.Process(ctx, exchange)
+	}
+	depth, bp, stop := d.enter(ctx, p.routeID, p.stepID, exchange)
+	defer d.exit(exchange)
+	if stop {
+		d.suspend(&suspended{
+			exchange:     exchange,
+			routeID:      p.routeID,
+			stepID:       p.stepID,
+			breakpointID: bp,
+			depth:        depth,
+			since:        time.Now(),
+			action:       make(chan Action, 1),
+		})
+	}
+	return p.target.Process(ctx, exchange)
+}
+
+// maskedCopy copies m, masking the values of sensitive keys such as
+// passwords and tokens since suspensions are served over HTTP.
+func maskedCopy(m map[string]interface{}) map[string]interface{} {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if camellog.IsSensitive(k) {
+			out[k] = camellog.MaskedValue
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
@@ -0,0 +1,237 @@
+package debugger
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	camellog "github.com/sonyjop/camelgo/component/log"
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/dsl"
+	"github.com/sonyjop/camelgo/internal/routetest"
+	"github.com/sonyjop/camelgo/language"
+	"github.com/sonyjop/camelgo/management"
+)
+
+// newDebuggedContext starts route "orders", whose steps are setHeader1,
+// filter1 { setHeader2 }, setHeader3.
+func newDebuggedContext(t *testing.T) (*core.DefaultContext, *Debugger) {
+	t.Helper()
+	d := NewDebugger()
+	ctx := routetest.NewContext()
+	d.Install(ctx)
+	routetest.Start(t, ctx, func(b *dsl.BaseRouteBuilder) {
+		b.From("direct:orders").RouteID("orders").
+			SetHeader("a", language.Constant("1")).
+			Filter(language.Equals(language.Constant(true), true)).
+			SetHeader("b", language.Header("a")).
+			End().
+			SetHeader("c", language.Body())
+	})
+	return ctx, d
+}
+
+// sendAsync sends body to the orders route on another goroutine, as the
+// debugger blocks the sending goroutine.
+func sendAsync(t *testing.T, ctx *core.DefaultContext, body string) (*core.Exchange, <-chan error) {
+	t.Helper()
+	p, _ := ctx.CreateProducer("direct:orders")
+	ex := ctx.NewExchange()
+	ex.In().SetBody(body)
+	done := make(chan error, 1)
+	go func() { done <- p.Process(ctx, ex) }()
+	return ex, done
+}
+
+func waitStep(t *testing.T, d *Debugger, want string) Suspension {
+	t.Helper()
+	s, ok := d.WaitSuspended(2 * time.Second)
+	if !ok {
+		t.Fatalf("expected the exchange to be suspended at %s", want)
+	}
+	if s.StepID != want {
+		t.Fatalf("expected suspension at %s, got %s", want, s.StepID)
+	}
+	return s
+}
+
+func waitDone(t *testing.T, done <-chan error) {
+	t.Helper()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("process error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("exchange did not complete")
+	}
+}
+
+func TestDebugger_StepOverAndInto(t *testing.T) {
+	ctx, d := newDebuggedContext(t)
+	d.AddBreakpoint(Breakpoint{StepID: "filter1"})
+	d.SetEnabled(true)
+
+	ex, done := sendAsync(t, ctx, "first")
+	s := waitStep(t, d, "filter1")
+	if s.BreakpointID != "bp1" || s.Depth != 1 || s.Headers["a"] != "1" {
+		t.Errorf("unexpected suspension %+v", s)
+	}
+	d.Continue(ex.ID(), StepOver)
+	waitStep(t, d, "setHeader3")
+	d.Continue(ex.ID(), Resume)
+	waitDone(t, done)
+
+	ex, done = sendAsync(t, ctx, "second")
+	waitStep(t, d, "filter1")
+	d.Continue(ex.ID(), StepInto)
+	if s := waitStep(t, d, "setHeader2"); s.Depth != 2 || s.BreakpointID != "" {
+		t.Errorf("unexpected nested suspension %+v", s)
+	}
+	d.Continue(ex.ID(), StepOver)
+	waitStep(t, d, "setHeader3")
+	d.Continue(ex.ID(), Resume)
+	waitDone(t, done)
+}
+
+func TestDebugger_ModifyAndDisable(t *testing.T) {
+	ctx, d := newDebuggedContext(t)
+	d.AddBreakpoint(Breakpoint{RouteID: "ord*", StepID: "setHeader3", Condition: language.Equals(language.Body(), "stop")})
+	d.SetEnabled(true)
+
+	ex, done := sendAsync(t, ctx, "go")
+	waitDone(t, done)
+	if len(d.Suspended()) != 0 {
+		t.Fatalf("expected the condition to let the exchange pass")
+	}
+
+	ex, done = sendAsync(t, ctx, "stop")
+	waitStep(t, d, "setHeader3")
+	if err := d.Modify(ex.ID(), func(ex *core.Exchange) { ex.In().SetBody("changed") }); err != nil {
+		t.Fatalf("modify error: %v", err)
+	}
+	d.SetEnabled(false)
+	waitDone(t, done)
+	if ex.In().Header("c") != "changed" {
+		t.Errorf("expected the modified body to reach the step, got %v", ex.In().Header("c"))
+	}
+	if err := d.Continue(ex.ID(), Resume); err == nil {
+		t.Errorf("expected an error continuing an exchange that is not suspended")
+	}
+}
+
+func TestDebugger_ManagementAPI(t *testing.T) {
+	ctx, d := newDebuggedContext(t)
+	h := management.NewHandler(ctx, "")
+	d.Mount(h)
+
+	do := func(method, path string, body interface{}, out interface{}) int {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, &buf))
+		if out != nil {
+			json.Unmarshal(rec.Body.Bytes(), out)
+		}
+		return rec.Code
+	}
+
+	var bp Breakpoint
+	if code := do(http.MethodPost, "/debug/breakpoints", map[string]string{"stepId": "setHeader2", "condition": "${header.debug}"}, &bp); code != http.StatusCreated {
+		t.Fatalf("add breakpoint: %d", code)
+	}
+	if code := do(http.MethodPost, "/debug/breakpoints", map[string]string{"condition": "${nope}"}, nil); code != http.StatusBadRequest {
+		t.Errorf("expected an invalid condition to be rejected, got %d", code)
+	}
+	do(http.MethodPost, "/debug/enable", nil, nil)
+
+	p, _ := ctx.CreateProducer("direct:orders")
+	ex := ctx.NewExchange()
+	ex.In().SetHeader("debug", "true")
+	ex.In().SetHeader("Authorization", "Bearer secret")
+	done := make(chan error, 1)
+	go func() { done <- p.Process(ctx, ex) }()
+	waitStep(t, d, "setHeader2")
+
+	var s Suspension
+	do(http.MethodGet, "/debug/suspended/"+ex.ID(), nil, &s)
+	if s.BreakpointID != bp.ID || s.RouteID != "orders" {
+		t.Errorf("unexpected suspension %+v", s)
+	}
+	if s.Headers["Authorization"] != camellog.MaskedValue {
+		t.Errorf("expected the Authorization header to be masked, got %v", s.Headers["Authorization"])
+	}
+	do(http.MethodPost, "/debug/suspended/"+ex.ID()+"/message", map[string]interface{}{"headers": map[string]string{"a": "2"}}, &s)
+	if s.Headers["a"] != "2" {
+		t.Errorf("expected the header to be changed, got %v", s.Headers)
+	}
+	if code := do(http.MethodPost, "/debug/suspended/"+ex.ID()+"/jump", nil, nil); code != http.StatusBadRequest {
+		t.Errorf("expected an unknown action to be rejected, got %d", code)
+	}
+	if code := do(http.MethodPost, "/debug/suspended/"+ex.ID()+"/resume", nil, nil); code != http.StatusNoContent {
+		t.Errorf("resume: %d", code)
+	}
+	waitDone(t, done)
+	if ex.In().Header("b") != "2" {
+		t.Errorf("expected the step to see the changed header, got %v", ex.In().Header("b"))
+	}
+	if ex.In().Header("Authorization") != "Bearer secret" {
+		t.Errorf("expected masking to leave the exchange alone, got %v", ex.In().Header("Authorization"))
+	}
+
+	if code := do(http.MethodDelete, "/debug/breakpoints/"+bp.ID, nil, nil); code != http.StatusNoContent {
+		t.Errorf("delete breakpoint: %d", code)
+	}
+	var st status
+	do(http.MethodGet, "/debug", nil, &st)
+	if !st.Enabled || len(st.Breakpoints) != 0 || len(st.Suspended) != 0 {
+		t.Errorf("unexpected status %+v", st)
+	}
+}
+
+func TestDebugger_ExchangesSharingAnID(t *testing.T) {
+	ctx, d := newDebuggedContext(t)
+	d.AddBreakpoint(Breakpoint{StepID: "filter1"})
+	d.SetEnabled(true)
+
+	p, _ := ctx.CreateProducer("direct:orders")
+	sendCopy := func() <-chan error {
+		ex := core.NewExchange() // every such exchange has the same ID
+		done := make(chan error, 1)
+		go func() { done <- p.Process(ctx, ex) }()
+		return done
+	}
+	waitBoth := func() {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for len(d.Suspended()) < 2 {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected both exchanges to be suspended, got %+v", d.Suspended())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	first, second := sendCopy(), sendCopy()
+	waitBoth()
+	id := d.Suspended()[0].ExchangeID
+	if err := d.Continue(id, Resume); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Continue(id, Resume); err != nil {
+		t.Fatalf("expected the second exchange to stay reachable, got %v", err)
+	}
+	waitDone(t, first)
+	waitDone(t, second)
+
+	first, second = sendCopy(), sendCopy()
+	waitBoth()
+	d.SetEnabled(false)
+	waitDone(t, first)
+	waitDone(t, second)
+}
@@ -0,0 +1,157 @@
+package debugger
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/language"
+	"github.com/sonyjop/camelgo/management"
+)
+
+type status struct {
+	Enabled     bool         `json:"enabled"`
+	Breakpoints []Breakpoint `json:"breakpoints"`
+	Suspended   []Suspension `json:"suspended"`
+}
+
+// breakpointRequest is the body of POST /debug/breakpoints. Condition is a
+// simple expression; the breakpoint matches when it yields true or "true".
+type breakpointRequest struct {
+	ID        string `json:"id"`
+	RouteID   string `json:"routeId"`
+	StepID    string `json:"stepId"`
+	Condition string `json:"condition"`
+}
+
+// messageUpdate is the body of POST /debug/suspended/{exchangeId}/message.
+type messageUpdate struct {
+	Body             *string                `json:"body"`
+	Headers          map[string]interface{} `json:"headers"`
+	RemoveHeaders    []string               `json:"removeHeaders"`
+	Properties       map[string]interface{} `json:"properties"`
+	RemoveProperties []string               `json:"removeProperties"`
+}
+
+// Mount adds the debugger to a management API:
+//
+//	GET    /debug                                   status, breakpoints and suspended exchanges
+//	POST   /debug/enable                            activate breakpoints
+//	POST   /debug/disable                           deactivate and resume everything
+//	GET    /debug/breakpoints                       list breakpoints
+//	POST   /debug/breakpoints                       add {"routeId","stepId","condition"}
+//	DELETE /debug/breakpoints/{id}                  remove a breakpoint
+//	GET    /debug/suspended                         suspended exchanges
+//	GET    /debug/suspended/{exchangeId}            one suspended exchange
+//	POST   /debug/suspended/{exchangeId}/message    change body, headers or properties
+//	POST   /debug/suspended/{exchangeId}/{action}   resume, step-over or step-into
+func (d *Debugger) Mount(h *management.Handler) {
+	h.Handle("GET /debug", http.HandlerFunc(d.getStatus))
+	h.Handle("POST /debug/enable", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d.SetEnabled(true)
+		d.getStatus(w, r)
+	}))
+	h.Handle("POST /debug/disable", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d.SetEnabled(false)
+		d.getStatus(w, r)
+	}))
+	h.Handle("GET /debug/breakpoints", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		management.WriteJSON(w, http.StatusOK, d.Breakpoints())
+	}))
+	h.Handle("POST /debug/breakpoints", http.HandlerFunc(d.addBreakpoint))
+	h.Handle("DELETE /debug/breakpoints/{id}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !d.RemoveBreakpoint(r.PathValue("id")) {
+			management.WriteError(w, http.StatusNotFound, "no breakpoint "+r.PathValue("id"))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	h.Handle("GET /debug/suspended", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		management.WriteJSON(w, http.StatusOK, d.Suspended())
+	}))
+	h.Handle("GET /debug/suspended/{exchangeId}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, ok := d.Suspension(r.PathValue("exchangeId"))
+		if !ok {
+			management.WriteError(w, http.StatusNotFound, ErrNotSuspended.Error())
+			return
+		}
+		management.WriteJSON(w, http.StatusOK, s)
+	}))
+	h.Handle("POST /debug/suspended/{exchangeId}/message", http.HandlerFunc(d.updateMessage))
+	h.Handle("POST /debug/suspended/{exchangeId}/{action}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("exchangeId")
+		if err := d.Continue(id, Action(r.PathValue("action"))); err != nil {
+			writeActionError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+func (d *Debugger) getStatus(w http.ResponseWriter, r *http.Request) {
+	management.WriteJSON(w, http.StatusOK, status{
+		Enabled:     d.Enabled(),
+		Breakpoints: d.Breakpoints(),
+		Suspended:   d.Suspended(),
+	})
+}
+
+func (d *Debugger) addBreakpoint(w http.ResponseWriter, r *http.Request) {
+	var req breakpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		management.WriteError(w, http.StatusBadRequest, "invalid breakpoint: "+err.Error())
+		return
+	}
+	bp := Breakpoint{ID: req.ID, RouteID: req.RouteID, StepID: req.StepID, Expression: req.Condition}
+	if req.Condition != "" {
+		cond, err := language.SimplePredicate(req.Condition)
+		if err != nil {
+			management.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		bp.Condition = cond
+	}
+	bp.ID = d.AddBreakpoint(bp)
+	management.WriteJSON(w, http.StatusCreated, bp)
+}
+
+func (d *Debugger) updateMessage(w http.ResponseWriter, r *http.Request) {
+	var upd messageUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		management.WriteError(w, http.StatusBadRequest, "invalid message update: "+err.Error())
+		return
+	}
+	id := r.PathValue("exchangeId")
+	err := d.Modify(id, func(ex *core.Exchange) {
+		if upd.Body != nil {
+			ex.In().SetBody(*upd.Body)
+		}
+		for k, v := range upd.Headers {
+			ex.In().SetHeader(k, v)
+		}
+		for _, k := range upd.RemoveHeaders {
+			delete(ex.In().Headers(), k)
+		}
+		for k, v := range upd.Properties {
+			ex.SetProperty(k, v)
+		}
+		for _, k := range upd.RemoveProperties {
+			delete(ex.Properties(), k)
+		}
+	})
+	if err != nil {
+		writeActionError(w, err)
+		return
+	}
+	s, _ := d.Suspension(id)
+	management.WriteJSON(w, http.StatusOK, s)
+}
+
+func writeActionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrNotSuspended) {
+		management.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	management.WriteError(w, http.StatusBadRequest, err.Error())
+}
@@ -1,24 +1,67 @@
 package definitions
 
 import (
+	"fmt"
+
 	"github.com/sonyjop/camelgo/core"
 	"github.com/sonyjop/camelgo/processors"
 )
 
 // ChoiceDefinition holds the blueprint for branching logic
 type ChoiceDefinition struct {
-	WhenClauses []WhenDefinition
-	Otherwise   []core.Definition
+	Identity
+	WhenClauses []*WhenDefinition
+	Otherwise   []core.Compilable
 }
 
 type WhenDefinition struct {
 	Condition core.Predicate
-	Steps     []core.Definition
+	Steps     []core.Compilable
+}
+
+func (d *ChoiceDefinition) ShortName() string {
+	return "choice"
 }
 
 // Compile transforms the IR into a ChoiceProcessor
-func (d *ChoiceDefinition) Compile(ctx core.Context) (core.Processor, error) {
+func (d *ChoiceDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if len(d.WhenClauses) == 0 {
+		return nil, fmt.Errorf("choice: at least one when clause is required")
+	}
+
 	runtimeChoice := &processors.ChoiceProcessor{}
+	for i, when := range d.WhenClauses {
+		if when.Condition == nil {
+			return nil, fmt.Errorf("choice: when clause %d has no condition", i+1)
+		}
+		pipeline, err := core.CompileSteps(ctx, when.Steps)
+		if err != nil {
+			return nil, err
+		}
+		runtimeChoice.Branches = append(runtimeChoice.Branches, processors.ChoiceBranch{
+			Condition: when.Condition,
+			Pipeline:  pipeline,
+		})
+	}
+	if len(d.Otherwise) > 0 {
+		otherwise, err := core.CompileSteps(ctx, d.Otherwise)
+		if err != nil {
+			return nil, err
+		}
+		runtimeChoice.Otherwise = otherwise
+	}
 
 	return runtimeChoice, nil
 }
+
+// Branches returns the when clauses followed by otherwise, if present.
+func (d *ChoiceDefinition) Branches() []core.Branch {
+	branches := make([]core.Branch, 0, len(d.WhenClauses)+1)
+	for _, when := range d.WhenClauses {
+		branches = append(branches, core.Branch{Label: "when", Steps: when.Steps})
+	}
+	if len(d.Otherwise) > 0 {
+		branches = append(branches, core.Branch{Label: "otherwise", Steps: d.Otherwise})
+	}
+	return branches
+}
@@ -0,0 +1,27 @@
+package definitions
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+// ConvertBodyDefinition converts the body to Type with the context's type
+// converter.
+type ConvertBodyDefinition struct {
+	Identity
+	Type reflect.Type
+}
+
+func (d *ConvertBodyDefinition) ShortName() string {
+	return "convertBodyTo"
+}
+
+func (d *ConvertBodyDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Type == nil {
+		return nil, fmt.Errorf("convertBodyTo: a type is required")
+	}
+	return &processors.ConvertBodyProcessor{Type: d.Type}, nil
+}
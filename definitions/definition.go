@@ -0,0 +1,27 @@
+package definitions
+
+import "github.com/sonyjop/camelgo/core"
+
+// Identity gives a definition an optional user-assigned step ID. Steps
+// without one get a generated ID such as "to2" when the route is compiled.
+// It also carries the step's source location when a loader knows it.
+type Identity struct {
+	ID       string
+	Location core.SourceLocation
+}
+
+func (i *Identity) GetID() string {
+	return i.ID
+}
+
+func (i *Identity) SetID(id string) {
+	i.ID = id
+}
+
+func (i *Identity) GetLocation() core.SourceLocation {
+	return i.Location
+}
+
+func (i *Identity) SetLocation(loc core.SourceLocation) {
+	i.Location = loc
+}
@@ -0,0 +1,62 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+// FilterDefinition runs its steps only for exchanges matching Predicate.
+// Exchanges that do not match are sent to DiscardURI, if set.
+type FilterDefinition struct {
+	Identity
+	Predicate  core.Predicate
+	Steps      []core.Compilable
+	DiscardURI string
+
+	discard *ToDefinition
+}
+
+func (d *FilterDefinition) ShortName() string {
+	return "filter"
+}
+
+func (d *FilterDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Predicate == nil {
+		return nil, fmt.Errorf("filter: no predicate given")
+	}
+	pipeline, err := core.CompileSteps(ctx, d.Steps)
+	if err != nil {
+		return nil, err
+	}
+	proc := &processors.FilterProcessor{Predicate: d.Predicate, Pipeline: pipeline, Stats: core.CompilingRouteStats(ctx)}
+	if steps := d.discardSteps(); steps != nil {
+		if proc.Discard, err = core.CompileSteps(ctx, steps); err != nil {
+			return nil, fmt.Errorf("filter: discard: %w", err)
+		}
+	}
+	return proc, nil
+}
+
+// Branches returns the matched steps and, with a DiscardURI, the send to it.
+func (d *FilterDefinition) Branches() []core.Branch {
+	branches := []core.Branch{{Label: "matched", Steps: d.Steps}}
+	if steps := d.discardSteps(); steps != nil {
+		branches = append(branches, core.Branch{Label: "discarded", Steps: steps})
+	}
+	return branches
+}
+
+// discardSteps returns the send to DiscardURI as a step, so it gets a step
+// ID and is checked and drawn like any other. The step is kept, since step
+// IDs are looked up by definition.
+func (d *FilterDefinition) discardSteps() []core.Compilable {
+	if d.DiscardURI == "" {
+		return nil
+	}
+	if d.discard == nil || d.discard.URI != d.DiscardURI {
+		d.discard = &ToDefinition{URI: d.DiscardURI, Identity: Identity{Location: d.Location}}
+	}
+	return []core.Compilable{d.discard}
+}
@@ -0,0 +1,71 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+// InterceptDefinition runs its steps before every processor step of the
+// routes it applies to, optionally only when When matches.
+type InterceptDefinition struct {
+	When  core.Predicate
+	Steps []core.Compilable
+}
+
+// Install compiles the interceptor steps and registers the strategy.
+func (d *InterceptDefinition) Install(ctx core.Context) error {
+	interceptor, err := core.CompileSteps(ctx, d.Steps)
+	if err != nil {
+		return fmt.Errorf("intercept: %w", err)
+	}
+	ctx.AddInterceptStrategy(&processors.StepInterceptStrategy{When: d.When, Interceptor: interceptor})
+	return nil
+}
+
+// InterceptFromDefinition runs its steps when a consumer whose URI matches
+// URI (exact, wildcard or regex) hands a new exchange to its route.
+type InterceptFromDefinition struct {
+	URI   string
+	When  core.Predicate
+	Steps []core.Compilable
+}
+
+// Install compiles the interceptor steps and registers the strategy.
+func (d *InterceptFromDefinition) Install(ctx core.Context) error {
+	interceptor, err := core.CompileSteps(ctx, d.Steps)
+	if err != nil {
+		return fmt.Errorf("interceptFrom(%s): %w", d.URI, err)
+	}
+	ctx.AddInterceptStrategy(&processors.FromInterceptStrategy{Pattern: d.URI, When: d.When, Interceptor: interceptor})
+	return nil
+}
+
+// InterceptSendToEndpointDefinition runs its steps before exchanges are sent
+// to endpoints whose URI matches URI. With SkipSendToOriginalEndpoint the
+// original endpoint is not called when the interceptor applies.
+type InterceptSendToEndpointDefinition struct {
+	URI                        string
+	When                       core.Predicate
+	SkipSendToOriginalEndpoint bool
+	Steps                      []core.Compilable
+}
+
+// Install compiles the interceptor steps and registers the producer interceptor.
+func (d *InterceptSendToEndpointDefinition) Install(ctx core.Context) error {
+	if d.URI == "" {
+		return fmt.Errorf("interceptSendToEndpoint requires an endpoint URI pattern")
+	}
+	interceptor, err := core.CompileSteps(ctx, d.Steps)
+	if err != nil {
+		return fmt.Errorf("interceptSendToEndpoint(%s): %w", d.URI, err)
+	}
+	ctx.AddProducerInterceptor(&processors.SendToEndpointInterceptor{
+		Pattern:      d.URI,
+		When:         d.When,
+		Interceptor:  interceptor,
+		SkipOriginal: d.SkipSendToOriginalEndpoint,
+	})
+	return nil
+}
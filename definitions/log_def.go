@@ -0,0 +1,37 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+// LogDefinition logs a message at Level. LoggerName selects the log
+// category; it defaults to processors.DefaultLogCategory.
+type LogDefinition struct {
+	Identity
+	Message    core.Expression
+	Level      logrus.Level
+	LoggerName string
+}
+
+func (d *LogDefinition) ShortName() string {
+	return "log"
+}
+
+func (d *LogDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Message == nil {
+		return nil, fmt.Errorf("log: a message is required")
+	}
+	if d.Level < logrus.ErrorLevel {
+		return nil, fmt.Errorf("log: level %s would stop the route; use error or below", d.Level)
+	}
+	category, err := core.ResolvePlaceholders(ctx, d.LoggerName)
+	if err != nil {
+		return nil, fmt.Errorf("log: %w", err)
+	}
+	return &processors.LogProcessor{Message: d.Message, Level: d.Level, Category: category}, nil
+}
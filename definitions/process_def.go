@@ -0,0 +1,33 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// ProcessDefinition plugs a user-supplied Processor into a route, either
+// directly or by the name it is bound under in the registry.
+type ProcessDefinition struct {
+	Identity
+	Processor core.Processor
+	Ref       string
+}
+
+func (d *ProcessDefinition) ShortName() string {
+	return "process"
+}
+
+func (d *ProcessDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Processor != nil {
+		return d.Processor, nil
+	}
+	if d.Ref == "" {
+		return nil, fmt.Errorf("process: no processor given")
+	}
+	p, err := core.Lookup[core.Processor](ctx.Registry(), d.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("process: %w", err)
+	}
+	return p, nil
+}
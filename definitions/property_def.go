@@ -0,0 +1,51 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+// SetPropertyDefinition sets an exchange property from an expression.
+type SetPropertyDefinition struct {
+	Identity
+	Name       string
+	Expression core.Expression
+}
+
+func (d *SetPropertyDefinition) ShortName() string {
+	return "setProperty"
+}
+
+func (d *SetPropertyDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Name == "" || d.Expression == nil {
+		return nil, fmt.Errorf("setProperty: both a name and an expression are required")
+	}
+	name, err := core.ResolvePlaceholders(ctx, d.Name)
+	if err != nil {
+		return nil, fmt.Errorf("setProperty: %w", err)
+	}
+	return &processors.SetPropertyProcessor{Name: name, Expression: d.Expression}, nil
+}
+
+// RemovePropertyDefinition removes an exchange property.
+type RemovePropertyDefinition struct {
+	Identity
+	Name string
+}
+
+func (d *RemovePropertyDefinition) ShortName() string {
+	return "removeProperty"
+}
+
+func (d *RemovePropertyDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Name == "" {
+		return nil, fmt.Errorf("removeProperty: a name is required")
+	}
+	name, err := core.ResolvePlaceholders(ctx, d.Name)
+	if err != nil {
+		return nil, fmt.Errorf("removeProperty: %w", err)
+	}
+	return &processors.RemovePropertyProcessor{Name: name}, nil
+}
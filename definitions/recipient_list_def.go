@@ -0,0 +1,62 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+// RecipientListDefinition sends a copy of the exchange to each endpoint
+// Expression lists, as a string separated by Delimiter (default ",") or a
+// slice, and aggregates the results with AggregationStrategy, or the
+// strategy bound under AggregationStrategyRef. Without either, the last
+// result wins.
+type RecipientListDefinition struct {
+	Identity
+	Expression             core.Expression
+	Delimiter              string
+	ParallelProcessing     bool
+	AggregationStrategy    core.AggregationStrategy
+	AggregationStrategyRef string
+	CacheSize              int
+}
+
+func (d *RecipientListDefinition) ShortName() string {
+	return "recipientList"
+}
+
+func (d *RecipientListDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Expression == nil {
+		return nil, fmt.Errorf("recipientList: an expression is required")
+	}
+	strategy, err := aggregationStrategy(ctx, d.AggregationStrategy, d.AggregationStrategyRef)
+	if err != nil {
+		return nil, fmt.Errorf("recipientList: %w", err)
+	}
+	cache, err := ctx.NewProducerCache(d.CacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("recipientList: %w", err)
+	}
+	return &processors.RecipientListProcessor{
+		Expression: d.Expression,
+		Delimiter:  d.Delimiter,
+		Parallel:   d.ParallelProcessing,
+		Strategy:   strategy,
+		Cache:      cache,
+	}, nil
+}
+
+// aggregationStrategy returns strategy, the strategy bound under ref, or
+// core.UseLatestStrategy when neither is given.
+func aggregationStrategy(ctx core.CompileContext, strategy core.AggregationStrategy, ref string) (core.AggregationStrategy, error) {
+	switch {
+	case strategy != nil && ref != "":
+		return nil, fmt.Errorf("give either an aggregation strategy or a reference to one, not both")
+	case strategy != nil:
+		return strategy, nil
+	case ref != "":
+		return core.Lookup[core.AggregationStrategy](ctx.Registry(), ref)
+	}
+	return core.UseLatestStrategy{}, nil
+}
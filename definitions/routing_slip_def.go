@@ -0,0 +1,32 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+// RoutingSlipDefinition sends the exchange through the endpoints Expression
+// lists, usually a header, in order.
+type RoutingSlipDefinition struct {
+	Identity
+	Expression core.Expression
+	Delimiter  string
+	CacheSize  int
+}
+
+func (d *RoutingSlipDefinition) ShortName() string {
+	return "routingSlip"
+}
+
+func (d *RoutingSlipDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Expression == nil {
+		return nil, fmt.Errorf("routingSlip: an expression is required")
+	}
+	cache, err := ctx.NewProducerCache(d.CacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("routingSlip: %w", err)
+	}
+	return &processors.RoutingSlipProcessor{Expression: d.Expression, Delimiter: d.Delimiter, Cache: cache}, nil
+}
@@ -0,0 +1,25 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+// SetBodyDefinition replaces the body with the value of an expression.
+type SetBodyDefinition struct {
+	Identity
+	Expression core.Expression
+}
+
+func (d *SetBodyDefinition) ShortName() string {
+	return "setBody"
+}
+
+func (d *SetBodyDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Expression == nil {
+		return nil, fmt.Errorf("setBody: an expression is required")
+	}
+	return &processors.SetBodyProcessor{Expression: d.Expression}, nil
+}
@@ -0,0 +1,109 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+// SetHeaderDefinition sets a header from an expression.
+type SetHeaderDefinition struct {
+	Identity
+	Name       string
+	Expression core.Expression
+}
+
+func (d *SetHeaderDefinition) ShortName() string {
+	return "setHeader"
+}
+
+func (d *SetHeaderDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Name == "" || d.Expression == nil {
+		return nil, fmt.Errorf("setHeader: both a name and an expression are required")
+	}
+	name, err := core.ResolvePlaceholders(ctx, d.Name)
+	if err != nil {
+		return nil, fmt.Errorf("setHeader: %w", err)
+	}
+	return &processors.SetHeaderProcessor{Name: name, Expression: d.Expression}, nil
+}
+
+// SetHeadersDefinition sets several headers, each from an expression.
+type SetHeadersDefinition struct {
+	Identity
+	Headers map[string]core.Expression
+}
+
+func (d *SetHeadersDefinition) ShortName() string {
+	return "setHeaders"
+}
+
+func (d *SetHeadersDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if len(d.Headers) == 0 {
+		return nil, fmt.Errorf("setHeaders: at least one header is required")
+	}
+	headers := make(map[string]core.Expression, len(d.Headers))
+	for name, expr := range d.Headers {
+		if name == "" || expr == nil {
+			return nil, fmt.Errorf("setHeaders: every header needs a name and an expression")
+		}
+		resolved, err := core.ResolvePlaceholders(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("setHeaders: %w", err)
+		}
+		headers[resolved] = expr
+	}
+	return &processors.SetHeadersProcessor{Headers: headers}, nil
+}
+
+// RemoveHeaderDefinition removes a header.
+type RemoveHeaderDefinition struct {
+	Identity
+	Name string
+}
+
+func (d *RemoveHeaderDefinition) ShortName() string {
+	return "removeHeader"
+}
+
+func (d *RemoveHeaderDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Name == "" {
+		return nil, fmt.Errorf("removeHeader: a name is required")
+	}
+	name, err := core.ResolvePlaceholders(ctx, d.Name)
+	if err != nil {
+		return nil, fmt.Errorf("removeHeader: %w", err)
+	}
+	return &processors.RemoveHeaderProcessor{Name: name}, nil
+}
+
+// RemoveHeadersDefinition removes the headers matching Pattern except those
+// matching one of Exclude. Patterns may be exact names, wildcards such as
+// "Camel*" or regular expressions.
+type RemoveHeadersDefinition struct {
+	Identity
+	Pattern string
+	Exclude []string
+}
+
+func (d *RemoveHeadersDefinition) ShortName() string {
+	return "removeHeaders"
+}
+
+func (d *RemoveHeadersDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	if d.Pattern == "" {
+		return nil, fmt.Errorf("removeHeaders: a pattern is required")
+	}
+	pattern, err := core.ResolvePlaceholders(ctx, d.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("removeHeaders: %w", err)
+	}
+	exclude := make([]string, len(d.Exclude))
+	for i, e := range d.Exclude {
+		if exclude[i], err = core.ResolvePlaceholders(ctx, e); err != nil {
+			return nil, fmt.Errorf("removeHeaders: %w", err)
+		}
+	}
+	return &processors.RemoveHeadersProcessor{Pattern: pattern, Exclude: exclude}, nil
+}
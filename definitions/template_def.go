@@ -0,0 +1,31 @@
+package definitions
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+// TransformTemplateDefinition replaces the body with the output of a
+// text/template executed with processors.TemplateData, e.g.
+// "Dear {{.Headers.name}}, your order {{.Body}} has shipped". Property
+// placeholders are not resolved in the template, since they share its
+// delimiters.
+type TransformTemplateDefinition struct {
+	Identity
+	Template string
+}
+
+func (d *TransformTemplateDefinition) ShortName() string {
+	return "transformTemplate"
+}
+
+func (d *TransformTemplateDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	tmpl, err := template.New("transformTemplate").Parse(d.Template)
+	if err != nil {
+		return nil, fmt.Errorf("transformTemplate: %w", err)
+	}
+	return &processors.TemplateProcessor{Template: tmpl}, nil
+}
@@ -1,14 +1,36 @@
 package definitions
 
-import "github.com/sonyjop/camelgo/core"
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/processors"
+)
 
 // ToDefinition is the metadata for sending to an endpoint.
 type ToDefinition struct {
+	Identity
 	URI string
 }
 
+func (d *ToDefinition) ShortName() string {
+	return "to"
+}
+
+// Compile creates a context-managed producer, so it is started and stopped
+// together with the context.
 func (d *ToDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
-	ep, _ := ctx.GetEndpoint(d.URI)
-	prod, _ := ep.CreateProducer()
-	return prod, nil // Producer implements Processor
+	uri, err := core.ResolvePlaceholders(ctx, d.URI)
+	if err != nil {
+		return nil, fmt.Errorf("to(%s): %w", d.URI, err)
+	}
+	prod, err := ctx.CreateProducer(uri)
+	if err != nil {
+		return nil, fmt.Errorf("to(%s): %w", d.URI, err)
+	}
+	return &processors.SendProcessor{URI: core.MaskURI(ctx, uri), Producer: prod}, nil
+}
+
+func (d *ToDefinition) EndpointURI() string {
+	return d.URI
 }
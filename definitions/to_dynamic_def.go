@@ -0,0 +1,39 @@
+package definitions
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/language"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+// ToDynamicDefinition sends to an endpoint whose URI is a simple language
+// template evaluated for each exchange, e.g. "file:out/${header.region}.txt".
+// Producers are kept in a cache of CacheSize, or
+// core.DefaultProducerCacheSize, endpoints.
+type ToDynamicDefinition struct {
+	Identity
+	URI       string
+	CacheSize int
+}
+
+func (d *ToDynamicDefinition) ShortName() string {
+	return "toD"
+}
+
+func (d *ToDynamicDefinition) Compile(ctx core.CompileContext) (core.Processor, error) {
+	uri, err := core.ResolvePlaceholders(ctx, d.URI)
+	if err != nil {
+		return nil, fmt.Errorf("toD(%s): %w", d.URI, err)
+	}
+	expr, err := language.Simple(uri)
+	if err != nil {
+		return nil, fmt.Errorf("toD(%s): %w", d.URI, err)
+	}
+	cache, err := ctx.NewProducerCache(d.CacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("toD(%s): %w", d.URI, err)
+	}
+	return &processors.DynamicSendProcessor{Expression: expr, Cache: cache}, nil
+}
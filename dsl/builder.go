@@ -1,20 +1,79 @@
 package dsl
 
-import "github.com/sonyjop/camelgo/core"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/definitions"
+)
 
 // BaseRouteBuilder provides common logic for user-defined routes.
 type BaseRouteBuilder struct {
-	definitions []*core.RouteDefinition
+	definitions        []*core.RouteDefinition
+	contextDefinitions []core.ContextDefinition
+	choices            []*choiceRef
+	errs               []error
+}
+
+// choiceRef remembers where a Choice() was declared for validation.
+type choiceRef struct {
+	def   *definitions.ChoiceDefinition
+	owner string
 }
 
-func (b *BaseRouteBuilder) From(uri string) *core.RouteDefinition {
+// From starts a new route consuming from uri and returns its fluent builder.
+func (b *BaseRouteBuilder) From(uri string) *RouteDSL {
 	route := &core.RouteDefinition{InputURI: uri}
 	b.definitions = append(b.definitions, route)
-	return route
-}
-func (b *BaseRouteBuilder) To(route *core.RouteDefinition, uri string) {
-	route.AddStep(&core.ToDefinition{URI: uri})
+	return newRouteDSL(b, route, fmt.Sprintf("from(%q)", uri), &route.Steps, nil)
 }
+
 func (b *BaseRouteBuilder) GetRouteDefinitions() []*core.RouteDefinition {
 	return b.definitions
 }
+
+// GetContextDefinitions returns the interceptors declared by this builder.
+func (b *BaseRouteBuilder) GetContextDefinitions() []core.ContextDefinition {
+	return b.contextDefinitions
+}
+
+// Err returns the DSL misuse recorded while configuring, if any. The loader
+// calls it after Configure so mistakes fail AddRoutes.
+func (b *BaseRouteBuilder) Err() error {
+	errs := append([]error(nil), b.errs...)
+	for _, c := range b.choices {
+		if len(c.def.WhenClauses) == 0 {
+			errs = append(errs, fmt.Errorf("%s: Choice() needs at least one When()", c.owner))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Intercept declares steps that run before every processor step of the
+// routes added together with this builder.
+func (b *BaseRouteBuilder) Intercept() *RouteDSL {
+	def := &definitions.InterceptDefinition{}
+	b.contextDefinitions = append(b.contextDefinitions, def)
+	return newRouteDSL(b, nil, "intercept()", &def.Steps, def)
+}
+
+// InterceptFrom declares steps that run when a consumer whose URI matches
+// pattern (exact, wildcard or regex) creates an exchange.
+func (b *BaseRouteBuilder) InterceptFrom(pattern string) *RouteDSL {
+	def := &definitions.InterceptFromDefinition{URI: pattern}
+	b.contextDefinitions = append(b.contextDefinitions, def)
+	return newRouteDSL(b, nil, fmt.Sprintf("interceptFrom(%q)", pattern), &def.Steps, def)
+}
+
+// InterceptSendToEndpoint declares steps that run before exchanges are sent
+// to endpoints whose URI matches pattern.
+func (b *BaseRouteBuilder) InterceptSendToEndpoint(pattern string) *RouteDSL {
+	def := &definitions.InterceptSendToEndpointDefinition{URI: pattern}
+	b.contextDefinitions = append(b.contextDefinitions, def)
+	return newRouteDSL(b, nil, fmt.Sprintf("interceptSendToEndpoint(%q)", pattern), &def.Steps, def)
+}
+
+func (b *BaseRouteBuilder) fail(err error) {
+	b.errs = append(b.errs, err)
+}
@@ -0,0 +1,181 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sonyjop/camelgo/component/direct"
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/language"
+)
+
+func TestRouteDSL_FluentChain(t *testing.T) {
+	var seen []string
+	record := core.PredicateFunc(func(ctx core.Context, ex *core.Exchange) (bool, error) {
+		return true, nil
+	})
+	tag := core.ProcessorFunc(func(ctx core.Context, ex *core.Exchange) error {
+		seen = append(seen, ex.In().Header("region").(string))
+		return nil
+	})
+
+	ctx, mock := newTestContext(t, func(b *BaseRouteBuilder) {
+		b.From("mock:in").RouteID("r1").
+			Filter(record).
+			SetHeader("region", language.Header("country")).
+			Process(tag).
+			Choice().
+			When(language.Equals(language.Header("country"), "DE")).To("mock:eu").
+			When(language.Equals(language.Header("country"), "US")).To("mock:us").
+			Otherwise().To("mock:other").
+			End().
+			End().
+			To("mock:out")
+	})
+
+	for _, country := range []string{"DE", "US", "JP"} {
+		ex := ctx.NewExchange()
+		ex.In().SetBody(country)
+		ex.In().SetHeader("country", country)
+		if err := ctx.Route("r1").Pipeline.Process(ctx, ex); err != nil {
+			t.Fatalf("route failed: %v", err)
+		}
+	}
+
+	for uri, want := range map[string]string{"mock:eu": "DE", "mock:us": "US", "mock:other": "JP"} {
+		if got := mock.bodies(uri); len(got) != 1 || got[0] != want {
+			t.Errorf("%s: expected [%s], got %v", uri, want, got)
+		}
+	}
+	if got := mock.bodies("mock:out"); len(got) != 3 {
+		t.Errorf("expected every exchange to reach mock:out after End(), got %v", got)
+	}
+	if strings.Join(seen, ",") != "DE,US,JP" {
+		t.Errorf("expected SetHeader to run before Process, saw %v", seen)
+	}
+}
+
+func TestRouteDSL_NestedChoiceAndDirect(t *testing.T) {
+	ctx := core.NewContext()
+	mock := newMockComponent()
+	ctx.RegisterComponent("mock", mock)
+	ctx.RegisterComponent("direct", direct.NewDirectComponent())
+	ctx.SetLoader(NewDSLLoader())
+
+	big := core.PredicateFunc(func(ctx core.Context, ex *core.Exchange) (bool, error) {
+		return len(ex.In().Body().(string)) > 3, nil
+	})
+	err := ctx.AddRoutes(&configureFunc{BaseRouteBuilder: &BaseRouteBuilder{}, fn: func(b *BaseRouteBuilder) {
+		b.From("mock:in").RouteID("main").
+			Choice().
+			When(bodyEquals("skip")).To("mock:skipped").
+			Otherwise().
+			Choice().
+			When(big).To("direct:big").
+			Otherwise().To("mock:small").
+			End().
+			End()
+		b.From("direct:big").RouteID("big").To("mock:big")
+	}})
+	if err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	defer ctx.Stop()
+
+	for _, body := range []string{"skip", "tiny", "ab"} {
+		send(t, ctx, "main", body)
+	}
+
+	if got := mock.bodies("mock:big"); len(got) != 1 || got[0] != "tiny" {
+		t.Errorf("expected tiny to go through direct:big, got %v", got)
+	}
+	if got := mock.bodies("mock:small"); len(got) != 1 || got[0] != "ab" {
+		t.Errorf("expected ab to be small, got %v", got)
+	}
+	if got := mock.bodies("mock:skipped"); len(got) != 1 {
+		t.Errorf("expected skip to be skipped, got %v", got)
+	}
+}
+
+func TestRouteDSL_MisuseIsReportedAtAddRoutes(t *testing.T) {
+	cases := map[string]struct {
+		configure func(b *BaseRouteBuilder)
+		message   string
+	}{
+		"when without choice": {
+			func(b *BaseRouteBuilder) { b.From("mock:in").When(bodyEquals("x")).To("mock:a") },
+			`from("mock:in"): When() must be used inside Choice()`,
+		},
+		"otherwise first": {
+			func(b *BaseRouteBuilder) { b.From("mock:in").Choice().Otherwise().To("mock:a").End() },
+			"Otherwise() must follow at least one When()",
+		},
+		"step directly in choice": {
+			func(b *BaseRouteBuilder) { b.From("mock:in").Choice().To("mock:a") },
+			`To("mock:a") cannot be added directly inside Choice()`,
+		},
+		"end without block": {
+			func(b *BaseRouteBuilder) { b.From("mock:in").To("mock:a").End() },
+			"End() called without an open Choice() or Filter()",
+		},
+		"when after otherwise": {
+			func(b *BaseRouteBuilder) {
+				b.From("mock:in").Choice().When(bodyEquals("a")).To("mock:a").Otherwise().To("mock:b").When(bodyEquals("c"))
+			},
+			"When() cannot follow Otherwise()",
+		},
+		"empty choice": {
+			func(b *BaseRouteBuilder) { b.From("mock:in").Choice().End() },
+			"Choice() needs at least one When()",
+		},
+		"id without step": {
+			func(b *BaseRouteBuilder) { b.From("mock:in").ID("x") },
+			`ID("x") must follow a step`,
+		},
+		"log at panic level": {
+			func(b *BaseRouteBuilder) { b.From("mock:in").Log(logrus.PanicLevel, "x") },
+			"log: level panic would stop the route",
+		},
+		"route id on interceptor": {
+			func(b *BaseRouteBuilder) {
+				b.Intercept().RouteID("x")
+				b.From("mock:in")
+			},
+			"RouteID(\"x\") is only valid on routes started with From()",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := core.NewContext()
+			ctx.RegisterComponent("mock", newMockComponent())
+			ctx.SetLoader(NewDSLLoader())
+
+			err := ctx.AddRoutes(&configureFunc{BaseRouteBuilder: &BaseRouteBuilder{}, fn: tc.configure})
+			if err == nil {
+				t.Fatalf("expected AddRoutes to fail")
+			}
+			if !strings.Contains(err.Error(), tc.message) {
+				t.Errorf("expected error to mention %q, got %v", tc.message, err)
+			}
+		})
+	}
+}
+
+func TestRouteDSL_StepIDs(t *testing.T) {
+	b := &BaseRouteBuilder{}
+	b.From("mock:in").To("mock:a").ID("send-a").SetHeader("k", language.Constant("v"))
+
+	steps := b.GetRouteDefinitions()[0].Steps
+	if id := steps[0].(core.Identifiable).GetID(); id != "send-a" {
+		t.Errorf("expected step ID send-a, got %q", id)
+	}
+	if id := steps[1].(core.Identifiable).GetID(); id != "" {
+		t.Errorf("expected no ID on the second step, got %q", id)
+	}
+}
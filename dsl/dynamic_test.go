@@ -0,0 +1,111 @@
+package dsl
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sonyjop/camelgo/component/direct"
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/language"
+)
+
+func TestDynamicEndpoints(t *testing.T) {
+	ctx := core.NewContext()
+	mock := newMockComponent()
+	ctx.RegisterComponent("mock", mock)
+	ctx.RegisterComponent("direct", direct.NewDirectComponent())
+	ctx.SetLoader(NewDSLLoader())
+
+	err := ctx.AddRoutes(&configureFunc{BaseRouteBuilder: &BaseRouteBuilder{}, fn: func(b *BaseRouteBuilder) {
+		b.From("mock:tod").RouteID("tod").ToD("mock:out-${header.region}").CacheSize(1)
+		b.From("mock:list").RouteID("list").
+			RecipientList(language.Header("recipients")).AggregationStrategy(core.GroupedBodyStrategy{})
+		b.From("mock:parallel").RouteID("parallel").
+			RecipientList(language.Header("recipients")).Delimiter(";").ParallelProcessing().AggregationStrategy(core.GroupedBodyStrategy{})
+		b.From("mock:slip").RouteID("slip").RoutingSlip("slip")
+		b.From("direct:a").RouteID("a").SetBody(language.MustSimple("${body}-a"))
+		b.From("direct:b").RouteID("b").SetBody(language.MustSimple("${body}-b"))
+		b.From("direct:declined").RouteID("declined").Process(core.ProcessorFunc(func(ctx core.Context, ex *core.Exchange) error {
+			ex.SetError(errors.New("declined"))
+			return nil
+		}))
+	}})
+	if err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	defer ctx.Stop()
+
+	run := func(routeID string, headers map[string]interface{}) *core.Exchange {
+		t.Helper()
+		ex := ctx.NewExchange()
+		ex.In().SetBody("x")
+		for k, v := range headers {
+			ex.In().SetHeader(k, v)
+		}
+		if err := ctx.Route(routeID).Pipeline.Process(ctx, ex); err != nil {
+			t.Fatalf("route %s failed: %v", routeID, err)
+		}
+		return ex
+	}
+
+	for _, region := range []string{"eu", "us", "eu"} {
+		run("tod", map[string]interface{}{"region": region})
+	}
+	if len(mock.bodies("mock:out-eu")) != 2 || len(mock.bodies("mock:out-us")) != 1 {
+		t.Errorf("expected toD to compute the URI per exchange, got %v", mock.received)
+	}
+
+	ex := run("list", map[string]interface{}{"recipients": "direct:a, direct:b,mock:copy"})
+	if want := []interface{}{"x-a", "x-b", "x"}; !reflect.DeepEqual(ex.In().Body(), want) {
+		t.Errorf("expected the replies aggregated in order, got %v", ex.In().Body())
+	}
+	ex = run("parallel", map[string]interface{}{"recipients": []string{"direct:b", "direct:a"}})
+	if want := []interface{}{"x-b", "x-a"}; !reflect.DeepEqual(ex.In().Body(), want) {
+		t.Errorf("expected parallel replies aggregated in list order, got %v", ex.In().Body())
+	}
+
+	ex = run("slip", map[string]interface{}{"slip": "direct:a,direct:b,mock:slipped"})
+	if ex.In().Body() != "x-a-b" || mock.bodies("mock:slipped")[0] != "x-a-b" {
+		t.Errorf("expected the slip to pass the exchange along in order, got %v", ex.In().Body())
+	}
+
+	ex = ctx.NewExchange()
+	ex.In().SetHeader("recipients", "direct:a,nope:x")
+	err = ctx.Route("list").Pipeline.Process(ctx, ex)
+	if err == nil || !strings.Contains(err.Error(), "recipientList(nope:x)") {
+		t.Errorf("expected the failing recipient in the error, got %v", err)
+	}
+
+	// A failure left on a copy is not aggregated as a reply.
+	for route, recipients := range map[string]string{"list": "direct:a,direct:declined", "parallel": "direct:a;direct:declined"} {
+		ex = ctx.NewExchange()
+		ex.In().SetHeader("recipients", recipients)
+		err = ctx.Route(route).Pipeline.Process(ctx, ex)
+		if err == nil || !strings.Contains(err.Error(), "recipientList(direct:declined): declined") {
+			t.Errorf("%s: expected the declined recipient to fail, got %v", route, err)
+		}
+	}
+}
+
+func TestDynamicEndpoints_Misuse(t *testing.T) {
+	cases := map[string]func(b *BaseRouteBuilder){
+		"ParallelProcessing() must follow RecipientList()": func(b *BaseRouteBuilder) { b.From("mock:in").To("mock:a").ParallelProcessing() },
+		"Delimiter(\";\") must follow RecipientList()":     func(b *BaseRouteBuilder) { b.From("mock:in").ToD("mock:a").Delimiter(";") },
+		"CacheSize(5) must follow ToD()":                   func(b *BaseRouteBuilder) { b.From("mock:in").To("mock:a").CacheSize(5) },
+		`ToD("mock:${nope}")`:                              func(b *BaseRouteBuilder) { b.From("mock:in").ToD("mock:${nope}") },
+	}
+	for want, fn := range cases {
+		ctx := core.NewContext()
+		ctx.RegisterComponent("mock", newMockComponent())
+		ctx.SetLoader(NewDSLLoader())
+		err := ctx.AddRoutes(&configureFunc{BaseRouteBuilder: &BaseRouteBuilder{}, fn: fn})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error containing %q, got %v", want, err)
+		}
+	}
+}
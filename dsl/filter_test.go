@@ -0,0 +1,80 @@
+package dsl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/language"
+	"github.com/sonyjop/camelgo/processors"
+)
+
+func TestFilter_DiscardAndStatistics(t *testing.T) {
+	var matched []interface{}
+	record := core.ProcessorFunc(func(ctx core.Context, ex *core.Exchange) error {
+		matched = append(matched, ex.GetProperty(processors.FilterMatchedProperty))
+		return nil
+	})
+	ctx, mock := newTestContext(t, func(b *BaseRouteBuilder) {
+		b.From("mock:in").RouteID("r1").
+			Filter(bodyEquals("keep")).
+			DiscardTo("mock:discarded").
+			To("mock:kept").
+			End().
+			Process(record)
+		b.From("mock:nested").RouteID("r2").
+			Choice().
+			When(language.Equals(language.Header("kind"), "order")).
+			Filter(bodyEquals("keep")).To("mock:orders").End().
+			Otherwise().To("mock:other").
+			End()
+	})
+
+	for _, body := range []string{"keep", "drop", "drop"} {
+		send(t, ctx, "r1", body)
+	}
+	if got := mock.bodies("mock:kept"); len(got) != 1 {
+		t.Errorf("expected one kept exchange, got %v", got)
+	}
+	if got := mock.bodies("mock:discarded"); len(got) != 2 {
+		t.Errorf("expected two discarded exchanges, got %v", got)
+	}
+	if len(matched) != 3 || matched[0] != true || matched[1] != false {
+		t.Errorf("expected the filter-matched property on every exchange, got %v", matched)
+	}
+	if st := ctx.Route("r1").Statistics(); st.ExchangesFiltered != 2 || st.ExchangesTotal != 3 {
+		t.Errorf("expected 2 of 3 exchanges filtered, got %+v", st)
+	}
+
+	for _, body := range []string{"keep", "drop"} {
+		ex := ctx.NewExchange()
+		ex.In().SetBody(body)
+		ex.In().SetHeader("kind", "order")
+		if err := ctx.Route("r2").Pipeline.Process(ctx, ex); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := mock.bodies("mock:orders"); len(got) != 1 || got[0] != "keep" {
+		t.Errorf("expected the nested filter to pass only keep, got %v", got)
+	}
+	if st := ctx.Route("r2").Statistics(); st.ExchangesFiltered != 1 {
+		t.Errorf("expected the nested filter to count in the route's statistics, got %+v", st)
+	}
+
+	steps := ctx.Route("r1").Dump().Steps
+	if len(steps) == 0 || len(steps[0].Branches) != 2 || steps[0].Branches[1].Steps[0].URI != "mock:discarded" {
+		t.Errorf("expected the discard endpoint as a branch of the filter, got %+v", steps)
+	}
+}
+
+func TestFilter_DiscardToOutsideFilter(t *testing.T) {
+	ctx := core.NewContext()
+	ctx.RegisterComponent("mock", newMockComponent())
+	ctx.SetLoader(NewDSLLoader())
+	err := ctx.AddRoutes(&configureFunc{BaseRouteBuilder: &BaseRouteBuilder{}, fn: func(b *BaseRouteBuilder) {
+		b.From("mock:in").DiscardTo("mock:x")
+	}})
+	if err == nil || !strings.Contains(err.Error(), `DiscardTo("mock:x") must be used inside Filter()`) {
+		t.Errorf("expected misuse to be reported, got %v", err)
+	}
+}
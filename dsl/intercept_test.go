@@ -0,0 +1,200 @@
+package dsl
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// mockComponent records every exchange sent to its producers, keyed by URI.
+type mockComponent struct {
+	mu       sync.Mutex
+	received map[string][]string
+}
+
+func newMockComponent() *mockComponent {
+	return &mockComponent{received: make(map[string][]string)}
+}
+
+func (c *mockComponent) GetScheme() string {
+	return "mock"
+}
+
+func (c *mockComponent) CreateEndpoint(cfg core.EndpointConfig) (core.Endpoint, error) {
+	return &mockEndpoint{component: c, uri: cfg.RawURI}, nil
+}
+
+func (c *mockComponent) bodies(uri string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.received[uri]...)
+}
+
+type mockEndpoint struct {
+	component *mockComponent
+	uri       string
+}
+
+func (e *mockEndpoint) CreateProducer() (core.Producer, error) {
+	return &mockProducer{endpoint: e}, nil
+}
+
+func (e *mockEndpoint) CreateConsumer(target core.Processor) (core.Consumer, error) {
+	return &mockConsumer{}, nil
+}
+
+func (e *mockEndpoint) GetURI() string {
+	return e.uri
+}
+
+type mockProducer struct {
+	core.ServiceSupport
+	endpoint *mockEndpoint
+}
+
+func (p *mockProducer) Start(ctx core.Context) error { return p.DoStart(nil) }
+func (p *mockProducer) Stop(ctx core.Context) error  { return p.DoStop(nil) }
+
+func (p *mockProducer) Process(ctx core.Context, exchange *core.Exchange) error {
+	c := p.endpoint.component
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	body, _ := exchange.In().Body().(string)
+	c.received[p.endpoint.uri] = append(c.received[p.endpoint.uri], body)
+	return nil
+}
+
+type mockConsumer struct{}
+
+func (m *mockConsumer) Start(ctx core.Context) error { return nil }
+func (m *mockConsumer) Stop(ctx core.Context) error  { return nil }
+
+// countingProcessor counts the exchanges it sees.
+type countingProcessor struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (p *countingProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.count++
+	return nil
+}
+
+func (p *countingProcessor) Count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.count
+}
+
+type bodyEquals string
+
+func (b bodyEquals) Evaluate(ctx core.Context, exchange *core.Exchange) (bool, error) {
+	return exchange.In().Body() == string(b), nil
+}
+
+type configureFunc struct {
+	*BaseRouteBuilder
+	fn func(b *BaseRouteBuilder)
+}
+
+func (c *configureFunc) Configure() {
+	c.fn(c.BaseRouteBuilder)
+}
+
+func newTestContext(t *testing.T, fn func(b *BaseRouteBuilder)) (*core.DefaultContext, *mockComponent) {
+	t.Helper()
+	ctx := core.NewContext()
+	mock := newMockComponent()
+	ctx.RegisterComponent("mock", mock)
+	ctx.SetLoader(NewDSLLoader())
+	if err := ctx.AddRoutes(&configureFunc{BaseRouteBuilder: &BaseRouteBuilder{}, fn: fn}); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	t.Cleanup(func() { ctx.Stop() })
+	return ctx, mock
+}
+
+func send(t *testing.T, ctx *core.DefaultContext, routeID, body string) {
+	t.Helper()
+	ex := ctx.NewExchange()
+	ex.In().SetBody(body)
+	if err := ctx.Route(routeID).Pipeline.Process(ctx, ex); err != nil {
+		t.Fatalf("route %s failed: %v", routeID, err)
+	}
+}
+
+func TestIntercept_RunsBeforeEveryStep(t *testing.T) {
+	counter := &countingProcessor{}
+	ctx, mock := newTestContext(t, func(b *BaseRouteBuilder) {
+		b.Intercept().Process(counter)
+
+		b.From("mock:in").RouteID("r1").To("mock:a").To("mock:b")
+	})
+
+	send(t, ctx, "r1", "hello")
+
+	if counter.Count() != 2 {
+		t.Errorf("expected interceptor to run before both steps, ran %d times", counter.Count())
+	}
+	if len(mock.bodies("mock:a")) != 1 || len(mock.bodies("mock:b")) != 1 {
+		t.Errorf("expected original steps to run as well")
+	}
+}
+
+func TestInterceptFrom_MatchesConsumerURI(t *testing.T) {
+	counter := &countingProcessor{}
+	ctx, _ := newTestContext(t, func(b *BaseRouteBuilder) {
+		b.InterceptFrom("mock:orders*").When(bodyEquals("vip")).Process(counter)
+
+		b.From("mock:orders-eu").RouteID("orders").To("mock:out")
+		b.From("mock:billing").RouteID("billing").To("mock:out")
+	})
+
+	send(t, ctx, "orders", "vip")
+	send(t, ctx, "orders", "regular")
+	send(t, ctx, "billing", "vip")
+
+	if counter.Count() != 1 {
+		t.Errorf("expected one interception (matching URI and predicate), got %d", counter.Count())
+	}
+}
+
+func TestInterceptSendToEndpoint_SkipOriginal(t *testing.T) {
+	ctx, mock := newTestContext(t, func(b *BaseRouteBuilder) {
+		b.InterceptSendToEndpoint("mock:out*").SkipSendToOriginalEndpoint().To("mock:audit")
+
+		b.From("mock:in").RouteID("r1").To("mock:out-1").To("mock:other")
+	})
+
+	send(t, ctx, "r1", "payload")
+
+	if got := mock.bodies("mock:out-1"); len(got) != 0 {
+		t.Errorf("expected original endpoint to be skipped, got %v", got)
+	}
+	if got := mock.bodies("mock:audit"); len(got) != 1 || got[0] != "payload" {
+		t.Errorf("expected interceptor to receive the exchange, got %v", got)
+	}
+	if got := mock.bodies("mock:other"); len(got) != 1 {
+		t.Errorf("expected non-matching endpoint to be called, got %v", got)
+	}
+}
+
+func TestInterceptBuilder_MisuseIsReported(t *testing.T) {
+	ctx := core.NewContext()
+	ctx.RegisterComponent("mock", newMockComponent())
+	ctx.SetLoader(NewDSLLoader())
+
+	err := ctx.AddRoutes(&configureFunc{BaseRouteBuilder: &BaseRouteBuilder{}, fn: func(b *BaseRouteBuilder) {
+		b.Intercept().SkipSendToOriginalEndpoint()
+		b.From("mock:in")
+	}})
+	if err == nil {
+		t.Fatalf("expected misuse of SkipSendToOriginalEndpoint to fail AddRoutes")
+	}
+}
@@ -26,6 +26,11 @@ func (l *DSLLoader) Load(source interface{}) ([]*core.RouteDefinition, error) {
 	// 2. Execute the user's DSL configuration
 	// This populates the internal state of the builder
 	builder.Configure()
+	if v, ok := builder.(interface{ Err() error }); ok {
+		if err := v.Err(); err != nil {
+			return nil, fmt.Errorf("invalid route DSL: %w", err)
+		}
+	}
 
 	// 3. Extract the resulting IR (Blueprints)
 	// We assume RouteBuilder has a GetRouteDefinitions() method
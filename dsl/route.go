@@ -0,0 +1,397 @@
+package dsl
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/definitions"
+	"github.com/sonyjop/camelgo/language"
+)
+
+type blockKind int
+
+const (
+	rootBlock blockKind = iota
+	choiceBlock
+	whenBlock
+	otherwiseBlock
+	filterBlock
+)
+
+var blockNames = map[blockKind]string{
+	rootBlock:      "route",
+	choiceBlock:    "Choice()",
+	whenBlock:      "When()",
+	otherwiseBlock: "Otherwise()",
+	filterBlock:    "Filter()",
+}
+
+// block is an open scope of the fluent builder; new steps go into steps.
+type block struct {
+	kind   blockKind
+	steps  *[]core.Compilable
+	choice *definitions.ChoiceDefinition // set for choice, when and otherwise blocks
+	filter *definitions.FilterDefinition // set for filter blocks
+}
+
+// RouteDSL is the fluent builder returned by From and the Intercept methods.
+// Nested blocks (Choice, Filter) are closed with End(). Misuse does not
+// panic; it is recorded on the BaseRouteBuilder and reported by AddRoutes.
+type RouteDSL struct {
+	builder   *BaseRouteBuilder
+	route     *core.RouteDefinition  // nil for interceptors
+	intercept core.ContextDefinition // nil for routes
+	owner     string                 // e.g. from("file:in"), used in error messages
+	stack     []*block
+	last      core.Compilable
+}
+
+func newRouteDSL(b *BaseRouteBuilder, route *core.RouteDefinition, owner string, steps *[]core.Compilable, intercept core.ContextDefinition) *RouteDSL {
+	return &RouteDSL{
+		builder:   b,
+		route:     route,
+		intercept: intercept,
+		owner:     owner,
+		stack:     []*block{{kind: rootBlock, steps: steps}},
+	}
+}
+
+func (r *RouteDSL) top() *block {
+	return r.stack[len(r.stack)-1]
+}
+
+func (r *RouteDSL) push(b *block) {
+	r.stack = append(r.stack, b)
+}
+
+func (r *RouteDSL) pop() {
+	r.stack = r.stack[:len(r.stack)-1]
+}
+
+func (r *RouteDSL) fail(format string, args ...interface{}) *RouteDSL {
+	r.builder.fail(fmt.Errorf("%s: %s", r.owner, fmt.Sprintf(format, args...)))
+	return r
+}
+
+// addStep appends def to the innermost open block.
+func (r *RouteDSL) addStep(name string, def core.Compilable) *RouteDSL {
+	top := r.top()
+	if top.kind == choiceBlock {
+		return r.fail("%s cannot be added directly inside Choice(); start a When() or Otherwise() first", name)
+	}
+	*top.steps = append(*top.steps, def)
+	r.last = def
+	return r
+}
+
+// RouteID sets the ID of the route.
+func (r *RouteDSL) RouteID(id string) *RouteDSL {
+	if r.route == nil {
+		return r.fail("RouteID(%q) is only valid on routes started with From()", id)
+	}
+	r.route.ID = id
+	return r
+}
+
+// ID sets the step ID of the most recently added step.
+func (r *RouteDSL) ID(id string) *RouteDSL {
+	target, ok := r.last.(interface{ SetID(string) })
+	if !ok {
+		return r.fail("ID(%q) must follow a step", id)
+	}
+	target.SetID(id)
+	return r
+}
+
+// To sends the exchange to the endpoint at uri.
+func (r *RouteDSL) To(uri string) *RouteDSL {
+	return r.addStep(fmt.Sprintf("To(%q)", uri), &definitions.ToDefinition{URI: uri})
+}
+
+// ToD sends the exchange to the endpoint whose URI the simple language
+// template uri computes for it, e.g. "file:out/${header.region}.txt".
+func (r *RouteDSL) ToD(uri string) *RouteDSL {
+	if _, err := language.Simple(uri); err != nil {
+		return r.fail("ToD(%q): %v", uri, err)
+	}
+	return r.addStep(fmt.Sprintf("ToD(%q)", uri), &definitions.ToDynamicDefinition{URI: uri})
+}
+
+// RecipientList sends a copy of the exchange to each endpoint URI expr
+// evaluates to, given comma-separated or as a slice. Configure it with
+// ParallelProcessing, AggregationStrategy, Delimiter and CacheSize.
+func (r *RouteDSL) RecipientList(expr core.Expression) *RouteDSL {
+	if expr == nil {
+		return r.fail("RecipientList() requires an expression")
+	}
+	return r.addStep("RecipientList()", &definitions.RecipientListDefinition{Expression: expr})
+}
+
+// RoutingSlip sends the exchange through the endpoint URIs listed in the
+// header, in order.
+func (r *RouteDSL) RoutingSlip(header string) *RouteDSL {
+	return r.addStep(fmt.Sprintf("RoutingSlip(%q)", header), &definitions.RoutingSlipDefinition{Expression: language.Header(header)})
+}
+
+// ParallelProcessing makes the preceding RecipientList() send to its
+// recipients concurrently.
+func (r *RouteDSL) ParallelProcessing() *RouteDSL {
+	d, ok := r.last.(*definitions.RecipientListDefinition)
+	if !ok {
+		return r.fail("ParallelProcessing() must follow RecipientList()")
+	}
+	d.ParallelProcessing = true
+	return r
+}
+
+// AggregationStrategy sets how the preceding RecipientList() combines the
+// results of its recipients.
+func (r *RouteDSL) AggregationStrategy(s core.AggregationStrategy) *RouteDSL {
+	d, ok := r.last.(*definitions.RecipientListDefinition)
+	if !ok {
+		return r.fail("AggregationStrategy() must follow RecipientList()")
+	}
+	d.AggregationStrategy = s
+	return r
+}
+
+// Delimiter sets the separator of the endpoint URIs of the preceding
+// RecipientList() or RoutingSlip().
+func (r *RouteDSL) Delimiter(delimiter string) *RouteDSL {
+	switch d := r.last.(type) {
+	case *definitions.RecipientListDefinition:
+		d.Delimiter = delimiter
+	case *definitions.RoutingSlipDefinition:
+		d.Delimiter = delimiter
+	default:
+		return r.fail("Delimiter(%q) must follow RecipientList() or RoutingSlip()", delimiter)
+	}
+	return r
+}
+
+// CacheSize limits how many producers the preceding ToD(), RecipientList()
+// or RoutingSlip() keeps.
+func (r *RouteDSL) CacheSize(size int) *RouteDSL {
+	switch d := r.last.(type) {
+	case *definitions.ToDynamicDefinition:
+		d.CacheSize = size
+	case *definitions.RecipientListDefinition:
+		d.CacheSize = size
+	case *definitions.RoutingSlipDefinition:
+		d.CacheSize = size
+	default:
+		return r.fail("CacheSize(%d) must follow ToD(), RecipientList() or RoutingSlip()", size)
+	}
+	return r
+}
+
+// Process runs a custom processor.
+func (r *RouteDSL) Process(p core.Processor) *RouteDSL {
+	if p == nil {
+		return r.fail("Process() requires a non-nil processor")
+	}
+	return r.addStep("Process()", &definitions.ProcessDefinition{Processor: p})
+}
+
+// SetHeader sets a header on the in message from expr.
+func (r *RouteDSL) SetHeader(name string, expr core.Expression) *RouteDSL {
+	if expr == nil {
+		return r.fail("SetHeader(%q) requires an expression", name)
+	}
+	return r.addStep(fmt.Sprintf("SetHeader(%q)", name), &definitions.SetHeaderDefinition{Name: name, Expression: expr})
+}
+
+// SetHeaders sets several headers, each from its expression. The
+// expressions are evaluated before any header is set.
+func (r *RouteDSL) SetHeaders(headers map[string]core.Expression) *RouteDSL {
+	if len(headers) == 0 {
+		return r.fail("SetHeaders() requires at least one header")
+	}
+	for name, expr := range headers {
+		if expr == nil {
+			return r.fail("SetHeaders(): header %q requires an expression", name)
+		}
+	}
+	return r.addStep("SetHeaders()", &definitions.SetHeadersDefinition{Headers: headers})
+}
+
+// RemoveHeader removes a header from the in message.
+func (r *RouteDSL) RemoveHeader(name string) *RouteDSL {
+	return r.addStep(fmt.Sprintf("RemoveHeader(%q)", name), &definitions.RemoveHeaderDefinition{Name: name})
+}
+
+// RemoveHeaders removes the headers matching pattern, except those matching
+// one of exclude. Patterns are exact names, wildcards or regular
+// expressions, see core.MatchPattern.
+func (r *RouteDSL) RemoveHeaders(pattern string, exclude ...string) *RouteDSL {
+	return r.addStep(fmt.Sprintf("RemoveHeaders(%q)", pattern), &definitions.RemoveHeadersDefinition{Pattern: pattern, Exclude: exclude})
+}
+
+// SetBody replaces the body of the in message with the value of expr.
+func (r *RouteDSL) SetBody(expr core.Expression) *RouteDSL {
+	if expr == nil {
+		return r.fail("SetBody() requires an expression")
+	}
+	return r.addStep("SetBody()", &definitions.SetBodyDefinition{Expression: expr})
+}
+
+// SetProperty sets an exchange property from expr.
+func (r *RouteDSL) SetProperty(name string, expr core.Expression) *RouteDSL {
+	if expr == nil {
+		return r.fail("SetProperty(%q) requires an expression", name)
+	}
+	return r.addStep(fmt.Sprintf("SetProperty(%q)", name), &definitions.SetPropertyDefinition{Name: name, Expression: expr})
+}
+
+// RemoveProperty removes an exchange property.
+func (r *RouteDSL) RemoveProperty(name string) *RouteDSL {
+	return r.addStep(fmt.Sprintf("RemoveProperty(%q)", name), &definitions.RemovePropertyDefinition{Name: name})
+}
+
+// ConvertBodyTo converts the body to t with the context's type converter.
+// ConvertBodyTo[T] is the type-safe form.
+func (r *RouteDSL) ConvertBodyTo(t reflect.Type) *RouteDSL {
+	if t == nil {
+		return r.fail("ConvertBodyTo() requires a type")
+	}
+	return r.addStep(fmt.Sprintf("ConvertBodyTo(%s)", t), &definitions.ConvertBodyDefinition{Type: t})
+}
+
+// TransformTemplate replaces the body with the output of the text/template
+// tmpl, executed with processors.TemplateData:
+//
+//	TransformTemplate("Hello {{.Headers.name}}, you sent {{.Body}}")
+func (r *RouteDSL) TransformTemplate(tmpl string) *RouteDSL {
+	return r.addStep("TransformTemplate()", &definitions.TransformTemplateDefinition{Template: tmpl})
+}
+
+// Log logs message, a simple language template such as
+// "received ${body}", at level.
+func (r *RouteDSL) Log(level logrus.Level, message string) *RouteDSL {
+	expr, err := language.Simple(message)
+	if err != nil {
+		return r.fail("Log(): %v", err)
+	}
+	return r.addStep("Log()", &definitions.LogDefinition{Message: expr, Level: level})
+}
+
+// Filter opens a block whose steps only run for exchanges matching p.
+// Close it with End().
+func (r *RouteDSL) Filter(p core.Predicate) *RouteDSL {
+	if p == nil {
+		return r.fail("Filter() requires a predicate")
+	}
+	def := &definitions.FilterDefinition{Predicate: p}
+	r.addStep("Filter()", def)
+	r.push(&block{kind: filterBlock, steps: &def.Steps, filter: def})
+	return r
+}
+
+// DiscardTo sends the exchanges the enclosing Filter() does not match to
+// uri. It may be given anywhere inside the Filter() block.
+func (r *RouteDSL) DiscardTo(uri string) *RouteDSL {
+	top := r.top()
+	if top.kind != filterBlock {
+		return r.fail("DiscardTo(%q) must be used inside Filter(), but the innermost open block is %s", uri, blockNames[top.kind])
+	}
+	top.filter.DiscardURI = uri
+	return r
+}
+
+// Choice opens a content-based router. Add branches with When() and
+// Otherwise() and close it with End().
+func (r *RouteDSL) Choice() *RouteDSL {
+	def := &definitions.ChoiceDefinition{}
+	r.addStep("Choice()", def)
+	r.builder.choices = append(r.builder.choices, &choiceRef{def: def, owner: r.owner})
+	r.push(&block{kind: choiceBlock, choice: def})
+	return r
+}
+
+// When adds a branch to the enclosing Choice(). On an interceptor it limits
+// the interceptor to exchanges matching p instead.
+func (r *RouteDSL) When(p core.Predicate) *RouteDSL {
+	if len(r.stack) == 1 && r.intercept != nil {
+		return r.interceptWhen(p)
+	}
+	if p == nil {
+		return r.fail("When() requires a predicate")
+	}
+
+	top := r.top()
+	switch top.kind {
+	case whenBlock:
+		r.pop()
+	case otherwiseBlock:
+		return r.fail("When() cannot follow Otherwise() in the same Choice()")
+	case choiceBlock:
+	default:
+		return r.fail("When() must be used inside Choice(), but the innermost open block is %s; close it with End() first", blockNames[top.kind])
+	}
+
+	choice := r.top().choice
+	when := &definitions.WhenDefinition{Condition: p}
+	choice.WhenClauses = append(choice.WhenClauses, when)
+	r.push(&block{kind: whenBlock, steps: &when.Steps, choice: choice})
+	return r
+}
+
+// Otherwise adds the fallback branch to the enclosing Choice().
+func (r *RouteDSL) Otherwise() *RouteDSL {
+	top := r.top()
+	switch top.kind {
+	case whenBlock:
+		r.pop()
+	case otherwiseBlock:
+		return r.fail("Otherwise() can only be used once per Choice()")
+	case choiceBlock:
+		return r.fail("Otherwise() must follow at least one When()")
+	default:
+		return r.fail("Otherwise() must be used inside Choice(), but the innermost open block is %s; close it with End() first", blockNames[top.kind])
+	}
+
+	choice := r.top().choice
+	r.push(&block{kind: otherwiseBlock, steps: &choice.Otherwise, choice: choice})
+	return r
+}
+
+// End closes the innermost Choice() or Filter().
+func (r *RouteDSL) End() *RouteDSL {
+	switch r.top().kind {
+	case rootBlock:
+		return r.fail("End() called without an open Choice() or Filter()")
+	case whenBlock, otherwiseBlock:
+		r.pop() // the branch
+		r.pop() // the choice itself
+	default:
+		r.pop()
+	}
+	r.last = nil
+	return r
+}
+
+// SkipSendToOriginalEndpoint stops the intercepted exchange from reaching the
+// original endpoint. Only valid right after InterceptSendToEndpoint().
+func (r *RouteDSL) SkipSendToOriginalEndpoint() *RouteDSL {
+	d, ok := r.intercept.(*definitions.InterceptSendToEndpointDefinition)
+	if !ok || len(r.stack) != 1 {
+		return r.fail("SkipSendToOriginalEndpoint() is only valid on InterceptSendToEndpoint()")
+	}
+	d.SkipSendToOriginalEndpoint = true
+	return r
+}
+
+func (r *RouteDSL) interceptWhen(p core.Predicate) *RouteDSL {
+	switch d := r.intercept.(type) {
+	case *definitions.InterceptDefinition:
+		d.When = p
+	case *definitions.InterceptFromDefinition:
+		d.When = p
+	case *definitions.InterceptSendToEndpointDefinition:
+		d.When = p
+	}
+	return r
+}
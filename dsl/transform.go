@@ -0,0 +1,48 @@
+package dsl
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// Transform returns a processor that converts the body to an In with the
+// context's type converter, calls fn and sets its result as the body. Use it
+// with Process:
+//
+//	b.From("direct:orders").Process(dsl.Transform(func(ctx core.Context, o Order) (Invoice, error) { ... }))
+func Transform[In, Out any](fn func(ctx core.Context, in In) (Out, error)) core.Processor {
+	return core.ProcessorFunc(func(ctx core.Context, exchange *core.Exchange) error {
+		in, err := core.ConvertTo[In](ctx, exchange.In().Body())
+		if err != nil {
+			return fmt.Errorf("transform: body: %w", err)
+		}
+		out, err := fn(ctx, in)
+		if err != nil {
+			return err
+		}
+		exchange.In().SetBody(out)
+		return nil
+	})
+}
+
+// Consume returns a processor that converts the body to a T and passes it to
+// fn, leaving the body unchanged.
+func Consume[T any](fn func(T) error) core.Processor {
+	return core.ProcessorFunc(func(ctx core.Context, exchange *core.Exchange) error {
+		v, err := core.ConvertTo[T](ctx, exchange.In().Body())
+		if err != nil {
+			return fmt.Errorf("consume: body: %w", err)
+		}
+		return fn(v)
+	})
+}
+
+// ConvertBodyTo adds a step to r converting the body to a T, like
+// r.ConvertBodyTo with T's type:
+//
+//	dsl.ConvertBodyTo[int](b.From("direct:qty")).To("direct:stock")
+func ConvertBodyTo[T any](r *RouteDSL) *RouteDSL {
+	return r.ConvertBodyTo(reflect.TypeOf((*T)(nil)).Elem())
+}
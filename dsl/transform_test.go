@@ -0,0 +1,102 @@
+package dsl
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/language"
+)
+
+type order struct {
+	ID  string
+	Qty int
+}
+
+func TestTransformAndConsume(t *testing.T) {
+	var consumed []int
+	toID := Transform(func(ctx core.Context, o order) (string, error) {
+		return o.ID, nil
+	})
+	ctx, mock := newTestContext(t, func(b *BaseRouteBuilder) {
+		b.From("mock:in").RouteID("r1").
+			Process(Consume(func(qty int) error {
+				consumed = append(consumed, qty)
+				return nil
+			})).
+			Process(Transform(func(ctx core.Context, qty int) (*order, error) {
+				return &order{ID: "o-" + strconv.Itoa(qty), Qty: qty}, nil
+			})).
+			Process(toID).
+			To("mock:out")
+		b.From("mock:typed").RouteID("r2").Process(toID).To("mock:out")
+	})
+
+	send(t, ctx, "r1", "7")
+	if len(consumed) != 1 || consumed[0] != 7 {
+		t.Errorf("expected the body converted to int, got %v", consumed)
+	}
+	if got := mock.bodies("mock:out"); len(got) != 1 || got[0] != "o-7" {
+		t.Errorf("expected the transformed body, got %v", got)
+	}
+
+	ex := ctx.NewExchange()
+	ex.In().SetBody("o-1")
+	err := ctx.Route("r2").Pipeline.Process(ctx, ex)
+	if err == nil || !strings.Contains(err.Error(), "transform: body: cannot convert string to dsl.order") {
+		t.Errorf("expected a type error, got %v", err)
+	}
+}
+
+func TestRouteDSL_MessageTransformation(t *testing.T) {
+	ctx, mock := newTestContext(t, func(b *BaseRouteBuilder) {
+		ConvertBodyTo[int](b.From("mock:in").RouteID("r1")).
+			SetProperty("qty", language.Body()).
+			SetHeaders(map[string]core.Expression{
+				"unit":  language.Constant("kg"),
+				"total": language.MustSimple("${body}0"),
+			}).
+			RemoveHeaders("Camel*", "CamelKeep").
+			RemoveHeader("secret").
+			SetBody(language.Header("total")).
+			TransformTemplate("{{.Body}} {{.Headers.unit}} (qty {{.Properties.qty}})").
+			RemoveProperty("qty").
+			To("mock:out")
+	})
+
+	ex := ctx.NewExchange()
+	ex.In().SetBody("4")
+	for _, h := range []string{"CamelFile", "CamelKeep", "secret"} {
+		ex.In().SetHeader(h, "x")
+	}
+	if err := ctx.Route("r1").Pipeline.Process(ctx, ex); err != nil {
+		t.Fatal(err)
+	}
+	if got := mock.bodies("mock:out"); len(got) != 1 || got[0] != "40 kg (qty 4)" {
+		t.Errorf("unexpected body %v", got)
+	}
+	headers := ex.In().Headers()
+	if _, ok := headers["CamelFile"]; ok {
+		t.Error("expected CamelFile to be removed")
+	}
+	if _, ok := headers["secret"]; ok {
+		t.Error("expected secret to be removed")
+	}
+	if headers["CamelKeep"] != "x" {
+		t.Error("expected the excluded header to be kept")
+	}
+	if _, ok := ex.Properties()["qty"]; ok {
+		t.Error("expected qty to be removed")
+	}
+
+	bad := core.NewContext()
+	bad.SetLoader(NewDSLLoader())
+	bad.RegisterComponent("mock", newMockComponent())
+	err := bad.AddRoutes(&configureFunc{BaseRouteBuilder: &BaseRouteBuilder{}, fn: func(b *BaseRouteBuilder) {
+		b.From("mock:in").TransformTemplate("{{.Body")
+	}})
+	if err == nil || !strings.Contains(err.Error(), "transformTemplate") {
+		t.Errorf("expected an invalid template to fail AddRoutes, got %v", err)
+	}
+}
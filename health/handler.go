@@ -0,0 +1,50 @@
+// Package health serves a context's health checks over HTTP.
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// Handler serves /health/live and /health/ready from a HealthCheckRegistry.
+// Responses are JSON reports with status 200 when UP and 503 when DOWN.
+// When mounted under a prefix with http.StripPrefix, paths are matched by
+// their suffix, so "/ops/health/ready" works as well.
+type Handler struct {
+	Registry *core.HealthCheckRegistry
+}
+
+// NewHandler returns a handler for the health checks of ctx.
+func NewHandler(ctx *core.DefaultContext) *Handler {
+	return &Handler{Registry: ctx.HealthCheckRegistry()}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var report core.HealthReport
+	switch path := strings.TrimSuffix(r.URL.Path, "/"); {
+	case strings.HasSuffix(path, "/health/live"):
+		report = h.Registry.Liveness()
+	case strings.HasSuffix(path, "/health/ready"), strings.HasSuffix(path, "/health"):
+		report = h.Registry.Readiness()
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	status := http.StatusOK
+	if report.State == core.HealthDown {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
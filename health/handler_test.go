@@ -0,0 +1,69 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sonyjop/camelgo/component/file"
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/dsl"
+	"github.com/sonyjop/camelgo/internal/routetest"
+)
+
+func get(t *testing.T, h http.Handler, path string) (int, core.HealthReport) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var report core.HealthReport
+	if rec.Code != http.StatusNotFound {
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("invalid JSON from %s: %v", path, err)
+		}
+	}
+	return rec.Code, report
+}
+
+func TestHandler_LiveAndReady(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in.txt")
+	os.WriteFile(input, []byte("line\n"), 0o644)
+
+	ctx := routetest.NewContext()
+	ctx.RegisterComponent("file", file.NewFileComponent())
+	// Added without routetest.Start so readiness can be checked before start.
+	err := ctx.AddRoutes(routetest.NewRoutes(func(b *dsl.BaseRouteBuilder) {
+		b.From("file:" + input).RouteID("reader").To("file:" + os.DevNull)
+	}))
+	if err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	h := NewHandler(ctx)
+
+	if code, _ := get(t, h, "/health/ready"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 before start, got %d", code)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	defer ctx.Stop()
+
+	if code, report := get(t, h, "/health/ready"); code != http.StatusOK || report.State != core.HealthUp {
+		t.Errorf("expected ready, got %d %+v", code, report)
+	}
+
+	os.Remove(input)
+	code, report := get(t, h, "/ops/health/ready/")
+	if code != http.StatusServiceUnavailable || report.State != core.HealthDown {
+		t.Errorf("expected not ready once the file is gone, got %d %+v", code, report)
+	}
+	if code, _ := get(t, h, "/health/live"); code != http.StatusOK {
+		t.Errorf("expected live, got %d", code)
+	}
+	if code, _ := get(t, h, "/health/other"); code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", code)
+	}
+}
@@ -0,0 +1,63 @@
+// Package routetest has helpers for tests that run routes built with the
+// Go DSL.
+package routetest
+
+import (
+	"testing"
+
+	"github.com/sonyjop/camelgo/component/direct"
+	"github.com/sonyjop/camelgo/component/seda"
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/dsl"
+)
+
+// Routes is a route builder configured by a function.
+type Routes struct {
+	dsl.BaseRouteBuilder
+	fn func(b *dsl.BaseRouteBuilder)
+}
+
+func NewRoutes(fn func(b *dsl.BaseRouteBuilder)) *Routes {
+	return &Routes{fn: fn}
+}
+
+func (r *Routes) Configure() {
+	r.fn(&r.BaseRouteBuilder)
+}
+
+// NewContext returns a context with the direct and seda components and the
+// DSL loader.
+func NewContext() *core.DefaultContext {
+	ctx := core.NewContext()
+	ctx.RegisterComponent("direct", direct.NewDirectComponent())
+	ctx.RegisterComponent("seda", seda.NewSedaComponent())
+	ctx.SetLoader(dsl.NewDSLLoader())
+	return ctx
+}
+
+// Start adds the routes configured by fn to ctx and starts it. The context
+// is stopped when the test ends.
+func Start(t testing.TB, ctx core.Context, fn func(b *dsl.BaseRouteBuilder)) {
+	t.Helper()
+	if err := ctx.AddRoutes(NewRoutes(fn)); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	t.Cleanup(func() { ctx.Stop() })
+}
+
+// Send sends a new exchange with body and headers to uri and returns it.
+func Send(ctx core.Context, uri string, body interface{}, headers map[string]interface{}) (*core.Exchange, error) {
+	producer, err := ctx.CreateProducer(uri)
+	if err != nil {
+		return nil, err
+	}
+	ex := ctx.NewExchange()
+	ex.In().SetBody(body)
+	for k, v := range headers {
+		ex.In().SetHeader(k, v)
+	}
+	return ex, producer.Process(ctx, ex)
+}
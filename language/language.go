@@ -0,0 +1,78 @@
+// Package language provides ready-made expressions and predicates for routes.
+package language
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// Constant always evaluates to v.
+func Constant(v interface{}) core.Expression {
+	return core.ExpressionFunc(func(ctx core.Context, exchange *core.Exchange) (interface{}, error) {
+		return v, nil
+	})
+}
+
+// Body evaluates to the body of the in message.
+func Body() core.Expression {
+	return core.ExpressionFunc(func(ctx core.Context, exchange *core.Exchange) (interface{}, error) {
+		return exchange.In().Body(), nil
+	})
+}
+
+// Header evaluates to the named header of the in message.
+func Header(name string) core.Expression {
+	return core.ExpressionFunc(func(ctx core.Context, exchange *core.Exchange) (interface{}, error) {
+		return exchange.In().Header(name), nil
+	})
+}
+
+// Property evaluates to the named exchange property.
+func Property(name string) core.Expression {
+	return core.ExpressionFunc(func(ctx core.Context, exchange *core.Exchange) (interface{}, error) {
+		return exchange.GetProperty(name), nil
+	})
+}
+
+// Equals matches when expr evaluates to a value equal to v.
+func Equals(expr core.Expression, v interface{}) core.Predicate {
+	return core.PredicateFunc(func(ctx core.Context, exchange *core.Exchange) (bool, error) {
+		got, err := expr.Evaluate(ctx, exchange)
+		if err != nil {
+			return false, err
+		}
+		return got == v, nil
+	})
+}
+
+// Ref evaluates the core.Expression bound under name in the registry of the
+// context. The lookup happens on each evaluation, so the bean may be bound
+// after the route is added.
+func Ref(name string) core.Expression {
+	return core.ExpressionFunc(func(ctx core.Context, exchange *core.Exchange) (interface{}, error) {
+		if ctx == nil {
+			return nil, fmt.Errorf("ref %s: no context to look the bean up in", name)
+		}
+		expr, err := core.Lookup[core.Expression](ctx.Registry(), name)
+		if err != nil {
+			return nil, err
+		}
+		return expr.Evaluate(ctx, exchange)
+	})
+}
+
+// RefPredicate matches with the core.Predicate bound under name in the
+// registry of the context.
+func RefPredicate(name string) core.Predicate {
+	return core.PredicateFunc(func(ctx core.Context, exchange *core.Exchange) (bool, error) {
+		if ctx == nil {
+			return false, fmt.Errorf("ref %s: no context to look the bean up in", name)
+		}
+		p, err := core.Lookup[core.Predicate](ctx.Registry(), name)
+		if err != nil {
+			return false, err
+		}
+		return p.Evaluate(ctx, exchange)
+	})
+}
@@ -0,0 +1,330 @@
+package language
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// simplePart is either literal text or a ${...} function or {{...}}
+// property placeholder.
+type simplePart struct {
+	text string
+	fn   func(ctx core.Context, exchange *core.Exchange) (interface{}, error)
+}
+
+// Simple parses a template mixing text and ${...} functions:
+//
+//	${body}                  the in message body
+//	${header.name}           an in message header (also ${headers.name})
+//	${exchangeProperty.name} an exchange property (also ${property.name})
+//	${exchangeId}, ${breadcrumbId}, ${routeId}, ${stepId}
+//	{{key}}, {{key:default}}   a property, resolved through the context
+//
+// A template that is a single function evaluates to the raw value; anything
+// else evaluates to a string. Unknown functions are reported here rather
+// than when the expression is evaluated.
+func Simple(template string) (core.Expression, error) {
+	parts, err := parseSimple(template)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 1 && parts[0].fn != nil {
+		fn := parts[0].fn
+		return core.ExpressionFunc(fn), nil
+	}
+	return core.ExpressionFunc(func(ctx core.Context, exchange *core.Exchange) (interface{}, error) {
+		var sb strings.Builder
+		for _, p := range parts {
+			if p.fn == nil {
+				sb.WriteString(p.text)
+				continue
+			}
+			v, err := p.fn(ctx, exchange)
+			if err != nil {
+				return nil, err
+			}
+			if v != nil {
+				fmt.Fprint(&sb, v)
+			}
+		}
+		return sb.String(), nil
+	}), nil
+}
+
+// MustSimple is like Simple but panics on invalid templates. It is meant
+// for templates that are constants in code.
+func MustSimple(template string) core.Expression {
+	expr, err := Simple(template)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// SimplePredicate parses template like Simple and matches exchanges for
+// which it evaluates to true or "true". A template may also compare two
+// operands with ==, !=, <, <=, > or >=, as in ${header.kind} == 'order'.
+// Operands are functions, 'quoted' or "quoted" text, null, or bare text
+// such as numbers; they are compared as numbers when both are numeric and
+// as text otherwise. Text around functions outside of a comparison can
+// never be true, so it is rejected. Templates without functions or
+// placeholders are core.StaticPredicates.
+func SimplePredicate(template string) (core.Predicate, error) {
+	if left, op, right, ok := splitOperator(template); ok {
+		return simpleComparison(template, left, op, right)
+	}
+	parts, err := parseSimple(template)
+	if err != nil {
+		return nil, err
+	}
+	static, text := hasNoFunctions(parts), false
+	for _, part := range parts {
+		if part.fn == nil && strings.TrimSpace(part.text) != "" {
+			text = true
+		}
+	}
+	if text && !static {
+		return nil, fmt.Errorf("simple %q: text around functions is never true; compare with an operator such as ==", template)
+	}
+	expr, err := Simple(template)
+	if err != nil {
+		return nil, err
+	}
+	return &simplePredicate{expr: expr, static: static}, nil
+}
+
+func hasNoFunctions(parts []simplePart) bool {
+	for _, part := range parts {
+		if part.fn != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// simpleOperators are the comparison operators of predicates. Two-character
+// operators come first so that <= is not read as <.
+var simpleOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// splitOperator finds the first operator surrounded by spaces outside of
+// functions, placeholders and quotes.
+func splitOperator(template string) (left, op, right string, ok bool) {
+	for i := 0; i < len(template); i++ {
+		switch {
+		case strings.HasPrefix(template[i:], "${"), strings.HasPrefix(template[i:], "{{"):
+			closing := "}"
+			if template[i] == '{' {
+				closing = "}}"
+			}
+			end := strings.Index(template[i:], closing)
+			if end < 0 {
+				return "", "", "", false
+			}
+			i += end + len(closing) - 1
+		case template[i] == '\'' || template[i] == '"':
+			end := strings.IndexByte(template[i+1:], template[i])
+			if end < 0 {
+				return "", "", "", false
+			}
+			i += end + 1
+		case template[i] == ' ':
+			for _, op := range simpleOperators {
+				if strings.HasPrefix(template[i+1:], op+" ") {
+					return template[:i], op, template[i+len(op)+2:], true
+				}
+			}
+		}
+	}
+	return "", "", "", false
+}
+
+func simpleComparison(template, left, op, right string) (core.Predicate, error) {
+	l, lStatic, err := simpleOperand(template, left)
+	if err != nil {
+		return nil, err
+	}
+	r, rStatic, err := simpleOperand(template, right)
+	if err != nil {
+		return nil, err
+	}
+	expr := core.ExpressionFunc(func(ctx core.Context, exchange *core.Exchange) (interface{}, error) {
+		a, err := l.Evaluate(ctx, exchange)
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.Evaluate(ctx, exchange)
+		if err != nil {
+			return nil, err
+		}
+		return compare(ctx, a, op, b), nil
+	})
+	return &simplePredicate{expr: expr, static: lStatic && rStatic}, nil
+}
+
+// simpleOperand parses one side of a comparison and reports whether it is
+// free of functions and placeholders.
+func simpleOperand(template, operand string) (core.Expression, bool, error) {
+	operand = strings.TrimSpace(operand)
+	if operand == "" {
+		return nil, false, fmt.Errorf("simple %q: missing operand", template)
+	}
+	if operand == "null" {
+		return Constant(nil), true, nil
+	}
+	if n := len(operand); n >= 2 && (operand[0] == '\'' || operand[0] == '"') && operand[n-1] == operand[0] {
+		operand = operand[1 : n-1]
+	}
+	parts, err := parseSimple(operand)
+	if err != nil {
+		return nil, false, err
+	}
+	expr, err := Simple(operand)
+	if err != nil {
+		return nil, false, err
+	}
+	return expr, hasNoFunctions(parts), nil
+}
+
+// compare applies op to a and b, as numbers when both are numeric and as
+// text otherwise. null equals only null and is not ordered.
+func compare(ctx core.Context, a interface{}, op string, b interface{}) bool {
+	if a == nil || b == nil {
+		switch op {
+		case "==":
+			return a == nil && b == nil
+		case "!=":
+			return (a == nil) != (b == nil)
+		}
+		return false
+	}
+	var c int
+	x, errA := core.ConvertTo[float64](ctx, a)
+	y, errB := core.ConvertTo[float64](ctx, b)
+	if errA == nil && errB == nil {
+		c = cmp.Compare(x, y)
+	} else {
+		c = strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+	switch op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+type simplePredicate struct {
+	expr   core.Expression
+	static bool
+}
+
+func (p *simplePredicate) Evaluate(ctx core.Context, exchange *core.Exchange) (bool, error) {
+	v, err := p.expr.Evaluate(ctx, exchange)
+	if err != nil {
+		return false, err
+	}
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case string:
+		return b == "true", nil
+	}
+	return false, nil
+}
+
+func (p *simplePredicate) StaticValue() (bool, bool) {
+	if !p.static {
+		return false, false
+	}
+	v, _ := p.Evaluate(nil, nil)
+	return v, true
+}
+
+func parseSimple(template string) ([]simplePart, error) {
+	var parts []simplePart
+	rest := template
+	for {
+		start, opening, closing := strings.Index(rest, "${"), "${", "}"
+		if p := strings.Index(rest, "{{"); p >= 0 && (start < 0 || p < start) {
+			start, opening, closing = p, "{{", "}}"
+		}
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], closing)
+		if end < 0 {
+			return nil, fmt.Errorf("simple %q: unclosed %s", template, opening)
+		}
+		if start > 0 {
+			parts = append(parts, simplePart{text: rest[:start]})
+		}
+		text := rest[start : start+end+len(closing)]
+		if opening == "{{" {
+			parts = append(parts, simplePart{text: text, fn: placeholder(text)})
+		} else {
+			fn, err := simpleFunction(strings.TrimSpace(rest[start+2 : start+end]))
+			if err != nil {
+				return nil, fmt.Errorf("simple %q: %w", template, err)
+			}
+			parts = append(parts, simplePart{text: text, fn: fn})
+		}
+		rest = rest[start+len(text):]
+	}
+	if rest != "" || len(parts) == 0 {
+		parts = append(parts, simplePart{text: rest})
+	}
+	return parts, nil
+}
+
+// placeholder resolves a {{...}} placeholder through the context.
+func placeholder(text string) func(ctx core.Context, exchange *core.Exchange) (interface{}, error) {
+	return func(ctx core.Context, exchange *core.Exchange) (interface{}, error) {
+		return core.ResolvePlaceholders(ctx, text)
+	}
+}
+
+func simpleFunction(name string) (func(ctx core.Context, exchange *core.Exchange) (interface{}, error), error) {
+	fn, err := exchangeFunction(name)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx core.Context, exchange *core.Exchange) (interface{}, error) {
+		return fn(exchange), nil
+	}, nil
+}
+
+func exchangeFunction(name string) (func(exchange *core.Exchange) interface{}, error) {
+	switch name {
+	case "body", "in.body":
+		return func(ex *core.Exchange) interface{} { return ex.In().Body() }, nil
+	case "exchangeId":
+		return func(ex *core.Exchange) interface{} { return ex.ID() }, nil
+	case "breadcrumbId":
+		return func(ex *core.Exchange) interface{} { return ex.In().Header(core.BreadcrumbIDHeader) }, nil
+	case "routeId":
+		return func(ex *core.Exchange) interface{} { return ex.CurrentRouteID() }, nil
+	case "stepId":
+		return func(ex *core.Exchange) interface{} { return ex.CurrentStepID() }, nil
+	}
+	for _, prefix := range []string{"header.", "headers.", "in.header.", "in.headers."} {
+		if key := strings.TrimPrefix(name, prefix); key != name && key != "" {
+			return func(ex *core.Exchange) interface{} { return ex.In().Header(key) }, nil
+		}
+	}
+	for _, prefix := range []string{"exchangeProperty.", "property."} {
+		if key := strings.TrimPrefix(name, prefix); key != name && key != "" {
+			return func(ex *core.Exchange) interface{} { return ex.GetProperty(key) }, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown function ${%s}", name)
+}
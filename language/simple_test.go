@@ -0,0 +1,147 @@
+package language
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+func TestSimple(t *testing.T) {
+	ex := core.NewExchange()
+	ex.In().SetBody(42)
+	ex.In().SetHeader("region", "eu")
+	ex.SetProperty("tenant", "acme")
+
+	cases := []struct {
+		template string
+		want     interface{}
+	}{
+		{"${body}", 42},
+		{"${header.region}", "eu"},
+		{"${in.header.region}", "eu"},
+		{"${exchangeProperty.tenant}", "acme"},
+		{"order ${body} for ${headers.region}/${property.tenant}", "order 42 for eu/acme"},
+		{"missing [${header.nope}]", "missing []"},
+		{"plain text", "plain text"},
+		{"", ""},
+	}
+	for _, c := range cases {
+		expr, err := Simple(c.template)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", c.template, err)
+			continue
+		}
+		got, _ := expr.Evaluate(nil, ex)
+		if got != c.want {
+			t.Errorf("%q: expected %v, got %v", c.template, c.want, got)
+		}
+	}
+}
+
+func TestSimple_Invalid(t *testing.T) {
+	for _, template := range []string{"${unknown}", "${header.}", "oops ${body"} {
+		if _, err := Simple(template); err == nil {
+			t.Errorf("%q: expected error", template)
+		}
+	}
+}
+
+// mapResolver resolves {{key}} placeholders from a map.
+type mapResolver map[string]string
+
+func (m mapResolver) ResolvePlaceholders(text string) (string, error) {
+	key := strings.TrimSuffix(strings.TrimPrefix(text, "{{"), "}}")
+	v, ok := m[key]
+	if !ok {
+		return "", fmt.Errorf("property %q not found", key)
+	}
+	return v, nil
+}
+
+func TestSimple_Placeholders(t *testing.T) {
+	ctx := core.NewContext()
+	ctx.SetPropertiesResolver(mapResolver{"greeting": "hello"})
+	ex := core.NewExchange()
+	ex.In().SetBody("world")
+
+	expr := MustSimple("{{greeting}} ${body}")
+	if got, err := expr.Evaluate(ctx, ex); err != nil || got != "hello world" {
+		t.Errorf("expected the placeholder to be resolved, got %v (%v)", got, err)
+	}
+	if _, err := MustSimple("{{missing}}").Evaluate(ctx, ex); err == nil {
+		t.Error("expected an unresolvable placeholder to fail")
+	}
+	if _, err := Simple("oops {{greeting"); err == nil {
+		t.Error("expected an unclosed placeholder to fail")
+	}
+}
+
+func TestSimplePredicate(t *testing.T) {
+	ex := core.NewExchange()
+	ex.In().SetHeader("vip", "true")
+	ex.In().SetHeader("flag", true)
+	ex.In().SetHeader("region", "eu")
+	ex.In().SetHeader("count", "10")
+
+	for template, want := range map[string]bool{
+		"${header.vip}":    true,
+		"${header.flag}":   true,
+		"${header.region}": false,
+		"${header.nope}":   false,
+		"true":             true,
+
+		"${header.region} == 'eu'":               true,
+		"${header.region} != \"eu\"":             false,
+		"${header.region} == eu":                 true,
+		"${header.nope} == null":                 true,
+		"${header.region} == null":               false,
+		"${header.count} > 9":                    true,
+		"${header.count} <= 9":                   false,
+		"${header.count} == 10.0":                true,
+		"${header.region} < 'fr'":                true,
+		"${header.flag} == true":                 true,
+		"'a b == c' == 'a b == c'":               true,
+		"${header.region} == '${header.region}'": true,
+	} {
+		p, err := SimplePredicate(template)
+		if err != nil {
+			t.Fatalf("%q: unexpected error %v", template, err)
+		}
+		if got, _ := p.Evaluate(nil, ex); got != want {
+			t.Errorf("%q: expected %v, got %v", template, want, got)
+		}
+	}
+}
+
+func TestSimplePredicate_Static(t *testing.T) {
+	for template, want := range map[string][2]bool{
+		"true":          {true, true},
+		"false":         {false, true},
+		"${header.vip}": {false, false},
+		"1 < 2":         {true, true},
+		"1 == ${body}":  {false, false},
+		"{{enabled}}":   {false, false},
+	} {
+		p, _ := SimplePredicate(template)
+		value, ok := p.(core.StaticPredicate).StaticValue()
+		if value != want[0] || ok != want[1] {
+			t.Errorf("%q: expected %v, got %v %v", template, want, value, ok)
+		}
+	}
+}
+
+func TestSimplePredicate_Errors(t *testing.T) {
+	for template, want := range map[string]string{
+		"${header.kind} = 'order'": "text around functions is never true",
+		"x ${body}":                "text around functions is never true",
+		"${header.kind} == ":       "missing operand",
+		"${nope} == 1":             "unknown function",
+	} {
+		_, err := SimplePredicate(template)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected an error mentioning %q, got %v", template, want, err)
+		}
+	}
+}
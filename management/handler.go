@@ -0,0 +1,255 @@
+// Package management exposes a running context over HTTP as JSON.
+//
+// The handler is opt-in and can be mounted into an existing mux (use
+// http.StripPrefix when mounting under a prefix) or served on its own with
+// Server:
+//
+//	GET    /context                  context status and uptime
+//	GET    /components               registered components
+//	GET    /endpoints                resolved endpoints
+//	GET    /routes                   routes with status and statistics
+//	GET    /routes/{id}              a single route
+//	GET    /routes/{id}/definition   the route's definition tree
+//	POST   /routes/{id}/{action}     start, stop, suspend, resume or reset
+//	GET    /graph?format=dot&counts=1 route graph (dot, mermaid or plantuml)
+//	GET    /inflight                 exchanges currently being routed
+//	GET    /failures                 recent failures (DELETE clears them)
+package management
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// TokenHeader is an alternative to "Authorization: Bearer <token>".
+const TokenHeader = "X-CamelGo-Token"
+
+// Handler serves the management API of one context.
+type Handler struct {
+	context *core.DefaultContext
+	token   string
+	mux     *http.ServeMux
+}
+
+// NewHandler returns the management API for ctx. When token is not empty,
+// every request must present it.
+func NewHandler(ctx *core.DefaultContext, token string) *Handler {
+	h := &Handler{context: ctx, token: token, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /context", h.getContext)
+	h.mux.HandleFunc("GET /components", h.getComponents)
+	h.mux.HandleFunc("GET /endpoints", h.getEndpoints)
+	h.mux.HandleFunc("GET /routes", h.getRoutes)
+	h.mux.HandleFunc("GET /routes/{id}", h.getRoute)
+	h.mux.HandleFunc("GET /routes/{id}/definition", h.getRouteDefinition)
+	h.mux.HandleFunc("POST /routes/{id}/{action}", h.controlRoute)
+	h.mux.HandleFunc("GET /graph", h.getGraph)
+	h.mux.HandleFunc("GET /inflight", h.getInflight)
+	h.mux.HandleFunc("GET /failures", h.getFailures)
+	h.mux.HandleFunc("DELETE /failures", h.clearFailures)
+	return h
+}
+
+// Handle registers an additional handler under pattern, behind the same
+// token check. Other packages use it to extend the API.
+func (h *Handler) Handle(pattern string, handler http.Handler) {
+	h.mux.Handle(pattern, handler)
+}
+
+// Context returns the managed context.
+func (h *Handler) Context() *core.DefaultContext {
+	return h.context
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="camelgo"`)
+		WriteError(w, http.StatusUnauthorized, "missing or invalid token")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) authorized(r *http.Request) bool {
+	if h.token == "" {
+		return true
+	}
+	got := r.Header.Get(TokenHeader)
+	if auth := r.Header.Get("Authorization"); got == "" && strings.HasPrefix(auth, "Bearer ") {
+		got = strings.TrimPrefix(auth, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(h.token)) == 1
+}
+
+// WriteJSON writes v as a JSON response with the given status.
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// WriteError writes {"error": msg} with the given status.
+func WriteError(w http.ResponseWriter, status int, msg string) {
+	WriteJSON(w, status, map[string]string{"error": msg})
+}
+
+type contextInfo struct {
+	Status   string        `json:"status"`
+	Uptime   time.Duration `json:"uptime"`
+	Routes   int           `json:"routes"`
+	Inflight int           `json:"inflight"`
+}
+
+func (h *Handler) getContext(w http.ResponseWriter, r *http.Request) {
+	WriteJSON(w, http.StatusOK, contextInfo{
+		Status:   h.context.Status().String(),
+		Uptime:   h.context.Uptime(),
+		Routes:   len(h.context.Routes()),
+		Inflight: h.context.Inflight().Size(),
+	})
+}
+
+type componentInfo struct {
+	Scheme string `json:"scheme"`
+	Type   string `json:"type"`
+}
+
+func (h *Handler) getComponents(w http.ResponseWriter, r *http.Request) {
+	components := h.context.Components()
+	out := make([]componentInfo, 0, len(components))
+	for scheme, c := range components {
+		out = append(out, componentInfo{Scheme: scheme, Type: fmt.Sprintf("%T", c)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Scheme < out[j].Scheme })
+	WriteJSON(w, http.StatusOK, out)
+}
+
+type endpointInfo struct {
+	URI           string `json:"uri"`
+	Type          string `json:"type"`
+	QueueDepth    *int   `json:"queueDepth,omitempty"`
+	QueueCapacity *int   `json:"queueCapacity,omitempty"`
+}
+
+func (h *Handler) getEndpoints(w http.ResponseWriter, r *http.Request) {
+	endpoints := h.context.Endpoints()
+	out := make([]endpointInfo, 0, len(endpoints))
+	for _, ep := range endpoints {
+		info := endpointInfo{URI: h.context.MaskURI(ep.GetURI()), Type: fmt.Sprintf("%T", ep)}
+		if q, ok := ep.(core.QueueEndpoint); ok {
+			depth, capacity := q.QueueDepth(), q.QueueCapacity()
+			info.QueueDepth, info.QueueCapacity = &depth, &capacity
+		}
+		out = append(out, info)
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
+
+type routeInfo struct {
+	ID         string               `json:"id"`
+	InputURI   string               `json:"from"`
+	Status     string               `json:"status"`
+	Statistics core.RouteStatistics `json:"statistics"`
+}
+
+func (h *Handler) routeInfo(r *core.Route) routeInfo {
+	return routeInfo{ID: r.ID, InputURI: h.context.MaskURI(r.InputURI), Status: r.Status().String(), Statistics: r.Statistics()}
+}
+
+func (h *Handler) getRoutes(w http.ResponseWriter, r *http.Request) {
+	routes := h.context.Routes()
+	out := make([]routeInfo, 0, len(routes))
+	for _, route := range routes {
+		out = append(out, h.routeInfo(route))
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
+
+func (h *Handler) route(w http.ResponseWriter, r *http.Request) *core.Route {
+	id := r.PathValue("id")
+	route := h.context.Route(id)
+	if route == nil {
+		WriteError(w, http.StatusNotFound, "no route with ID "+id)
+	}
+	return route
+}
+
+func (h *Handler) getRoute(w http.ResponseWriter, r *http.Request) {
+	if route := h.route(w, r); route != nil {
+		WriteJSON(w, http.StatusOK, h.routeInfo(route))
+	}
+}
+
+func (h *Handler) getRouteDefinition(w http.ResponseWriter, r *http.Request) {
+	if route := h.route(w, r); route != nil {
+		WriteJSON(w, http.StatusOK, route.Dump())
+	}
+}
+
+func (h *Handler) controlRoute(w http.ResponseWriter, r *http.Request) {
+	route := h.route(w, r)
+	if route == nil {
+		return
+	}
+	var err error
+	switch action := r.PathValue("action"); action {
+	case "start":
+		err = route.Start(h.context)
+	case "stop":
+		err = route.Stop(h.context)
+	case "suspend":
+		err = route.Suspend(h.context)
+	case "resume":
+		err = route.Resume(h.context)
+	case "reset":
+		route.ResetStatistics()
+	default:
+		WriteError(w, http.StatusNotFound, "unknown action "+action)
+		return
+	}
+	if err != nil {
+		WriteError(w, http.StatusConflict, err.Error())
+		return
+	}
+	WriteJSON(w, http.StatusOK, h.routeInfo(route))
+}
+
+func (h *Handler) getGraph(w http.ResponseWriter, r *http.Request) {
+	format := core.GraphFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = core.GraphDOT
+	}
+	counts, _ := strconv.ParseBool(r.URL.Query().Get("counts"))
+	out, err := h.context.DumpRouteGraph(format, counts)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, out)
+}
+
+func (h *Handler) getInflight(w http.ResponseWriter, r *http.Request) {
+	WriteJSON(w, http.StatusOK, h.context.Inflight().Browse())
+}
+
+func (h *Handler) getFailures(w http.ResponseWriter, r *http.Request) {
+	out := h.context.FailureHistory().Recent()
+	if out == nil {
+		out = []core.FailureRecord{}
+	}
+	WriteJSON(w, http.StatusOK, out)
+}
+
+func (h *Handler) clearFailures(w http.ResponseWriter, r *http.Request) {
+	h.context.FailureHistory().Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
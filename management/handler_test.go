@@ -0,0 +1,213 @@
+package management
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/dsl"
+	"github.com/sonyjop/camelgo/internal/routetest"
+	"github.com/sonyjop/camelgo/language"
+)
+
+// gate holds exchanges in the slow route until released.
+type gate struct {
+	release chan struct{}
+	entered chan struct{}
+}
+
+func newManagedContext(t *testing.T) (*core.DefaultContext, *gate, http.Handler) {
+	t.Helper()
+	g := &gate{release: make(chan struct{}), entered: make(chan struct{})}
+	ctx := routetest.NewContext()
+	routetest.Start(t, ctx, func(b *dsl.BaseRouteBuilder) {
+		b.From("direct:orders").RouteID("orders").
+			Choice().
+			When(language.Equals(language.Body(), "bad")).
+			Process(core.ProcessorFunc(func(ctx core.Context, ex *core.Exchange) error {
+				return errors.New("rejected")
+			})).ID("reject").
+			Otherwise().
+			To("seda:accepted").
+			End()
+		b.From("direct:slow").RouteID("slow").Process(core.ProcessorFunc(func(ctx core.Context, ex *core.Exchange) error {
+			g.entered <- struct{}{}
+			<-g.release
+			return nil
+		}))
+	})
+	return ctx, g, NewHandler(ctx, "s3cret")
+}
+
+func call(t *testing.T, h http.Handler, method, path string, out interface{}) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if out != nil && rec.Code < 300 {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: invalid JSON: %v", method, path, err)
+		}
+	}
+	return rec.Code
+}
+
+func TestHandler_RequiresToken(t *testing.T) {
+	_, _, h := newManagedContext(t)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/routes", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without token, got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/routes", nil)
+	req.Header.Set(TokenHeader, "s3cret")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 with token header, got %d", rec.Code)
+	}
+}
+
+func TestHandler_RoutesStatisticsAndFailures(t *testing.T) {
+	ctx, _, h := newManagedContext(t)
+	routetest.Send(ctx, "direct:orders", "good", nil)
+	routetest.Send(ctx, "direct:orders", "bad", nil)
+
+	var route routeInfo
+	if code := call(t, h, http.MethodGet, "/routes/orders", &route); code != http.StatusOK {
+		t.Fatalf("GET route: %d", code)
+	}
+	if route.Status != "Started" || route.Statistics.ExchangesTotal != 2 || route.Statistics.ExchangesFailed != 1 {
+		t.Errorf("unexpected route info %+v", route)
+	}
+
+	var failures []core.FailureRecord
+	call(t, h, http.MethodGet, "/failures", &failures)
+	if len(failures) != 1 || failures[0].RouteID != "orders" || failures[0].StepID != "reject" || failures[0].Error != "rejected" {
+		t.Errorf("unexpected failures %+v", failures)
+	}
+	if code := call(t, h, http.MethodDelete, "/failures", nil); code != http.StatusNoContent {
+		t.Errorf("DELETE failures: %d", code)
+	}
+	call(t, h, http.MethodGet, "/failures", &failures)
+	if len(failures) != 0 {
+		t.Errorf("expected failures to be cleared, got %+v", failures)
+	}
+
+	var endpoints []endpointInfo
+	call(t, h, http.MethodGet, "/endpoints", &endpoints)
+	var queued bool
+	for _, ep := range endpoints {
+		if ep.URI == "seda:accepted" && ep.QueueDepth != nil && *ep.QueueDepth == 1 {
+			queued = true
+		}
+	}
+	if !queued {
+		t.Errorf("expected seda:accepted with queue depth 1 in %+v", endpoints)
+	}
+
+	var components []componentInfo
+	call(t, h, http.MethodGet, "/components", &components)
+	if len(components) != 2 || components[0].Scheme != "direct" {
+		t.Errorf("unexpected components %+v", components)
+	}
+}
+
+func TestHandler_ControlAndDefinition(t *testing.T) {
+	_, _, h := newManagedContext(t)
+
+	var route routeInfo
+	if code := call(t, h, http.MethodPost, "/routes/orders/suspend", &route); code != http.StatusOK || route.Status != "Suspended" {
+		t.Errorf("suspend: %d %+v", code, route)
+	}
+	if code := call(t, h, http.MethodPost, "/routes/orders/resume", &route); code != http.StatusOK || route.Status != "Started" {
+		t.Errorf("resume: %d %+v", code, route)
+	}
+	if code := call(t, h, http.MethodPost, "/routes/orders/stop", &route); code != http.StatusOK || route.Status != "Stopped" {
+		t.Errorf("stop: %d %+v", code, route)
+	}
+	if code := call(t, h, http.MethodPost, "/routes/orders/explode", nil); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown action, got %d", code)
+	}
+	if code := call(t, h, http.MethodGet, "/routes/missing", nil); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown route, got %d", code)
+	}
+
+	var dump core.RouteDump
+	call(t, h, http.MethodGet, "/routes/orders/definition", &dump)
+	if dump.InputURI != "direct:orders" || len(dump.Steps) != 1 || dump.Steps[0].ID != "choice1" {
+		t.Fatalf("unexpected dump %+v", dump)
+	}
+	branches := dump.Steps[0].Branches
+	if len(branches) != 2 || branches[0].Steps[0].ID != "reject" || branches[1].Steps[0].URI != "seda:accepted" {
+		t.Errorf("unexpected branches %+v", branches)
+	}
+}
+
+func TestHandler_Inflight(t *testing.T) {
+	ctx, g, h := newManagedContext(t)
+	done := make(chan error)
+	go func() {
+		_, err := routetest.Send(ctx, "direct:slow", "x", nil)
+		done <- err
+	}()
+	<-g.entered
+
+	var inflight []core.InflightExchange
+	call(t, h, http.MethodGet, "/inflight", &inflight)
+	if len(inflight) != 1 || inflight[0].FromRouteID != "slow" {
+		t.Errorf("unexpected inflight %+v", inflight)
+	}
+	close(g.release)
+	<-done
+
+	call(t, h, http.MethodGet, "/inflight", &inflight)
+	if len(inflight) != 0 {
+		t.Errorf("expected no inflight exchanges, got %+v", inflight)
+	}
+}
+
+func TestServer_FollowsContextLifecycle(t *testing.T) {
+	ctx := core.NewContext()
+	srv := NewServer("127.0.0.1:0", ctx, "")
+	ctx.AddService(srv)
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+
+	resp, err := http.Get("http://" + srv.ListenAddr() + "/context")
+	if err != nil {
+		t.Fatalf("GET /context: %v", err)
+	}
+	var info contextInfo
+	json.NewDecoder(resp.Body).Decode(&info)
+	resp.Body.Close()
+	if info.Status != "Started" {
+		t.Errorf("unexpected context info %+v", info)
+	}
+
+	ctx.Stop()
+	if _, err := http.Get("http://" + srv.ListenAddr() + "/context"); err == nil {
+		t.Errorf("expected server to be stopped with the context")
+	}
+}
+
+func TestHandler_Graph(t *testing.T) {
+	_, _, h := newManagedContext(t)
+	req := httptest.NewRequest(http.MethodGet, "/graph?format=mermaid", nil)
+	req.Header.Set(TokenHeader, "s3cret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), "flowchart TD") {
+		t.Errorf("unexpected graph response %d %q", rec.Code, rec.Body.String())
+	}
+	if code := call(t, h, http.MethodGet, "/graph?format=svg", nil); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown format, got %d", code)
+	}
+}
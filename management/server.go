@@ -0,0 +1,72 @@
+package management
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// Server serves a Handler on its own address. It is a core.Service, so it
+// can be added to the context with AddService and follow its lifecycle.
+type Server struct {
+	core.ServiceSupport
+
+	Addr    string
+	Handler http.Handler
+
+	// ShutdownTimeout bounds how long Stop waits for open requests.
+	ShutdownTimeout time.Duration
+
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewServer returns a server for the management API of ctx on addr.
+func NewServer(addr string, ctx *core.DefaultContext, token string) *Server {
+	return &Server{Addr: addr, Handler: NewHandler(ctx, token), ShutdownTimeout: 5 * time.Second}
+}
+
+// Start listens on Addr and serves in the background.
+func (s *Server) Start(ctx core.Context) error {
+	return s.DoStart(func() error {
+		ln, err := net.Listen("tcp", s.Addr)
+		if err != nil {
+			return err
+		}
+		s.listener = ln
+		s.server = &http.Server{Handler: s.Handler}
+		go func(srv *http.Server) {
+			if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				core.Log(ctx, "management").WithError(err).Error("management server stopped")
+			}
+		}(s.server)
+		return nil
+	})
+}
+
+// Stop shuts the server down gracefully.
+func (s *Server) Stop(ctx core.Context) error {
+	return s.DoStop(func() error {
+		if s.server == nil {
+			return nil
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+		defer cancel()
+		err := s.server.Shutdown(shutdownCtx)
+		s.server = nil
+		return err
+	})
+}
+
+// ListenAddr returns the address the server is listening on, which is
+// useful with ":0".
+func (s *Server) ListenAddr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
@@ -0,0 +1,189 @@
+// Package metrics exports Prometheus metrics for a camelgo context.
+//
+// Route metrics are labelled with route_id, processor metrics additionally
+// with step_id. Queue depths of endpoints implementing core.QueueEndpoint
+// and the context uptime are collected on scrape.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+const namespace = "camelgo"
+
+// Metrics holds the collectors for one context. It is an InterceptStrategy
+// for route and processor metrics.
+type Metrics struct {
+	reg prometheus.Registerer
+
+	exchanges     *prometheus.CounterVec
+	failed        *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	inflight      *prometheus.GaugeVec
+	stepExchanges *prometheus.CounterVec
+	stepFailed    *prometheus.CounterVec
+	stepDuration  *prometheus.HistogramVec
+	queueDepth    *prometheus.Desc
+	queueCapacity *prometheus.Desc
+	uptime        *prometheus.Desc
+	context       core.Context
+}
+
+// New creates the collectors. They are registered on reg by Install.
+func New(reg prometheus.Registerer) *Metrics {
+	routeLabels := []string{"route_id"}
+	stepLabels := []string{"route_id", "step_id"}
+	return &Metrics{
+		reg: reg,
+		exchanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "exchanges_total",
+			Help: "Exchanges processed by a route.",
+		}, routeLabels),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "exchanges_failed_total",
+			Help: "Exchanges that failed in a route.",
+		}, routeLabels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "exchange_duration_seconds",
+			Help:    "Time taken by a route to process an exchange.",
+			Buckets: prometheus.DefBuckets,
+		}, routeLabels),
+		inflight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "exchanges_inflight",
+			Help: "Exchanges currently being processed by a route.",
+		}, routeLabels),
+		stepExchanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "processor_exchanges_total",
+			Help: "Exchanges processed by a route step.",
+		}, stepLabels),
+		stepFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "processor_exchanges_failed_total",
+			Help: "Exchanges that failed in a route step.",
+		}, stepLabels),
+		stepDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "processor_duration_seconds",
+			Help:    "Time taken by a route step to process an exchange.",
+			Buckets: prometheus.DefBuckets,
+		}, stepLabels),
+		queueDepth: prometheus.NewDesc(namespace+"_endpoint_queue_depth",
+			"Exchanges waiting in an endpoint queue.", []string{"endpoint_uri"}, nil),
+		queueCapacity: prometheus.NewDesc(namespace+"_endpoint_queue_capacity",
+			"Capacity of an endpoint queue.", []string{"endpoint_uri"}, nil),
+		uptime: prometheus.NewDesc(namespace+"_context_uptime_seconds",
+			"Time since the context was started.", nil, nil),
+	}
+}
+
+// Install registers the collectors and hooks them into ctx. Only routes
+// added afterwards are measured, so call it before AddRoutes.
+func (m *Metrics) Install(ctx core.Context) error {
+	m.context = ctx
+	collectors := []prometheus.Collector{
+		m.exchanges, m.failed, m.duration, m.inflight,
+		m.stepExchanges, m.stepFailed, m.stepDuration, contextCollector{m},
+	}
+	for _, c := range collectors {
+		if err := m.reg.Register(c); err != nil {
+			return err
+		}
+	}
+	ctx.AddInterceptStrategy(m)
+	return nil
+}
+
+// Handler serves the metrics gathered from the registry given to New, or
+// from the default gatherer when it is not a prometheus.Gatherer. Mount it
+// at /metrics.
+func (m *Metrics) Handler() http.Handler {
+	g, ok := m.reg.(prometheus.Gatherer)
+	if !ok {
+		g = prometheus.DefaultGatherer
+	}
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) WrapProcessor(ctx core.Context, info core.StepInfo, target core.Processor) (core.Processor, error) {
+	if info.Kind == core.RouteStepKind {
+		return &routeMetricsProcessor{metrics: m, routeID: info.RouteID, target: target}, nil
+	}
+	return &stepMetricsProcessor{
+		total:    m.stepExchanges.WithLabelValues(info.RouteID, info.StepID),
+		failed:   m.stepFailed.WithLabelValues(info.RouteID, info.StepID),
+		duration: m.stepDuration.WithLabelValues(info.RouteID, info.StepID),
+		target:   target,
+	}, nil
+}
+
+type routeMetricsProcessor struct {
+	metrics *Metrics
+	routeID string
+	target  core.Processor
+}
+
+func (p *routeMetricsProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	inflight := p.metrics.inflight.WithLabelValues(p.routeID)
+	inflight.Inc()
+	defer inflight.Dec()
+
+	start := time.Now()
+	err := p.target.Process(ctx, exchange)
+	p.metrics.duration.WithLabelValues(p.routeID).Observe(time.Since(start).Seconds())
+	p.metrics.exchanges.WithLabelValues(p.routeID).Inc()
+	if err != nil || exchange.Error() != nil {
+		p.metrics.failed.WithLabelValues(p.routeID).Inc()
+	}
+	return err
+}
+
+type stepMetricsProcessor struct {
+	total    prometheus.Counter
+	failed   prometheus.Counter
+	duration prometheus.Observer
+	target   core.Processor
+}
+
+func (p *stepMetricsProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	start := time.Now()
+	err := p.target.Process(ctx, exchange)
+	p.duration.Observe(time.Since(start).Seconds())
+	p.total.Inc()
+	if err != nil || exchange.Error() != nil {
+		p.failed.Inc()
+	}
+	return err
+}
+
+// contextCollector reports values read from the context on every scrape.
+type contextCollector struct {
+	m *Metrics
+}
+
+func (c contextCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.m.queueDepth
+	ch <- c.m.queueCapacity
+	ch <- c.m.uptime
+}
+
+// Contexts without these methods report no queue or uptime metrics.
+func (c contextCollector) Collect(ch chan<- prometheus.Metric) {
+	if ctx, ok := c.m.context.(interface{ Endpoints() []core.Endpoint }); ok {
+		for _, ep := range ctx.Endpoints() {
+			q, ok := ep.(core.QueueEndpoint)
+			if !ok {
+				continue
+			}
+			uri := core.MaskURI(c.m.context, q.GetURI())
+			ch <- prometheus.MustNewConstMetric(c.m.queueDepth, prometheus.GaugeValue, float64(q.QueueDepth()), uri)
+			ch <- prometheus.MustNewConstMetric(c.m.queueCapacity, prometheus.GaugeValue, float64(q.QueueCapacity()), uri)
+		}
+	}
+	if ctx, ok := c.m.context.(interface{ Uptime() time.Duration }); ok {
+		ch <- prometheus.MustNewConstMetric(c.m.uptime, prometheus.GaugeValue, ctx.Uptime().Seconds())
+	}
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/dsl"
+	"github.com/sonyjop/camelgo/internal/routetest"
+)
+
+func newMeasuredContext(t *testing.T, fn func(b *dsl.BaseRouteBuilder)) (*core.DefaultContext, *Metrics) {
+	t.Helper()
+	ctx := routetest.NewContext()
+	m := New(prometheus.NewRegistry())
+	if err := m.Install(ctx); err != nil {
+		t.Fatalf("Install error: %v", err)
+	}
+	routetest.Start(t, ctx, fn)
+	return ctx, m
+}
+
+func TestMetrics_RouteAndProcessorCounters(t *testing.T) {
+	ctx, m := newMeasuredContext(t, func(b *dsl.BaseRouteBuilder) {
+		b.From("direct:orders").RouteID("orders").Process(core.ProcessorFunc(func(ctx core.Context, ex *core.Exchange) error {
+			if ex.In().Body() == "bad" {
+				return errors.New("rejected")
+			}
+			return nil
+		})).ID("validate").To("seda:backlog")
+	})
+
+	routetest.Send(ctx, "direct:orders", "good", nil)
+	routetest.Send(ctx, "direct:orders", "good", nil)
+	routetest.Send(ctx, "direct:orders", "bad", nil)
+
+	if got := testutil.ToFloat64(m.exchanges.WithLabelValues("orders")); got != 3 {
+		t.Errorf("expected 3 exchanges, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.failed.WithLabelValues("orders")); got != 1 {
+		t.Errorf("expected 1 failed exchange, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.stepFailed.WithLabelValues("orders", "validate")); got != 1 {
+		t.Errorf("expected 1 failure in step validate, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.stepExchanges.WithLabelValues("orders", "to1")); got != 2 {
+		t.Errorf("expected 2 exchanges in step to1, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.inflight.WithLabelValues("orders")); got != 0 {
+		t.Errorf("expected no in-flight exchanges, got %v", got)
+	}
+	if n := testutil.CollectAndCount(m.duration); n != 1 {
+		t.Errorf("expected one duration series, got %d", n)
+	}
+}
+
+func TestMetrics_Scrape(t *testing.T) {
+	ctx, m := newMeasuredContext(t, func(b *dsl.BaseRouteBuilder) {
+		b.From("direct:in").RouteID("in").To("seda:backlog")
+	})
+	routetest.Send(ctx, "direct:in", "a", nil)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	for _, want := range []string{
+		`camelgo_endpoint_queue_depth{endpoint_uri="seda:backlog"} 1`,
+		`camelgo_exchanges_total{route_id="in"} 1`,
+		"camelgo_context_uptime_seconds",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("expected scrape to contain %q", want)
+		}
+	}
+}
@@ -2,17 +2,21 @@ package processors
 
 import "github.com/sonyjop/camelgo/core"
 
+// ChoiceBranch is one When clause of a ChoiceProcessor.
+type ChoiceBranch struct {
+	Condition core.Predicate
+	Pipeline  core.Processor
+}
+
 // ChoiceProcessor handles Content-Based Routing.
 type ChoiceProcessor struct {
-	Branches []struct {
-		Condition core.Processor
-	}
+	Branches  []ChoiceBranch
 	Otherwise core.Processor
 }
 
 func (c *ChoiceProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
-	/*for _, branch := range c.Branches {
-		match, err := branch.Condition.Evaluate(exchange)
+	for _, branch := range c.Branches {
+		match, err := branch.Condition.Evaluate(ctx, exchange)
 		if err != nil {
 			return err
 		}
@@ -22,6 +26,6 @@ func (c *ChoiceProcessor) Process(ctx core.Context, exchange *core.Exchange) err
 	}
 	if c.Otherwise != nil {
 		return c.Otherwise.Process(ctx, exchange)
-	}*/
+	}
 	return nil
 }
@@ -0,0 +1,23 @@
+package processors
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// ConvertBodyProcessor converts the body of the in message to Type with the
+// context's type converter.
+type ConvertBodyProcessor struct {
+	Type reflect.Type
+}
+
+func (p *ConvertBodyProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	v, err := core.Convert(ctx, exchange.In().Body(), p.Type)
+	if err != nil {
+		return fmt.Errorf("convertBodyTo(%s): %w", p.Type, err)
+	}
+	exchange.In().SetBody(v)
+	return nil
+}
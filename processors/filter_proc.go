@@ -0,0 +1,32 @@
+package processors
+
+import "github.com/sonyjop/camelgo/core"
+
+// FilterMatchedProperty is the exchange property a filter sets to whether
+// its predicate matched.
+const FilterMatchedProperty = "CamelGoFilterMatched"
+
+// FilterProcessor only lets exchanges matching Predicate into Pipeline.
+// Other exchanges are counted in Stats and sent to Discard, if set.
+type FilterProcessor struct {
+	Predicate core.Predicate
+	Pipeline  core.Processor
+	Discard   core.Processor
+	Stats     *core.RouteStats
+}
+
+func (f *FilterProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	match, err := f.Predicate.Evaluate(ctx, exchange)
+	if err != nil {
+		return err
+	}
+	exchange.SetProperty(FilterMatchedProperty, match)
+	if !match {
+		f.Stats.ExchangeFiltered()
+		if f.Discard != nil {
+			return f.Discard.Process(ctx, exchange)
+		}
+		return nil
+	}
+	return f.Pipeline.Process(ctx, exchange)
+}
@@ -0,0 +1,112 @@
+package processors
+
+import "github.com/sonyjop/camelgo/core"
+
+// InterceptProcessor runs Interceptor before Target whenever When matches
+// (always, when When is nil). If the interceptor fails the target is skipped.
+type InterceptProcessor struct {
+	When        core.Predicate
+	Interceptor core.Processor
+	Target      core.Processor
+}
+
+func (p *InterceptProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	matched, err := matches(ctx, p.When, exchange)
+	if err != nil {
+		return err
+	}
+	if matched {
+		if err := p.Interceptor.Process(ctx, exchange); err != nil {
+			return err
+		}
+		if exchange.Error() != nil {
+			return exchange.Error()
+		}
+	}
+	return p.Target.Process(ctx, exchange)
+}
+
+// StepInterceptStrategy intercepts every processor step of every route.
+type StepInterceptStrategy struct {
+	When        core.Predicate
+	Interceptor core.Processor
+}
+
+func (s *StepInterceptStrategy) WrapProcessor(ctx core.Context, info core.StepInfo, target core.Processor) (core.Processor, error) {
+	if info.Kind == core.RouteStepKind {
+		return target, nil
+	}
+	return &InterceptProcessor{When: s.When, Interceptor: s.Interceptor, Target: target}, nil
+}
+
+// FromInterceptStrategy intercepts exchanges entering routes whose input URI
+// matches Pattern (see core.MatchPattern). An empty pattern matches every route.
+type FromInterceptStrategy struct {
+	Pattern     string
+	When        core.Predicate
+	Interceptor core.Processor
+}
+
+func (s *FromInterceptStrategy) WrapProcessor(ctx core.Context, info core.StepInfo, target core.Processor) (core.Processor, error) {
+	if info.Kind != core.RouteStepKind {
+		return target, nil
+	}
+	if s.Pattern != "" && !core.MatchPattern(info.URI, s.Pattern) {
+		return target, nil
+	}
+	return &InterceptProcessor{When: s.When, Interceptor: s.Interceptor, Target: target}, nil
+}
+
+// SendToEndpointInterceptor wraps producers of endpoints whose URI matches
+// Pattern so Interceptor runs before each send. With SkipOriginal the
+// original endpoint is not called when When matches.
+type SendToEndpointInterceptor struct {
+	Pattern      string
+	When         core.Predicate
+	Interceptor  core.Processor
+	SkipOriginal bool
+}
+
+func (s *SendToEndpointInterceptor) WrapProducer(ctx core.Context, endpoint core.Endpoint, producer core.Producer) (core.Producer, error) {
+	if !core.MatchPattern(endpoint.GetURI(), s.Pattern) {
+		return producer, nil
+	}
+	return &InterceptSendProducer{Producer: producer, EndpointURI: endpoint.GetURI(), Interceptor: s}, nil
+}
+
+// InterceptSendProducer is the producer decorator installed by
+// SendToEndpointInterceptor. Lifecycle calls go to the wrapped producer.
+type InterceptSendProducer struct {
+	core.Producer
+	EndpointURI string
+	Interceptor *SendToEndpointInterceptor
+}
+
+func (p *InterceptSendProducer) Process(ctx core.Context, exchange *core.Exchange) error {
+	matched, err := matches(ctx, p.Interceptor.When, exchange)
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return p.Producer.Process(ctx, exchange)
+	}
+
+	if err := p.Interceptor.Interceptor.Process(ctx, exchange); err != nil {
+		return err
+	}
+	if exchange.Error() != nil {
+		return exchange.Error()
+	}
+	if p.Interceptor.SkipOriginal {
+		return nil
+	}
+	return p.Producer.Process(ctx, exchange)
+}
+
+// matches evaluates an optional predicate; a nil predicate always matches.
+func matches(ctx core.Context, pred core.Predicate, exchange *core.Exchange) (bool, error) {
+	if pred == nil {
+		return true, nil
+	}
+	return pred.Evaluate(ctx, exchange)
+}
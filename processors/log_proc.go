@@ -0,0 +1,34 @@
+package processors
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// DefaultLogCategory is the category of log EIP messages without a logger
+// name.
+const DefaultLogCategory = "processor.log"
+
+// LogProcessor writes a message evaluated from an expression to the context
+// logger, with the exchange's MDC fields attached.
+type LogProcessor struct {
+	Message  core.Expression
+	Level    logrus.Level
+	Category string
+}
+
+func (p *LogProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	msg, err := p.Message.Evaluate(ctx, exchange)
+	if err != nil {
+		return fmt.Errorf("log: %w", err)
+	}
+	category := p.Category
+	if category == "" {
+		category = DefaultLogCategory
+	}
+	core.LogExchange(ctx, category, exchange).Log(p.Level, msg)
+	return nil
+}
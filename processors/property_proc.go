@@ -0,0 +1,32 @@
+package processors
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// SetPropertyProcessor sets an exchange property from an expression.
+type SetPropertyProcessor struct {
+	Name       string
+	Expression core.Expression
+}
+
+func (p *SetPropertyProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	v, err := p.Expression.Evaluate(ctx, exchange)
+	if err != nil {
+		return fmt.Errorf("setProperty(%s): %w", p.Name, err)
+	}
+	exchange.SetProperty(p.Name, v)
+	return nil
+}
+
+// RemovePropertyProcessor removes an exchange property.
+type RemovePropertyProcessor struct {
+	Name string
+}
+
+func (p *RemovePropertyProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	exchange.RemoveProperty(p.Name)
+	return nil
+}
@@ -0,0 +1,93 @@
+package processors
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// RecipientListProcessor sends a copy of the exchange to each endpoint URI
+// that Expression lists and aggregates the copies into the exchange with
+// Strategy, in the order of the list. A recipient fails when sending returns
+// an error or leaves one on its copy. Sequentially, the first failure stops
+// the list; in parallel, all recipients run and the failures are joined.
+type RecipientListProcessor struct {
+	Expression core.Expression
+	Delimiter  string
+	Parallel   bool
+	Strategy   core.AggregationStrategy
+	Cache      *core.ProducerCache
+}
+
+func (p *RecipientListProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	v, err := p.Expression.Evaluate(ctx, exchange)
+	if err != nil {
+		return fmt.Errorf("recipientList: %w", err)
+	}
+	uris, err := endpointURIs(ctx, v, p.Delimiter)
+	if err != nil {
+		return fmt.Errorf("recipientList: %w", err)
+	}
+	if len(uris) == 0 {
+		return nil
+	}
+
+	copies := make([]*core.Exchange, len(uris))
+	for i := range uris {
+		c := exchange.Clone()
+		copies[i] = &c
+	}
+	if p.Parallel {
+		err = p.sendParallel(ctx, uris, copies)
+	} else {
+		err = p.sendSequential(ctx, uris, copies)
+	}
+	if err != nil {
+		return err
+	}
+
+	var result *core.Exchange
+	for _, c := range copies {
+		if result, err = p.Strategy.Aggregate(result, c); err != nil {
+			return fmt.Errorf("recipientList: aggregation: %w", err)
+		}
+	}
+	exchange.CopyResults(result)
+	return nil
+}
+
+func (p *RecipientListProcessor) sendSequential(ctx core.Context, uris []string, copies []*core.Exchange) error {
+	for i, uri := range uris {
+		if err := p.send(ctx, uri, copies[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *RecipientListProcessor) sendParallel(ctx core.Context, uris []string, copies []*core.Exchange) error {
+	errs := make([]error, len(uris))
+	var wg sync.WaitGroup
+	for i, uri := range uris {
+		wg.Add(1)
+		go func(i int, uri string) {
+			defer wg.Done()
+			errs[i] = p.send(ctx, uri, copies[i])
+		}(i, uri)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (p *RecipientListProcessor) send(ctx core.Context, uri string, copy *core.Exchange) error {
+	err := sendTo(ctx, p.Cache, uri, copy)
+	if err == nil {
+		err = copy.Error()
+	}
+	if err != nil {
+		return fmt.Errorf("recipientList(%s): %w", core.MaskURI(ctx, uri), err)
+	}
+	return nil
+}
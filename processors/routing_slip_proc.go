@@ -0,0 +1,36 @@
+package processors
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// RoutingSlipProcessor sends the exchange to the endpoint URIs that
+// Expression lists, one after the other, each receiving the result of the
+// one before. The slip is evaluated once, before the first endpoint.
+type RoutingSlipProcessor struct {
+	Expression core.Expression
+	Delimiter  string
+	Cache      *core.ProducerCache
+}
+
+func (p *RoutingSlipProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	v, err := p.Expression.Evaluate(ctx, exchange)
+	if err != nil {
+		return fmt.Errorf("routingSlip: %w", err)
+	}
+	uris, err := endpointURIs(ctx, v, p.Delimiter)
+	if err != nil {
+		return fmt.Errorf("routingSlip: %w", err)
+	}
+	for _, uri := range uris {
+		if err := sendTo(ctx, p.Cache, uri, exchange); err != nil {
+			return fmt.Errorf("routingSlip(%s): %w", core.MaskURI(ctx, uri), err)
+		}
+		if err := exchange.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
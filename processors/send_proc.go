@@ -0,0 +1,28 @@
+package processors
+
+import (
+	"time"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// SendProcessor sends the exchange to an endpoint through its producer and
+// reports ExchangeSending/ExchangeSent events around the call.
+type SendProcessor struct {
+	URI      string
+	Producer core.Producer
+}
+
+func (s *SendProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	if core.EventEnabled(ctx, core.ExchangeSendingEvent) {
+		ctx.NotifyEvent(core.NewExchangeSendEvent(core.ExchangeSendingEvent, exchange, s.URI, 0, nil))
+	}
+	start := time.Now()
+
+	err := s.Producer.Process(ctx, exchange)
+
+	if core.EventEnabled(ctx, core.ExchangeSentEvent) {
+		ctx.NotifyEvent(core.NewExchangeSendEvent(core.ExchangeSentEvent, exchange, s.URI, time.Since(start), err))
+	}
+	return err
+}
@@ -0,0 +1,22 @@
+package processors
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// SetBodyProcessor replaces the body of the in message with the value of an
+// expression.
+type SetBodyProcessor struct {
+	Expression core.Expression
+}
+
+func (p *SetBodyProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	v, err := p.Expression.Evaluate(ctx, exchange)
+	if err != nil {
+		return fmt.Errorf("setBody: %w", err)
+	}
+	exchange.In().SetBody(v)
+	return nil
+}
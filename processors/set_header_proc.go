@@ -0,0 +1,71 @@
+package processors
+
+import (
+	"fmt"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// SetHeaderProcessor sets a header on the in message from an expression.
+type SetHeaderProcessor struct {
+	Name       string
+	Expression core.Expression
+}
+
+func (p *SetHeaderProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	v, err := p.Expression.Evaluate(ctx, exchange)
+	if err != nil {
+		return fmt.Errorf("setHeader(%s): %w", p.Name, err)
+	}
+	exchange.In().SetHeader(p.Name, v)
+	return nil
+}
+
+// SetHeadersProcessor sets several headers. All expressions are evaluated
+// before any header is set, so they see the message as it was.
+type SetHeadersProcessor struct {
+	Headers map[string]core.Expression
+}
+
+func (p *SetHeadersProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	values := make(map[string]interface{}, len(p.Headers))
+	for name, expr := range p.Headers {
+		v, err := expr.Evaluate(ctx, exchange)
+		if err != nil {
+			return fmt.Errorf("setHeaders(%s): %w", name, err)
+		}
+		values[name] = v
+	}
+	for name, v := range values {
+		exchange.In().SetHeader(name, v)
+	}
+	return nil
+}
+
+// RemoveHeaderProcessor removes a header from the in message.
+type RemoveHeaderProcessor struct {
+	Name string
+}
+
+func (p *RemoveHeaderProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	exchange.In().RemoveHeader(p.Name)
+	return nil
+}
+
+// RemoveHeadersProcessor removes the headers of the in message matching
+// Pattern, except those matching one of Exclude. Patterns are matched with
+// core.MatchPattern.
+type RemoveHeadersProcessor struct {
+	Pattern string
+	Exclude []string
+}
+
+func (p *RemoveHeadersProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	msg := exchange.In()
+	for name := range msg.Headers() {
+		if core.MatchPattern(name, p.Pattern) && !core.MatchAnyPattern(name, p.Exclude) {
+			msg.RemoveHeader(name)
+		}
+	}
+	return nil
+}
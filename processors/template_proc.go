@@ -0,0 +1,39 @@
+package processors
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// TemplateData is what transform templates are executed with, e.g.
+// {{.Body}} or {{.Headers.customer}}.
+type TemplateData struct {
+	Body       interface{}
+	Headers    map[string]interface{}
+	Properties map[string]interface{}
+	ExchangeID string
+}
+
+// TemplateProcessor replaces the body of the in message with the output of
+// a text/template.
+type TemplateProcessor struct {
+	Template *template.Template
+}
+
+func (p *TemplateProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	data := TemplateData{
+		Body:       exchange.In().Body(),
+		Headers:    exchange.In().Headers(),
+		Properties: exchange.Properties(),
+		ExchangeID: exchange.ID(),
+	}
+	var sb strings.Builder
+	if err := p.Template.Execute(&sb, data); err != nil {
+		return fmt.Errorf("transformTemplate: %w", err)
+	}
+	exchange.In().SetBody(sb.String())
+	return nil
+}
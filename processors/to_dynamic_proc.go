@@ -0,0 +1,80 @@
+package processors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// DefaultDelimiter separates the endpoint URIs of recipient lists and
+// routing slips given as a single string.
+const DefaultDelimiter = ","
+
+// DynamicSendProcessor sends the exchange to the endpoint URI that
+// Expression computes for it (toD).
+type DynamicSendProcessor struct {
+	Expression core.Expression
+	Cache      *core.ProducerCache
+}
+
+func (p *DynamicSendProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	v, err := p.Expression.Evaluate(ctx, exchange)
+	if err != nil {
+		return fmt.Errorf("toD: %w", err)
+	}
+	uri, err := core.ConvertTo[string](ctx, v)
+	if err != nil {
+		return fmt.Errorf("toD: endpoint URI: %w", err)
+	}
+	if uri = strings.TrimSpace(uri); uri == "" {
+		return fmt.Errorf("toD: the endpoint URI is empty")
+	}
+	return sendTo(ctx, p.Cache, uri, exchange)
+}
+
+// sendTo sends exchange to uri through a producer from cache.
+func sendTo(ctx core.Context, cache *core.ProducerCache, uri string, exchange *core.Exchange) error {
+	prod, release, err := cache.Acquire(uri)
+	if err != nil {
+		return err
+	}
+	defer release()
+	send := &SendProcessor{URI: core.MaskURI(ctx, uri), Producer: prod}
+	return send.Process(ctx, exchange)
+}
+
+// endpointURIs returns the URIs v lists: a string separated by delimiter,
+// or a slice of strings. Blank entries are skipped.
+func endpointURIs(ctx core.Context, v interface{}, delimiter string) ([]string, error) {
+	if delimiter == "" {
+		delimiter = DefaultDelimiter
+	}
+	var items []string
+	switch v := v.(type) {
+	case nil:
+	case []string:
+		items = v
+	case []interface{}:
+		for _, item := range v {
+			s, err := core.ConvertTo[string](ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, s)
+		}
+	default:
+		s, err := core.ConvertTo[string](ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		items = strings.Split(s, delimiter)
+	}
+	uris := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			uris = append(uris, item)
+		}
+	}
+	return uris, nil
+}
@@ -0,0 +1,245 @@
+// Package properties resolves {{key}} property placeholders in endpoint
+// URIs, route IDs and expressions.
+//
+// Properties are read from config files in any format viper understands,
+// overridden by environment variables (kafka.brokers is also looked up as
+// KAFKA_BROKERS) and then by values set in code. With a profile such as
+// "dev", each file app.yaml is followed by app-dev.yaml when it exists.
+//
+// Placeholders have the forms
+//
+//	{{key}}                   a property; it is an error if it is not set
+//	{{key:default}}           a property, or default when it is not set
+//	{{env:NAME}}              an environment variable, also with :default
+//	{{file:/run/secrets/pw}}  the content of a file without trailing newlines
+//
+// Property values may contain placeholders themselves. Keys are case
+// insensitive.
+package properties
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// maxDepth limits how deeply property values may refer to other properties.
+const maxDepth = 32
+
+// Function resolves a {{name:arg}} placeholder. It returns ok false when
+// there is no value, so the placeholder's default applies.
+type Function func(arg string) (value string, ok bool, err error)
+
+// PropertiesComponent holds the properties of a context and resolves
+// placeholders against them. It is safe for concurrent use.
+type PropertiesComponent struct {
+	mu        sync.RWMutex
+	v         *viper.Viper
+	locations []string
+	profile   string
+	overrides map[string]interface{}
+	functions map[string]Function
+}
+
+// NewPropertiesComponent returns a component reading only environment
+// variables, with the env and file functions.
+func NewPropertiesComponent() *PropertiesComponent {
+	p := &PropertiesComponent{
+		overrides: make(map[string]interface{}),
+		functions: map[string]Function{"env": envFunction, "file": fileFunction},
+	}
+	p.v, _ = p.load()
+	return p
+}
+
+// Install makes p resolve the placeholders of ctx. Call it before AddRoutes.
+func (p *PropertiesComponent) Install(ctx *core.DefaultContext) {
+	ctx.SetPropertiesResolver(p)
+}
+
+// AddLocation adds a config file. Files added later take precedence.
+func (p *PropertiesComponent) AddLocation(path string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	locations := append(append([]string(nil), p.locations...), path)
+	return p.reload(locations, p.profile)
+}
+
+// SetProfile selects the profile whose files are merged over each location.
+func (p *PropertiesComponent) SetProfile(profile string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.reload(p.locations, profile)
+}
+
+// Profile returns the selected profile.
+func (p *PropertiesComponent) Profile() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.profile
+}
+
+// SetOverride sets a property that takes precedence over files and the
+// environment.
+func (p *PropertiesComponent) SetOverride(key string, value interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.overrides[key] = value
+	p.v.Set(key, value)
+}
+
+// AddFunction registers fn for {{name:arg}} placeholders.
+func (p *PropertiesComponent) AddFunction(name string, fn Function) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.functions[name] = fn
+}
+
+// Property returns the raw value of key, without resolving placeholders in
+// it.
+func (p *PropertiesComponent) Property(key string) (string, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if !p.v.IsSet(key) {
+		return "", false
+	}
+	return p.v.GetString(key), true
+}
+
+// ResolvePlaceholders replaces the placeholders in text.
+func (p *PropertiesComponent) ResolvePlaceholders(text string) (string, error) {
+	return p.resolve(text, nil)
+}
+
+func (p *PropertiesComponent) resolve(text string, stack []string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	var sb strings.Builder
+	rest := text
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			return "", fmt.Errorf("unclosed placeholder in %q", text)
+		}
+		sb.WriteString(rest[:start])
+		value, err := p.placeholder(strings.TrimSpace(rest[start+2:start+end]), stack)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(value)
+		rest = rest[start+end+2:]
+	}
+	sb.WriteString(rest)
+	return sb.String(), nil
+}
+
+// placeholder resolves the content of one placeholder.
+func (p *PropertiesComponent) placeholder(content string, stack []string) (string, error) {
+	name, arg, hasArg := strings.Cut(content, ":")
+	p.mu.RLock()
+	fn, isFunction := p.functions[name]
+	p.mu.RUnlock()
+
+	if isFunction && hasArg {
+		fnArg, def, hasDefault := strings.Cut(arg, ":")
+		value, ok, err := fn(fnArg)
+		if err != nil {
+			return "", fmt.Errorf("{{%s}}: %w", content, err)
+		}
+		if !ok {
+			if !hasDefault {
+				return "", fmt.Errorf("{{%s}}: no value", content)
+			}
+			value = def
+		}
+		return value, nil
+	}
+
+	key, def := name, arg
+	for _, k := range stack {
+		if strings.EqualFold(k, key) {
+			return "", fmt.Errorf("property %q refers to itself: %s -> %s", key, strings.Join(stack, " -> "), key)
+		}
+	}
+	if len(stack) >= maxDepth {
+		return "", fmt.Errorf("property %q: placeholders nested too deeply", key)
+	}
+	value, ok := p.Property(key)
+	if !ok {
+		if !hasArg {
+			return "", fmt.Errorf("property %q not found", key)
+		}
+		value = def
+	}
+	return p.resolve(value, append(stack, key))
+}
+
+// reload reads locations with profile into a new viper instance and swaps it
+// in on success. p.mu must be held.
+func (p *PropertiesComponent) reload(locations []string, profile string) error {
+	old := p.locations
+	oldProfile := p.profile
+	p.locations, p.profile = locations, profile
+	v, err := p.load()
+	if err != nil {
+		p.locations, p.profile = old, oldProfile
+		return err
+	}
+	p.v = v
+	return nil
+}
+
+func (p *PropertiesComponent) load() (*viper.Viper, error) {
+	v := viper.New()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	v.AutomaticEnv()
+	for _, loc := range p.locations {
+		files := []string{loc}
+		if p.profile != "" {
+			ext := filepath.Ext(loc)
+			profiled := strings.TrimSuffix(loc, ext) + "-" + p.profile + ext
+			if _, err := os.Stat(profiled); err == nil {
+				files = append(files, profiled)
+			}
+		}
+		for _, f := range files {
+			v.SetConfigFile(f)
+			v.SetConfigType(strings.TrimPrefix(filepath.Ext(f), "."))
+			if err := v.MergeInConfig(); err != nil {
+				return nil, fmt.Errorf("reading properties %s: %w", f, err)
+			}
+		}
+	}
+	for key, value := range p.overrides {
+		v.Set(key, value)
+	}
+	return v, nil
+}
+
+func envFunction(name string) (string, bool, error) {
+	value, ok := os.LookupEnv(name)
+	return value, ok, nil
+}
+
+func fileFunction(path string) (string, bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
@@ -0,0 +1,132 @@
+package properties
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sonyjop/camelgo/component/direct"
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/yamldsl"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPropertiesComponent_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	app := filepath.Join(dir, "app.yaml")
+	writeFile(t, app, "queue:\n  name: orders\n  size: 10\nurl: \"seda:{{queue.name}}\"\nenv: base\n")
+	writeFile(t, filepath.Join(dir, "app-dev.yaml"), "env: dev\n")
+	secret := filepath.Join(dir, "token")
+	writeFile(t, secret, "s3cret\n")
+	t.Setenv("QUEUE_SIZE", "20")
+	t.Setenv("CAMELGO_TEST_HOME", "/home/camel")
+
+	p := NewPropertiesComponent()
+	if err := p.AddLocation(app); err != nil {
+		t.Fatal(err)
+	}
+	for text, want := range map[string]string{
+		"seda:{{queue.name}}?size={{queue.size}}": "seda:orders?size=20",
+		"{{url}}":                     "seda:orders",
+		"{{missing:fallback}}":        "fallback",
+		"{{missing:}}x":               "x",
+		"{{ env }}":                   "base",
+		"{{env:CAMELGO_TEST_HOME}}":   "/home/camel",
+		"{{env:CAMELGO_TEST_NONE:/}}": "/",
+		"{{file:" + secret + "}}":     "s3cret",
+		"no placeholders":             "no placeholders",
+	} {
+		got, err := p.ResolvePlaceholders(text)
+		if err != nil || got != want {
+			t.Errorf("%s: expected %q, got %q (%v)", text, want, got, err)
+		}
+	}
+
+	if err := p.SetProfile("dev"); err != nil {
+		t.Fatal(err)
+	}
+	p.SetOverride("queue.name", "override")
+	if got, _ := p.ResolvePlaceholders("{{env}}/{{queue.name}}"); got != "dev/override" {
+		t.Errorf("expected the profile and override to win, got %q", got)
+	}
+
+	for _, text := range []string{"{{missing}}", "{{env:CAMELGO_TEST_NONE}}", "{{unclosed", "{{file:" + filepath.Join(dir, "none") + "}}"} {
+		if _, err := p.ResolvePlaceholders(text); err == nil {
+			t.Errorf("expected %q to fail", text)
+		}
+	}
+	p.SetOverride("a", "{{b}}")
+	p.SetOverride("b", "{{a}}")
+	if _, err := p.ResolvePlaceholders("{{a}}"); err == nil || !strings.Contains(err.Error(), "refers to itself") {
+		t.Errorf("expected a cycle error, got %v", err)
+	}
+	if err := p.AddLocation(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected a missing location to fail")
+	}
+	if got, _ := p.ResolvePlaceholders("{{queue.size}}"); got != "20" {
+		t.Errorf("expected a failed AddLocation to keep the properties, got %q", got)
+	}
+}
+
+func TestPropertiesComponent_Context(t *testing.T) {
+	p := NewPropertiesComponent()
+	p.SetOverride("route.input", "direct:in")
+	p.SetOverride("greeting", "hello")
+	p.SetOverride("route.id", "greeter")
+
+	ctx := core.NewContext()
+	ctx.RegisterComponent("direct", direct.NewDirectComponent())
+	ctx.SetLoader(yamldsl.NewLoader())
+	p.Install(ctx)
+
+	routes := `
+- route:
+    id: "{{route.id}}"
+    from:
+      uri: "{{route.input}}"
+      steps:
+        - setHeader:
+            name: greeting
+            simple: "{{greeting}} ${body}"
+`
+	if err := ctx.AddRoutes([]byte(routes)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Stop()
+
+	route := ctx.Route("greeter")
+	if route == nil || route.InputURI != "direct:in" {
+		t.Fatalf("expected the route ID and input to be resolved, got %+v", route)
+	}
+	ep, err := ctx.GetEndpoint("{{route.input}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same, _ := ctx.GetEndpoint("direct:in"); same != ep {
+		t.Error("expected endpoints to be cached by the resolved URI")
+	}
+
+	prod, _ := ep.CreateProducer()
+	ex := ctx.NewExchange()
+	ex.In().SetBody("world")
+	if err := prod.Process(ctx, ex); err != nil {
+		t.Fatal(err)
+	}
+	if got := ex.In().Header("greeting"); got != "hello world" {
+		t.Errorf("expected the expression placeholder to be resolved, got %v", got)
+	}
+
+	if _, err := ctx.GetEndpoint("direct:{{nope}}"); err == nil {
+		t.Error("expected an unresolvable URI to fail")
+	}
+}
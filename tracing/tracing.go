@@ -0,0 +1,181 @@
+// Package tracing adds opt-in OpenTelemetry tracing to a camelgo context.
+//
+// Every exchange gets a span per route it passes through, with a child span
+// per step and per producer send. The W3C trace context is written to the
+// message headers on every send, so traces continue across direct:, seda:
+// and any other hop that keeps headers.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// InstrumentationName identifies the tracer obtained from the provider.
+const InstrumentationName = "github.com/sonyjop/camelgo/tracing"
+
+// contextProperty holds the context.Context of the innermost active span on
+// the exchange.
+const contextProperty = "CamelGoTraceContext"
+
+// Span attribute keys.
+const (
+	RouteIDKey     = attribute.Key("camelgo.route.id")
+	StepIDKey      = attribute.Key("camelgo.step.id")
+	ExchangeIDKey  = attribute.Key("camelgo.exchange.id")
+	EndpointURIKey = attribute.Key("camelgo.endpoint.uri")
+)
+
+// Tracer creates spans for routes, steps and producer sends. It is both an
+// InterceptStrategy and a ProducerInterceptor.
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewTracer returns a Tracer using tp and W3C trace-context propagation.
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	return &Tracer{
+		tracer:     tp.Tracer(InstrumentationName),
+		propagator: propagation.TraceContext{},
+	}
+}
+
+// Install registers the tracer on ctx. Only routes added and producers
+// created afterwards are traced, so call it before AddRoutes.
+func (t *Tracer) Install(ctx core.Context) {
+	ctx.AddInterceptStrategy(t)
+	ctx.AddProducerInterceptor(t)
+}
+
+func (t *Tracer) WrapProcessor(ctx core.Context, info core.StepInfo, target core.Processor) (core.Processor, error) {
+	if info.Kind == core.RouteStepKind {
+		return &routeSpanProcessor{tracer: t, routeID: info.RouteID, uri: core.MaskURI(ctx, info.URI), target: target}, nil
+	}
+	return &stepSpanProcessor{tracer: t, routeID: info.RouteID, stepID: info.StepID, target: target}, nil
+}
+
+func (t *Tracer) WrapProducer(ctx core.Context, endpoint core.Endpoint, producer core.Producer) (core.Producer, error) {
+	return &sendSpanProducer{Producer: producer, tracer: t, uri: core.MaskURI(ctx, endpoint.GetURI())}, nil
+}
+
+// activeSpan is stored on the exchange while a span is open.
+type activeSpan struct {
+	ctx     context.Context
+	routeID string
+}
+
+// ContextFromExchange returns a context.Context carrying the innermost span
+// active on exchange, so processors can start their own child spans.
+func ContextFromExchange(exchange *core.Exchange) context.Context {
+	if a, ok := exchange.GetProperty(contextProperty).(*activeSpan); ok {
+		return a.ctx
+	}
+	return context.Background()
+}
+
+func currentRouteID(exchange *core.Exchange) string {
+	if a, ok := exchange.GetProperty(contextProperty).(*activeSpan); ok {
+		return a.routeID
+	}
+	return ""
+}
+
+// start opens a span as a child of parent and makes it the exchange's
+// active span. The returned function ends the span and restores parent.
+func (t *Tracer) start(parent context.Context, exchange *core.Exchange, routeID, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, func(err error)) {
+	attrs = append(attrs, ExchangeIDKey.String(exchange.ID()))
+	if routeID != "" {
+		attrs = append(attrs, RouteIDKey.String(routeID))
+	}
+	spanCtx, span := t.tracer.Start(parent, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+
+	previous := exchange.GetProperty(contextProperty)
+	exchange.SetProperty(contextProperty, &activeSpan{ctx: spanCtx, routeID: routeID})
+
+	return spanCtx, func(err error) {
+		if err == nil {
+			err = exchange.Error()
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		exchange.SetProperty(contextProperty, previous)
+	}
+}
+
+// routeSpanProcessor opens the span for an exchange entering a route. Its
+// parent is the trace context found in the headers, falling back to the
+// span active on the exchange.
+type routeSpanProcessor struct {
+	tracer  *Tracer
+	routeID string
+	uri     string
+	target  core.Processor
+}
+
+func (p *routeSpanProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	parent := p.tracer.propagator.Extract(ContextFromExchange(exchange), headerCarrier(exchange.In().Headers()))
+	_, end := p.tracer.start(parent, exchange, p.routeID, p.routeID, trace.SpanKindConsumer, EndpointURIKey.String(p.uri))
+	err := p.target.Process(ctx, exchange)
+	end(err)
+	return err
+}
+
+type stepSpanProcessor struct {
+	tracer  *Tracer
+	routeID string
+	stepID  string
+	target  core.Processor
+}
+
+func (p *stepSpanProcessor) Process(ctx core.Context, exchange *core.Exchange) error {
+	_, end := p.tracer.start(ContextFromExchange(exchange), exchange, p.routeID, p.stepID, trace.SpanKindInternal, StepIDKey.String(p.stepID))
+	err := p.target.Process(ctx, exchange)
+	end(err)
+	return err
+}
+
+// sendSpanProducer opens a span per send and injects its trace context into
+// the message headers for the receiving side.
+type sendSpanProducer struct {
+	core.Producer
+	tracer *Tracer
+	uri    string
+}
+
+func (p *sendSpanProducer) Process(ctx core.Context, exchange *core.Exchange) error {
+	spanCtx, end := p.tracer.start(ContextFromExchange(exchange), exchange, currentRouteID(exchange), p.uri, trace.SpanKindProducer, EndpointURIKey.String(p.uri))
+	p.tracer.propagator.Inject(spanCtx, headerCarrier(exchange.In().Headers()))
+	err := p.Producer.Process(ctx, exchange)
+	end(err)
+	return err
+}
+
+// headerCarrier adapts message headers to propagation.TextMapCarrier.
+type headerCarrier map[string]interface{}
+
+func (h headerCarrier) Get(key string) string {
+	s, _ := h[key].(string)
+	return s
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h[key] = value
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
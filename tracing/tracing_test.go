@@ -0,0 +1,132 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/dsl"
+	"github.com/sonyjop/camelgo/internal/routetest"
+)
+
+func newTracedContext(t *testing.T, fn func(b *dsl.BaseRouteBuilder)) (*core.DefaultContext, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	ctx := routetest.NewContext()
+	NewTracer(tp).Install(ctx)
+	routetest.Start(t, ctx, fn)
+	return ctx, exporter
+}
+
+func spanByName(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, s := range spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	t.Fatalf("no span named %q in %d spans", name, len(spans))
+	return tracetest.SpanStub{}
+}
+
+func attr(s tracetest.SpanStub, key string) string {
+	for _, kv := range s.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestTracer_SpansAcrossDirectAndSeda(t *testing.T) {
+	done := make(chan struct{})
+	ctx, exporter := newTracedContext(t, func(b *dsl.BaseRouteBuilder) {
+		b.From("direct:start").RouteID("start").To("direct:enrich").To("seda:async")
+		b.From("direct:enrich").RouteID("enrich").Process(core.ProcessorFunc(func(ctx core.Context, ex *core.Exchange) error {
+			return nil
+		}))
+		b.From("seda:async").RouteID("async").Process(core.ProcessorFunc(func(ctx core.Context, ex *core.Exchange) error {
+			close(done)
+			return nil
+		}))
+	})
+
+	producer, err := ctx.CreateProducer("direct:start")
+	if err != nil {
+		t.Fatalf("CreateProducer error: %v", err)
+	}
+	ex := ctx.NewExchange()
+	if err := producer.Process(ctx, ex); err != nil {
+		t.Fatalf("process error: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for seda route")
+	}
+	ctx.Stop()
+
+	spans := exporter.GetSpans()
+	root := spanByName(t, spans, "direct:start")
+	start := spanByName(t, spans, "start")
+	enrich := spanByName(t, spans, "enrich")
+	async := spanByName(t, spans, "async")
+	sendSeda := spanByName(t, spans, "seda:async")
+
+	for _, s := range spans {
+		if s.SpanContext.TraceID() != root.SpanContext.TraceID() {
+			t.Errorf("span %q is not part of the trace", s.Name)
+		}
+	}
+	if start.Parent.SpanID() != root.SpanContext.SpanID() {
+		t.Errorf("expected route span to be a child of the send span")
+	}
+	if enrich.Parent.SpanID() != spanByName(t, spans, "direct:enrich").SpanContext.SpanID() {
+		t.Errorf("expected direct: route span to be a child of its send span")
+	}
+	if async.Parent.SpanID() != sendSeda.SpanContext.SpanID() {
+		t.Errorf("expected seda: route span to be a child of its send span")
+	}
+	step := spanByName(t, spans, "to1")
+	if step.Parent.SpanID() != start.SpanContext.SpanID() {
+		t.Errorf("expected step span to be a child of the route span")
+	}
+	if attr(step, "camelgo.step.id") != "to1" || attr(step, "camelgo.route.id") != "start" {
+		t.Errorf("unexpected step attributes %v", step.Attributes)
+	}
+	if attr(sendSeda, "camelgo.endpoint.uri") != "seda:async" || attr(sendSeda, "camelgo.exchange.id") != ex.ID() {
+		t.Errorf("unexpected send attributes %v", sendSeda.Attributes)
+	}
+	if ex.In().Header("traceparent") == nil {
+		t.Errorf("expected trace context in the message headers")
+	}
+}
+
+func TestTracer_RecordsErrors(t *testing.T) {
+	ctx, exporter := newTracedContext(t, func(b *dsl.BaseRouteBuilder) {
+		b.From("direct:start").RouteID("failing").Process(core.ProcessorFunc(func(ctx core.Context, ex *core.Exchange) error {
+			return errors.New("boom")
+		})).ID("explode")
+	})
+
+	producer, _ := ctx.CreateProducer("direct:start")
+	if err := producer.Process(ctx, ctx.NewExchange()); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	spans := exporter.GetSpans()
+	for _, name := range []string{"explode", "failing", "direct:start"} {
+		if s := spanByName(t, spans, name); s.Status.Code != codes.Error {
+			t.Errorf("expected span %q to have error status, got %v", name, s.Status)
+		}
+	}
+}
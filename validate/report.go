@@ -0,0 +1,143 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Output formats understood by Write.
+const (
+	FormatText  = "text"
+	FormatJSON  = "json"
+	FormatSARIF = "sarif"
+)
+
+// Write renders issues in format.
+func Write(w io.Writer, format string, issues []Issue) error {
+	switch format {
+	case FormatText, "":
+		return writeText(w, issues)
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if issues == nil {
+			issues = []Issue{}
+		}
+		return enc.Encode(issues)
+	case FormatSARIF:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(toSARIF(issues))
+	default:
+		return fmt.Errorf("unknown format %q, expected %s, %s or %s", format, FormatText, FormatJSON, FormatSARIF)
+	}
+}
+
+func writeText(w io.Writer, issues []Issue) error {
+	var errs, warnings int
+	for _, i := range issues {
+		if _, err := fmt.Fprintln(w, i); err != nil {
+			return err
+		}
+		if i.Severity == SeverityError {
+			errs++
+		} else {
+			warnings++
+		}
+	}
+	if len(issues) == 0 {
+		_, err := fmt.Fprintln(w, "no problems found")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d errors, %d warnings\n", errs, warnings)
+	return err
+}
+
+// The subset of SARIF 2.1.0 needed to report issues with locations.
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func toSARIF(issues []Issue) sarifLog {
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: "camelgo"}},
+		Results: []sarifResult{},
+	}
+	ids := make([]string, 0, len(RuleDescriptions))
+	for id := range RuleDescriptions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: RuleDescriptions[id]}})
+	}
+
+	for _, i := range issues {
+		res := sarifResult{RuleID: i.Rule, Level: string(i.Severity), Message: sarifMessage{Text: i.Message}}
+		if i.Location.File != "" {
+			loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: i.Location.File}}}
+			if i.Location.Line > 0 {
+				loc.PhysicalLocation.Region = &sarifRegion{StartLine: i.Location.Line, StartColumn: i.Location.Column}
+			}
+			res.Locations = []sarifLocation{loc}
+		}
+		run.Results = append(run.Results, res)
+	}
+	return sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	}
+}
@@ -0,0 +1,384 @@
+// Package validate checks route definitions without starting anything:
+// unknown components, invalid endpoints, unresolved or cyclic direct calls,
+// duplicate route IDs, unreachable branches and steps that fail to compile.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/definitions"
+	"github.com/sonyjop/camelgo/yamldsl"
+)
+
+// Severity says whether an issue makes the routes unusable.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rules reported by the validator.
+const (
+	RuleLoad              = "load"
+	RuleDuplicateRouteID  = "duplicate-route-id"
+	RuleUnknownComponent  = "unknown-component"
+	RuleInvalidEndpoint   = "invalid-endpoint"
+	RuleUnresolvedDirect  = "unresolved-direct"
+	RuleDirectCycle       = "direct-cycle"
+	RuleUnreachableBranch = "unreachable-branch"
+	RuleCompile           = "compile"
+)
+
+// RuleDescriptions describes each rule in a sentence.
+var RuleDescriptions = map[string]string{
+	RuleLoad:              "The route file could not be read or parsed.",
+	RuleDuplicateRouteID:  "Two routes have the same ID.",
+	RuleUnknownComponent:  "An endpoint URI uses a scheme no component is registered for.",
+	RuleInvalidEndpoint:   "The component rejected the endpoint URI or its options.",
+	RuleUnresolvedDirect:  "A direct endpoint is sent to, but no route consumes from it.",
+	RuleDirectCycle:       "Routes call each other through direct endpoints in a cycle.",
+	RuleUnreachableBranch: "A branch can never run because of a condition that is always true or false.",
+	RuleCompile:           "The route failed to compile.",
+}
+
+// Issue is one problem found in the routes.
+type Issue struct {
+	Rule     string              `json:"rule"`
+	Severity Severity            `json:"severity"`
+	Message  string              `json:"message"`
+	RouteID  string              `json:"routeId,omitempty"`
+	Location core.SourceLocation `json:"location"`
+}
+
+func (i Issue) String() string {
+	loc := i.Location.String()
+	if loc == "" {
+		loc = "<unknown>"
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", loc, i.Severity, i.Message, i.Rule)
+}
+
+// Validator collects routes from one or more sources and checks them
+// together, so direct endpoints can be resolved across files.
+type Validator struct {
+	newContext func() *core.DefaultContext
+	routes     []*core.RouteDefinition
+	issues     []Issue
+}
+
+// New returns a validator resolving endpoints and compiling routes against
+// contexts returned by newContext, which should have the same components
+// registered as the contexts the routes will run in.
+func New(newContext func() *core.DefaultContext) *Validator {
+	return &Validator{newContext: newContext}
+}
+
+// Load adds the routes loader reads from file. Load errors are recorded as
+// issues.
+func (v *Validator) Load(file string, loader core.RouteLoader) {
+	defs, err := loader.Load(file)
+	if err != nil {
+		issue := Issue{Rule: RuleLoad, Severity: SeverityError, Message: err.Error(), Location: core.SourceLocation{File: file}}
+		var posErr *yamldsl.Error
+		if errors.As(err, &posErr) {
+			issue.Message = posErr.Msg
+			issue.Location.Line, issue.Location.Column = posErr.Line, posErr.Column
+		}
+		v.issues = append(v.issues, issue)
+		return
+	}
+	v.AddRoutes(defs...)
+}
+
+// AddRoutes adds definitions that were loaded elsewhere.
+func (v *Validator) AddRoutes(defs ...*core.RouteDefinition) {
+	v.routes = append(v.routes, defs...)
+}
+
+// Validate runs all checks and returns the issues ordered by location.
+func (v *Validator) Validate() []Issue {
+	r := &run{Validator: v, issues: append([]Issue(nil), v.issues...), ctx: v.newContext(), failed: make(map[*core.RouteDefinition]bool)}
+	r.checkDuplicateIDs()
+	r.checkEndpoints()
+	r.checkDirect()
+	r.checkBranches()
+	r.checkCompile()
+
+	sort.SliceStable(r.issues, func(i, j int) bool {
+		a, b := r.issues[i].Location, r.issues[j].Location
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return r.issues
+}
+
+// HasErrors reports whether issues contains an error.
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// run holds the state of one Validate call.
+type run struct {
+	*Validator
+	issues []Issue
+	ctx    *core.DefaultContext
+
+	// failed marks routes with errors; they are not compiled, as that
+	// would only repeat the error.
+	failed map[*core.RouteDefinition]bool
+}
+
+func (r *run) report(rule string, sev Severity, route *core.RouteDefinition, loc core.SourceLocation, format string, args ...interface{}) {
+	if loc == (core.SourceLocation{}) {
+		loc = route.Location
+	}
+	if sev == SeverityError {
+		r.failed[route] = true
+	}
+	r.issues = append(r.issues, Issue{Rule: rule, Severity: sev, Message: fmt.Sprintf(format, args...), RouteID: route.ID, Location: loc})
+}
+
+func (r *run) checkDuplicateIDs() {
+	seen := make(map[string]*core.RouteDefinition)
+	for _, route := range r.routes {
+		if route.ID == "" {
+			continue
+		}
+		if first, ok := seen[route.ID]; ok {
+			r.report(RuleDuplicateRouteID, SeverityError, route, core.SourceLocation{}, "duplicate route ID %q, first used at %s", route.ID, first.Location)
+			continue
+		}
+		seen[route.ID] = route
+	}
+}
+
+func (r *run) checkEndpoints() {
+	for _, route := range r.routes {
+		r.checkEndpoint(route, route.InputURI, route.Location)
+		walk(route.Steps, func(step core.Compilable) {
+			if e, ok := step.(core.EndpointAware); ok {
+				r.checkEndpoint(route, e.EndpointURI(), location(step))
+			}
+		})
+	}
+}
+
+func (r *run) checkEndpoint(route *core.RouteDefinition, uri string, loc core.SourceLocation) {
+	resolved, err := core.ResolvePlaceholders(r.ctx, uri)
+	if err != nil {
+		r.report(RuleInvalidEndpoint, SeverityError, route, loc, "%v", err)
+		return
+	}
+	scheme, _, ok := strings.Cut(resolved, ":")
+	if !ok || scheme == "" {
+		r.report(RuleInvalidEndpoint, SeverityError, route, loc, "invalid endpoint URI %q, expected scheme:path", uri)
+		return
+	}
+	if _, err := r.ctx.GetComponent(scheme); err != nil {
+		r.report(RuleUnknownComponent, SeverityError, route, loc, "unknown component %q in %q", scheme, uri)
+		return
+	}
+	if _, err := r.ctx.GetEndpoint(uri); err != nil {
+		r.report(RuleInvalidEndpoint, SeverityError, route, loc, "%v", err)
+	}
+}
+
+// directName returns the endpoint name of a direct URI after resolving its
+// placeholders. URIs with unresolvable placeholders are reported by
+// checkEndpoint.
+func (r *run) directName(uri string) (string, bool) {
+	uri, err := core.ResolvePlaceholders(r.ctx, uri)
+	if err != nil {
+		return "", false
+	}
+	rest, ok := strings.CutPrefix(uri, "direct:")
+	if !ok {
+		return "", false
+	}
+	name, _, _ := strings.Cut(rest, "?")
+	return name, true
+}
+
+func (r *run) checkDirect() {
+	consumers := make(map[string]*core.RouteDefinition)
+	for _, route := range r.routes {
+		if name, ok := r.directName(route.InputURI); ok {
+			consumers[name] = route
+		}
+	}
+
+	calls := make(map[*core.RouteDefinition][]*core.RouteDefinition)
+	for _, route := range r.routes {
+		walk(route.Steps, func(step core.Compilable) {
+			e, ok := step.(core.EndpointAware)
+			if !ok {
+				return
+			}
+			name, ok := r.directName(e.EndpointURI())
+			if !ok {
+				return
+			}
+			target, ok := consumers[name]
+			if !ok {
+				r.report(RuleUnresolvedDirect, SeverityError, route, location(step), "no route consumes from direct:%s", name)
+				return
+			}
+			calls[route] = append(calls[route], target)
+		})
+	}
+	r.findCycles(calls)
+}
+
+// findCycles reports each cycle of direct calls once, at the route that
+// appears first among the loaded routes.
+func (r *run) findCycles(calls map[*core.RouteDefinition][]*core.RouteDefinition) {
+	order := make(map[*core.RouteDefinition]int)
+	for i, route := range r.routes {
+		order[route] = i
+	}
+	reported := make(map[string]bool)
+	var path []*core.RouteDefinition
+	onPath := make(map[*core.RouteDefinition]int)
+	done := make(map[*core.RouteDefinition]bool)
+
+	var visit func(route *core.RouteDefinition)
+	visit = func(route *core.RouteDefinition) {
+		onPath[route] = len(path)
+		path = append(path, route)
+		for _, target := range calls[route] {
+			if i, ok := onPath[target]; ok {
+				cycle := append([]*core.RouteDefinition(nil), path[i:]...)
+				start := 0
+				for j, c := range cycle {
+					if order[c] < order[cycle[start]] {
+						start = j
+					}
+				}
+				cycle = append(cycle[start:], cycle[:start]...)
+				names := make([]string, 0, len(cycle)+1)
+				for _, c := range cycle {
+					names = append(names, routeName(c))
+				}
+				names = append(names, routeName(cycle[0]))
+				key := strings.Join(names, " -> ")
+				if !reported[key] {
+					reported[key] = true
+					r.report(RuleDirectCycle, SeverityError, cycle[0], core.SourceLocation{}, "direct calls form a cycle: %s", key)
+				}
+				continue
+			}
+			if !done[target] {
+				visit(target)
+			}
+		}
+		path = path[:len(path)-1]
+		delete(onPath, route)
+		done[route] = true
+	}
+	for _, route := range r.routes {
+		if !done[route] {
+			visit(route)
+		}
+	}
+}
+
+func (r *run) checkBranches() {
+	for _, route := range r.routes {
+		walk(route.Steps, func(step core.Compilable) {
+			switch d := step.(type) {
+			case *definitions.ChoiceDefinition:
+				for i, when := range d.WhenClauses {
+					value, static := staticValue(when.Condition)
+					if !static {
+						continue
+					}
+					if !value {
+						r.report(RuleUnreachableBranch, SeverityWarning, route, location(step), "when clause %d never matches", i+1)
+						continue
+					}
+					if i < len(d.WhenClauses)-1 {
+						r.report(RuleUnreachableBranch, SeverityWarning, route, location(step), "when clause %d always matches, so the when clauses after it never run", i+1)
+					}
+					if len(d.Otherwise) > 0 {
+						r.report(RuleUnreachableBranch, SeverityWarning, route, location(step), "when clause %d always matches, so otherwise never runs", i+1)
+					}
+					break
+				}
+			case *definitions.FilterDefinition:
+				if value, static := staticValue(d.Predicate); static && !value && len(d.Steps) > 0 {
+					r.report(RuleUnreachableBranch, SeverityWarning, route, location(step), "filter never matches, so its steps never run")
+				}
+			}
+		})
+	}
+}
+
+func staticValue(p core.Predicate) (value, ok bool) {
+	if s, is := p.(core.StaticPredicate); is {
+		return s.StaticValue()
+	}
+	return false, false
+}
+
+// checkCompile compiles each route that has no errors yet in a context of
+// its own.
+func (r *run) checkCompile() {
+	for _, route := range r.routes {
+		if r.failed[route] {
+			continue
+		}
+		ctx := r.newContext()
+		ctx.SetLoader(definitionLoader{})
+		if err := ctx.AddRoutes([]*core.RouteDefinition{route}); err != nil {
+			r.report(RuleCompile, SeverityError, route, core.SourceLocation{}, "%v", err)
+		}
+	}
+}
+
+// definitionLoader hands definitions that are already loaded to AddRoutes.
+type definitionLoader struct{}
+
+func (definitionLoader) Load(source interface{}) ([]*core.RouteDefinition, error) {
+	return source.([]*core.RouteDefinition), nil
+}
+
+// walk calls fn for steps and their nested steps, parents first.
+func walk(steps []core.Compilable, fn func(step core.Compilable)) {
+	for _, step := range steps {
+		fn(step)
+		if b, ok := step.(core.BranchingDefinition); ok {
+			for _, branch := range b.Branches() {
+				walk(branch.Steps, fn)
+			}
+		}
+	}
+}
+
+func location(step core.Compilable) core.SourceLocation {
+	if l, ok := step.(core.Locatable); ok {
+		return l.GetLocation()
+	}
+	return core.SourceLocation{}
+}
+
+func routeName(route *core.RouteDefinition) string {
+	if route.ID != "" {
+		return route.ID
+	}
+	return route.InputURI
+}
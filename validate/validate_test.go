@@ -0,0 +1,199 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sonyjop/camelgo/component/direct"
+	camellog "github.com/sonyjop/camelgo/component/log"
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/yamldsl"
+)
+
+func newContext() *core.DefaultContext {
+	ctx := core.NewContext()
+	ctx.RegisterComponent("direct", direct.NewDirectComponent())
+	ctx.RegisterComponent("log", camellog.NewLogComponent())
+	return ctx
+}
+
+const ordersYAML = `- route:
+    id: orders
+    from:
+      uri: direct:orders
+      steps:
+        - to: direct:billing
+        - to: kafka:orders
+        - to: log:orders?showBody=maybe
+        - to: direct:nowhere
+        - choice:
+            when:
+              - simple: "true"
+                steps:
+                  - to: log:a
+              - simple: ${header.express}
+                steps:
+                  - to: log:b
+            otherwise:
+              steps:
+                - to: log:c
+- route:
+    id: billing
+    from:
+      uri: direct:billing
+      steps:
+        - to: direct:audit
+- route:
+    id: audit
+    from:
+      uri: direct:audit
+      steps:
+        - to: direct:billing
+        - filter:
+            simple: "false"
+            steps:
+              - to: log:never
+`
+
+const dupYAML = `- route:
+    id: billing
+    from:
+      uri: direct:other
+      steps:
+        - setHeader: {name: a, constant: x}
+`
+
+func validateFiles(t *testing.T) ([]Issue, string) {
+	t.Helper()
+	dir := t.TempDir()
+	orders, dup := filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")
+	os.WriteFile(orders, []byte(ordersYAML), 0o644)
+	os.WriteFile(dup, []byte(dupYAML), 0o644)
+
+	v := New(newContext)
+	loader := yamldsl.NewLoader()
+	v.Load(orders, loader)
+	v.Load(dup, loader)
+	v.Load(filepath.Join(dir, "missing.yaml"), loader)
+	return v.Validate(), orders
+}
+
+func TestValidate_Rules(t *testing.T) {
+	issues, orders := validateFiles(t)
+
+	var got []string
+	for _, i := range issues {
+		got = append(got, strings.TrimPrefix(i.Location.String(), filepath.Dir(orders)+"/")+" "+i.Rule)
+	}
+	want := []string{
+		"a.yaml:7:11 unknown-component",
+		"a.yaml:8:11 invalid-endpoint",
+		"a.yaml:9:11 unresolved-direct",
+		"a.yaml:10:11 unreachable-branch",
+		"a.yaml:10:11 unreachable-branch",
+		"a.yaml:22:5 direct-cycle",
+		"a.yaml:33:11 unreachable-branch",
+		"b.yaml:2:5 duplicate-route-id",
+		"missing.yaml load",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected issues:\n%s\n\nall:\n%v", strings.Join(got, "\n"), issues)
+	}
+	if !strings.Contains(issues[5].Message, "billing -> audit -> billing") {
+		t.Errorf("unexpected cycle message %q", issues[5].Message)
+	}
+	if issues[3].Severity != SeverityWarning || !HasErrors(issues) {
+		t.Errorf("unexpected severities %+v", issues)
+	}
+}
+
+func TestValidate_CompileAndLoadPositions(t *testing.T) {
+	dir := t.TempDir()
+	compile := filepath.Join(dir, "compile.yaml")
+	broken := filepath.Join(dir, "broken.yaml")
+	os.WriteFile(compile, []byte("- route:\n    from:\n      uri: direct:a\n      steps:\n        - setHeader: {name: a, constant: x}\n"), 0o644)
+	os.WriteFile(broken, []byte("- route:\n    from:\n      uri: direct:b\n      steps:\n        - log: '${oops}'\n"), 0o644)
+
+	v := New(newContext)
+	v.Load(compile, yamldsl.NewLoader())
+	v.Load(broken, yamldsl.NewLoader())
+	issues := v.Validate()
+	if len(issues) != 1 || issues[0].Rule != RuleLoad || issues[0].Location.Line != 5 {
+		t.Fatalf("expected only the load error at line 5, got %v", issues)
+	}
+
+	// Definitions built in code fail at compile time instead.
+	v = New(newContext)
+	v.AddRoutes(&core.RouteDefinition{ID: "code", InputURI: "direct:c", Steps: []core.Compilable{&badStep{}}})
+	issues = v.Validate()
+	if len(issues) != 1 || issues[0].Rule != RuleCompile || !strings.Contains(issues[0].Message, "bad step") {
+		t.Errorf("expected a compile error, got %v", issues)
+	}
+}
+
+type badStep struct{}
+
+func (badStep) Compile(ctx core.CompileContext) (core.Processor, error) {
+	return nil, errors.New("bad step")
+}
+
+func TestWrite_Formats(t *testing.T) {
+	issues, _ := validateFiles(t)
+
+	var text bytes.Buffer
+	Write(&text, FormatText, issues)
+	if !strings.Contains(text.String(), "a.yaml:7:11: error: unknown component \"kafka\"") || !strings.HasSuffix(text.String(), "6 errors, 3 warnings\n") {
+		t.Errorf("unexpected text output:\n%s", text.String())
+	}
+
+	var out bytes.Buffer
+	Write(&out, FormatJSON, issues)
+	var decoded []Issue
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || len(decoded) != len(issues) {
+		t.Errorf("unexpected JSON output (%v):\n%s", err, out.String())
+	}
+
+	out.Reset()
+	Write(&out, FormatSARIF, issues)
+	var sarif sarifLog
+	if err := json.Unmarshal(out.Bytes(), &sarif); err != nil {
+		t.Fatalf("invalid SARIF: %v", err)
+	}
+	res := sarif.Runs[0].Results[0]
+	if sarif.Version != "2.1.0" || res.RuleID != RuleUnknownComponent || res.Locations[0].PhysicalLocation.Region.StartLine != 7 {
+		t.Errorf("unexpected SARIF result %+v", res)
+	}
+	if len(sarif.Runs[0].Tool.Driver.Rules) != len(RuleDescriptions) {
+		t.Errorf("expected every rule to be described")
+	}
+
+	if err := Write(&out, "xml", issues); err == nil {
+		t.Errorf("expected an unknown format to be rejected")
+	}
+}
+
+func TestValidate_FilterDiscardURI(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filter.yaml")
+	os.WriteFile(path, []byte(`- route:
+    from:
+      uri: direct:in
+      steps:
+        - filter:
+            simple: ${header.ok}
+            discardUri: direct:rejects
+            steps:
+              - to: log:ok
+`), 0o644)
+
+	v := New(newContext)
+	v.Load(path, yamldsl.NewLoader())
+	issues := v.Validate()
+	if len(issues) != 1 || issues[0].Rule != RuleUnresolvedDirect || issues[0].Location.Line != 5 {
+		t.Errorf("expected the discard URI to be checked at the filter, got %v", issues)
+	}
+}
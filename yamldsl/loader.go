@@ -0,0 +1,186 @@
+// Package yamldsl loads routes from YAML files:
+//
+//	# routes/orders.yaml
+//	- route:
+//	    id: orders
+//	    from:
+//	      uri: file:orders.txt
+//	      steps:
+//	        - setHeader: {name: source, constant: file}
+//	        - process: {ref: orderEnricher}
+//	        - filter:
+//	            simple: ${header.tier} == 'vip'
+//	            discardUri: direct:regular
+//	            steps:
+//	              - log: "VIP order ${body}"
+//	        - choice:
+//	            when:
+//	              - simple: ${header.express}
+//	                steps:
+//	                  - to: direct:express
+//	            otherwise:
+//	              steps:
+//	                - to: direct:standard
+//
+// Besides to, exchanges are sent with toD, whose uri is a simple template,
+// recipientList, whose expression lists the URIs, and routingSlip, which
+// names the header listing them. The aggregationStrategy of a
+// recipientList names a core.AggregationStrategy in the registry.
+//
+// Messages are changed with setBody, setHeader, setHeaders, removeHeader,
+// removeHeaders, setProperty, removeProperty, convertBodyTo (string, bytes,
+// int, float64, bool, ...) and transformTemplate, a text/template executed
+// with processors.TemplateData.
+//
+// Expressions are given as constant, simple (see language.Simple) or ref;
+// predicates as simple, matching when they evaluate to true or when a
+// comparison such as ${header.kind} == 'order' holds (see
+// language.SimplePredicate), or ref. A ref
+// names a bean in the context's registry, as does the ref of process.
+package yamldsl
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sonyjop/camelgo/core"
+)
+
+// Loader is the core.RouteLoader for YAML route files. Sources are file
+// paths (string) or YAML documents ([]byte); definitions loaded earlier
+// ([]*core.RouteDefinition) are passed through so they can be added again.
+type Loader struct{}
+
+func NewLoader() *Loader {
+	return &Loader{}
+}
+
+// Load reads the routes of source.
+func (l *Loader) Load(source interface{}) ([]*core.RouteDefinition, error) {
+	switch src := source.(type) {
+	case string:
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return nil, err
+		}
+		defs, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", src, err)
+		}
+		for _, def := range defs {
+			def.Location.File = src
+			setFile(def.Steps, src)
+		}
+		return defs, nil
+	case []byte:
+		return Parse(src)
+	case []*core.RouteDefinition:
+		return src, nil
+	default:
+		return nil, fmt.Errorf("yaml loader expected a file path or []byte, got %T", source)
+	}
+}
+
+type routeEntry struct {
+	Route *routeSpec `yaml:"route"`
+}
+
+type routeSpec struct {
+	ID   string   `yaml:"id"`
+	From fromSpec `yaml:"from"`
+
+	node *yaml.Node
+}
+
+type routeFields routeSpec
+
+func (r *routeSpec) UnmarshalYAML(node *yaml.Node) error {
+	r.node = node
+	return decode(node, (*routeFields)(r), "id", "from")
+}
+
+type fromSpec struct {
+	URI   string     `yaml:"uri"`
+	Steps []stepSpec `yaml:"steps"`
+}
+
+type fromFields fromSpec
+
+func (f *fromSpec) UnmarshalYAML(node *yaml.Node) error {
+	return decode(node, (*fromFields)(f), "uri", "steps")
+}
+
+// Parse reads the routes of a YAML document. Unknown keys are errors, so
+// typos do not go unnoticed.
+func Parse(data []byte) ([]*core.RouteDefinition, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var entries []routeEntry
+	if err := dec.Decode(&entries); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no routes defined")
+		}
+		return nil, err
+	}
+
+	var defs []*core.RouteDefinition
+	for i, e := range entries {
+		if e.Route == nil {
+			return nil, fmt.Errorf("entry %d: only route entries are supported", i+1)
+		}
+		node := e.Route.node
+		if e.Route.From.URI == "" {
+			return nil, errorAt(node, "route %d: from.uri is required", i+1)
+		}
+		def := &core.RouteDefinition{
+			ID:       e.Route.ID,
+			InputURI: e.Route.From.URI,
+			Location: core.SourceLocation{Line: node.Line, Column: node.Column},
+		}
+		for _, s := range e.Route.From.Steps {
+			def.Steps = append(def.Steps, s.def)
+		}
+		defs = append(defs, def)
+	}
+	if len(defs) == 0 {
+		return nil, fmt.Errorf("no routes defined")
+	}
+	return defs, nil
+}
+
+// setFile records file in the locations of steps and their nested steps.
+func setFile(steps []core.Compilable, file string) {
+	for _, step := range steps {
+		if l, ok := step.(core.Locatable); ok {
+			loc := l.GetLocation()
+			loc.File = file
+			l.SetLocation(loc)
+		}
+		if b, ok := step.(core.BranchingDefinition); ok {
+			for _, branch := range b.Branches() {
+				setFile(branch.Steps, file)
+			}
+		}
+	}
+}
+
+// Error is a problem at a position in a YAML document.
+type Error struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+func errorAt(node *yaml.Node, format string, args ...interface{}) error {
+	return &Error{Line: node.Line, Column: node.Column, Msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,354 @@
+package yamldsl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sonyjop/camelgo/component/direct"
+	"github.com/sonyjop/camelgo/core"
+)
+
+const ordersYAML = `
+- route:
+    id: orders
+    from:
+      uri: direct:orders
+      steps:
+        - setHeader: {id: source, name: source, constant: yaml}
+        - filter:
+            simple: ${header.vip}
+            steps:
+              - setHeader: {name: tier, simple: "gold ${body}"}
+        - choice:
+            when:
+              - simple: ${header.express}
+                steps:
+                  - to: direct:express
+            otherwise:
+              steps:
+                - to: {id: standard, uri: direct:standard}
+        - log: {message: "done ${body}", level: debug}
+- route:
+    id: express
+    from:
+      uri: direct:express
+      steps:
+        - setHeader: {name: lane, constant: express}
+- route:
+    id: standard
+    from:
+      uri: direct:standard
+      steps:
+        - setHeader: {name: lane, constant: standard}
+`
+
+func TestLoader_RunsRoutes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.yaml")
+	os.WriteFile(path, []byte(ordersYAML), 0o644)
+
+	ctx := core.NewContext()
+	ctx.RegisterComponent("direct", direct.NewDirectComponent())
+	ctx.SetLoader(NewLoader())
+	if err := ctx.AddRoutes(path); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatalf("start error: %v", err)
+	}
+	defer ctx.Stop()
+
+	if len(ctx.Routes()) != 3 {
+		t.Fatalf("expected 3 routes, got %d", len(ctx.Routes()))
+	}
+	p, _ := ctx.CreateProducer("direct:orders")
+	for _, c := range []struct {
+		vip, express string
+		tier, lane   interface{}
+	}{
+		{"true", "true", "gold 7", "express"},
+		{"false", "no", nil, "standard"},
+	} {
+		ex := ctx.NewExchange()
+		ex.In().SetBody(7)
+		ex.In().SetHeader("vip", c.vip)
+		ex.In().SetHeader("express", c.express)
+		if err := p.Process(ctx, ex); err != nil {
+			t.Fatalf("process error: %v", err)
+		}
+		h := ex.In()
+		if h.Header("source") != "yaml" || h.Header("tier") != c.tier || h.Header("lane") != c.lane {
+			t.Errorf("unexpected headers %v", h.Headers())
+		}
+	}
+	if ctx.Route("orders").StepStatistics("standard").ExchangesTotal != 1 {
+		t.Errorf("expected the step ID from YAML to be used")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := map[string]string{
+		"":                 "no routes",
+		"- route: {id: a}": "from.uri is required",
+		"- rest: {}":       "field rest not found",
+		"- route: {from: {uri: direct:a, steps: [{bogus: x}]}}":                                       `unknown step "bogus"`,
+		"- route: {from: {uri: direct:a, steps: [{to: {uri: direct:b, x: 1}}]}}":                      `unknown key "x"`,
+		"- route: {from: {uri: direct:a, steps: [{setHeader: {name: a}}]}}":                           "expression (constant, simple or ref) is required",
+		"- route: {from: {uri: direct:a, steps: [{filter: {simple: '${nope}'}}]}}":                    "unknown function",
+		"- route: {from: {uri: direct:a, steps: [{filter: {simple: 'kind=${header.kind}'}}]}}":        "never true",
+		"- route: {from: {uri: direct:a, steps: [{log: {message: x, level: loud}}]}}":                 "unknown log level",
+		"- route: {from: {uri: direct:a, steps: [{log: {message: x, level: panic}}]}}":                "unknown log level",
+		"- route: {from: {uri: direct:a, steps: [{choice: {otherwise: {steps: []}}}]}}":               "at least one when clause",
+		"- route: {from: {uri: direct:a, steps: [{setHeaders: {headers: [{simple: x}]}}]}}":           "name is required",
+		"- route: {from: {uri: direct:a, steps: [{convertBodyTo: decimal}]}}":                         `unknown type "decimal"`,
+		"- route: {from: {uri: direct:a, steps: [{transformTemplate: '{{.Body'}]}}":                   "unclosed action",
+		"- route: {from: {uri: direct:a, steps: [{toD: 'direct:${nope}'}]}}":                          "unknown function",
+		"- route: {from: {uri: direct:a, steps: [{routingSlip: {delimiter: ;}}]}}":                    "header is required",
+		"- route: {from: {uri: direct:a, steps: [{recipientList: {parallelProcessing: true}}]}}":      "expression (constant, simple or ref) is required",
+		"- route: {from: {uri: direct:a, steps: [{removeHeaders: {exclude: [a]}}]}}":                  "pattern is required",
+		"- route: {from: {uri: direct:a, steps: [{setProperty: {name: p, constant: a, simple: b}}]}}": "give only one of",
+	}
+	for doc, want := range cases {
+		_, err := Parse([]byte(doc))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected error containing %q, got %v", doc, want, err)
+		}
+	}
+}
+
+func TestLoader_Locations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.yaml")
+	os.WriteFile(path, []byte(ordersYAML), 0o644)
+
+	defs, err := NewLoader().Load(path)
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	if got := defs[0].Location; got.File != path || got.Line != 3 {
+		t.Errorf("unexpected route location %v", got)
+	}
+	filter := defs[0].Steps[1].(core.Locatable).GetLocation()
+	nested := defs[0].Steps[1].(core.BranchingDefinition).Branches()[0].Steps[0].(core.Locatable).GetLocation()
+	if filter.String() != path+":8:11" || nested.String() != path+":11:17" {
+		t.Errorf("unexpected step locations %v, %v", filter, nested)
+	}
+
+	_, err = Parse([]byte("- route:\n    from:\n      uri: direct:a\n      steps:\n        - log: '${oops}'\n"))
+	var posErr *Error
+	if !errors.As(err, &posErr) || posErr.Line != 5 {
+		t.Errorf("expected an error at line 5, got %v", err)
+	}
+}
+
+func TestLoader_Refs(t *testing.T) {
+	ctx := core.NewContext()
+	ctx.RegisterComponent("direct", direct.NewDirectComponent())
+	ctx.SetLoader(NewLoader())
+	ctx.Registry().Bind("isVip", core.PredicateFunc(func(ctx core.Context, ex *core.Exchange) (bool, error) {
+		return ex.In().Body() == "vip", nil
+	}))
+	ctx.Registry().Bind("shout", core.ExpressionFunc(func(ctx core.Context, ex *core.Exchange) (interface{}, error) {
+		return strings.ToUpper(ex.In().Body().(string)), nil
+	}))
+	ctx.Registry().Bind("tagger", &tagProcessor{})
+
+	routes := `
+- route:
+    id: refs
+    from:
+      uri: direct:refs
+      steps:
+        - process: {ref: tagger}
+        - filter:
+            ref: isVip
+            steps:
+              - setHeader: {name: loud, ref: shout}
+`
+	if err := ctx.AddRoutes([]byte(routes)); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	for body, loud := range map[string]interface{}{"vip": "VIP", "regular": nil} {
+		ex := ctx.NewExchange()
+		ex.In().SetBody(body)
+		if err := ctx.Route("refs").Pipeline.Process(ctx, ex); err != nil {
+			t.Fatal(err)
+		}
+		if ex.In().Header("tagged") != true || ex.In().Header("loud") != loud {
+			t.Errorf("%s: unexpected headers %v", body, ex.In().Headers())
+		}
+	}
+
+	err := ctx.AddRoutes([]byte("- route: {from: {uri: direct:x, steps: [{process: {ref: missing}}]}}"))
+	if err == nil || !strings.Contains(err.Error(), `no bean named "missing"`) {
+		t.Errorf("expected a missing processor bean to fail AddRoutes, got %v", err)
+	}
+}
+
+func TestLoader_MessageTransformation(t *testing.T) {
+	ctx := core.NewContext()
+	ctx.RegisterComponent("direct", direct.NewDirectComponent())
+	ctx.SetLoader(NewLoader())
+
+	routes := `
+- route:
+    id: transform
+    from:
+      uri: direct:transform
+      steps:
+        - setProperty: {name: original, simple: "${body}"}
+        - convertBodyTo: int
+        - setHeaders:
+            headers:
+              - {name: qty, simple: "${body}"}
+              - {name: unit, constant: kg}
+        - removeHeaders: {pattern: "tmp*", exclude: [tmpKeep]}
+        - removeHeader: secret
+        - setBody: {simple: "${header.qty} ${header.unit}"}
+        - transformTemplate: "{{.Body}} for {{.Properties.original}}"
+        - removeProperty: original
+`
+	if err := ctx.AddRoutes([]byte(routes)); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	ex := ctx.NewExchange()
+	ex.In().SetBody(" 12 ")
+	for _, h := range []string{"tmpA", "tmpKeep", "secret", "keep"} {
+		ex.In().SetHeader(h, "x")
+	}
+	if err := ctx.Route("transform").Pipeline.Process(ctx, ex); err != nil {
+		t.Fatal(err)
+	}
+	if body := ex.In().Body(); body != "12 kg for  12 " {
+		t.Errorf("unexpected body %q", body)
+	}
+	if ex.In().Header("qty") != 12 {
+		t.Errorf("expected the body converted to int before setHeaders, got %#v", ex.In().Header("qty"))
+	}
+	for h, present := range map[string]bool{"tmpA": false, "tmpKeep": true, "secret": false, "keep": true} {
+		if _, ok := ex.In().Headers()[h]; ok != present {
+			t.Errorf("header %s: expected present=%v", h, present)
+		}
+	}
+	if _, ok := ex.Properties()["original"]; ok {
+		t.Error("expected removeProperty to remove the property")
+	}
+}
+
+func TestLoader_FilterDiscard(t *testing.T) {
+	ctx := core.NewContext()
+	ctx.RegisterComponent("direct", direct.NewDirectComponent())
+	ctx.SetLoader(NewLoader())
+
+	routes := `
+- route:
+    id: orders
+    from:
+      uri: direct:orders
+      steps:
+        - filter:
+            simple: ${header.kind} == 'order'
+            discardUri: direct:rejected
+            steps:
+              - setHeader: {name: accepted, constant: "yes"}
+- route:
+    id: rejected
+    from:
+      uri: direct:rejected
+      steps:
+        - setHeader: {name: rejected, constant: "yes"}
+`
+	if err := ctx.AddRoutes([]byte(routes)); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Stop()
+
+	ex := ctx.NewExchange()
+	ex.In().SetHeader("kind", "refund")
+	if err := ctx.Route("orders").Pipeline.Process(ctx, ex); err != nil {
+		t.Fatal(err)
+	}
+	if ex.In().Header("rejected") != "yes" || ex.In().Header("accepted") != nil {
+		t.Errorf("expected the exchange to be discarded to direct:rejected, got %v", ex.In().Headers())
+	}
+	ex = ctx.NewExchange()
+	ex.In().SetHeader("kind", "order")
+	if err := ctx.Route("orders").Pipeline.Process(ctx, ex); err != nil {
+		t.Fatal(err)
+	}
+	if ex.In().Header("accepted") != "yes" {
+		t.Errorf("expected an order to pass the filter, got %v", ex.In().Headers())
+	}
+	if st := ctx.Route("orders").Statistics(); st.ExchangesFiltered != 1 {
+		t.Errorf("expected one filtered exchange, got %+v", st)
+	}
+}
+
+func TestLoader_DynamicEndpoints(t *testing.T) {
+	ctx := core.NewContext()
+	ctx.RegisterComponent("direct", direct.NewDirectComponent())
+	ctx.SetLoader(NewLoader())
+	ctx.Registry().Bind("grouped", core.GroupedBodyStrategy{})
+
+	routes := `
+- route:
+    id: main
+    from:
+      uri: direct:main
+      steps:
+        - toD: direct:${header.first}
+        - recipientList:
+            simple: direct:b;direct:c
+            delimiter: ";"
+            parallelProcessing: true
+            aggregationStrategy: grouped
+        - setHeader: {name: slip, constant: "direct:b,direct:c"}
+        - setBody: {constant: "s"}
+        - routingSlip: slip
+- route: {from: {uri: direct:a, steps: [{setBody: {simple: "${body}a"}}]}}
+- route: {from: {uri: direct:b, steps: [{setBody: {simple: "${body}b"}}]}}
+- route: {from: {uri: direct:c, steps: [{setBody: {simple: "${body}c"}}]}}
+`
+	if err := ctx.AddRoutes([]byte(routes)); err != nil {
+		t.Fatalf("AddRoutes error: %v", err)
+	}
+	if err := ctx.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer ctx.Stop()
+
+	var grouped interface{}
+	ctx.Registry().Bind("grouped", core.AggregationStrategyFunc(func(old, ex *core.Exchange) (*core.Exchange, error) {
+		out, err := core.GroupedBodyStrategy{}.Aggregate(old, ex)
+		grouped = out.In().Body()
+		return out, err
+	}))
+	ex := ctx.NewExchange()
+	ex.In().SetBody("x")
+	ex.In().SetHeader("first", "a")
+	if err := ctx.Route("main").Pipeline.Process(ctx, ex); err != nil {
+		t.Fatal(err)
+	}
+	if ex.In().Body() != "sbc" {
+		t.Errorf("expected the routing slip to run b then c, got %v", ex.In().Body())
+	}
+	if grouped != nil {
+		t.Errorf("expected the strategy to be looked up when the route is added, got %v", grouped)
+	}
+
+	err := ctx.AddRoutes([]byte("- route: {from: {uri: direct:x, steps: [{recipientList: {constant: direct:a, aggregationStrategy: missing}}]}}"))
+	if err == nil || !strings.Contains(err.Error(), `no bean named "missing"`) {
+		t.Errorf("expected a missing strategy to fail AddRoutes, got %v", err)
+	}
+}
+
+type tagProcessor struct{}
+
+func (tagProcessor) Process(ctx core.Context, ex *core.Exchange) error {
+	ex.In().SetHeader("tagged", true)
+	return nil
+}
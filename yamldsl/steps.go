@@ -0,0 +1,597 @@
+package yamldsl
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/sonyjop/camelgo/core"
+	"github.com/sonyjop/camelgo/definitions"
+	"github.com/sonyjop/camelgo/language"
+)
+
+// stepSpec is one entry of a steps list: a mapping with the EIP name as its
+// only key.
+type stepSpec struct {
+	def core.Compilable
+}
+
+func (s *stepSpec) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode || len(node.Content) != 2 {
+		return errorAt(node, "a step must be a mapping with a single EIP name, e.g. \"- to: direct:next\"")
+	}
+	name, body := node.Content[0].Value, node.Content[1]
+
+	var err error
+	switch name {
+	case "to":
+		s.def, err = toStep(body)
+	case "toD":
+		s.def, err = toDStep(body)
+	case "recipientList":
+		s.def, err = recipientListStep(body)
+	case "routingSlip":
+		s.def, err = routingSlipStep(body)
+	case "setHeader":
+		s.def, err = setHeaderStep(body)
+	case "setHeaders":
+		s.def, err = setHeadersStep(body)
+	case "removeHeader":
+		s.def, err = removeHeaderStep(body)
+	case "removeHeaders":
+		s.def, err = removeHeadersStep(body)
+	case "setBody":
+		s.def, err = setBodyStep(body)
+	case "setProperty":
+		s.def, err = setPropertyStep(body)
+	case "removeProperty":
+		s.def, err = removePropertyStep(body)
+	case "convertBodyTo":
+		s.def, err = convertBodyToStep(body)
+	case "transformTemplate":
+		s.def, err = transformTemplateStep(body)
+	case "log":
+		s.def, err = logStep(body)
+	case "filter":
+		s.def, err = filterStep(body)
+	case "choice":
+		s.def, err = choiceStep(body)
+	case "process":
+		s.def, err = processStep(body)
+	default:
+		return errorAt(node, "unknown step %q", name)
+	}
+	if err != nil {
+		var posErr *Error
+		if errors.As(err, &posErr) {
+			posErr.Msg = name + ": " + posErr.Msg
+			return posErr
+		}
+		return errorAt(node, "%s: %v", name, err)
+	}
+	if l, ok := s.def.(core.Locatable); ok {
+		l.SetLocation(core.SourceLocation{Line: node.Line, Column: node.Column})
+	}
+	return nil
+}
+
+// expressionSpec is embedded by steps taking an expression.
+type expressionSpec struct {
+	Constant *string `yaml:"constant"`
+	Simple   *string `yaml:"simple"`
+	Ref      *string `yaml:"ref"`
+}
+
+// count returns the number of expression languages given.
+func (e expressionSpec) count() int {
+	n := 0
+	for _, lang := range []*string{e.Constant, e.Simple, e.Ref} {
+		if lang != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func (e expressionSpec) expression(node *yaml.Node) (core.Expression, error) {
+	switch {
+	case e.count() > 1:
+		return nil, errorAt(node, "give only one of constant, simple or ref")
+	case e.Ref != nil:
+		return language.Ref(*e.Ref), nil
+	case e.Constant != nil:
+		return language.Constant(*e.Constant), nil
+	case e.Simple != nil:
+		expr, err := language.Simple(*e.Simple)
+		if err != nil {
+			return nil, errorAt(node, "%v", err)
+		}
+		return expr, nil
+	}
+	return nil, errorAt(node, "an expression (constant, simple or ref) is required")
+}
+
+func (e expressionSpec) predicate(node *yaml.Node) (core.Predicate, error) {
+	if e.Constant == nil && e.Simple == nil && e.Ref != nil {
+		return language.RefPredicate(*e.Ref), nil
+	}
+	if e.Constant != nil || e.Simple == nil || e.Ref != nil {
+		return nil, errorAt(node, "a simple or ref predicate is required")
+	}
+	pred, err := language.SimplePredicate(*e.Simple)
+	if err != nil {
+		return nil, errorAt(node, "%v", err)
+	}
+	return pred, nil
+}
+
+type toSpec struct {
+	ID  string `yaml:"id"`
+	URI string `yaml:"uri"`
+}
+
+func toStep(node *yaml.Node) (core.Compilable, error) {
+	if node.Kind == yaml.ScalarNode {
+		return &definitions.ToDefinition{URI: node.Value}, nil
+	}
+	var spec toSpec
+	if err := decode(node, &spec, "id", "uri"); err != nil {
+		return nil, err
+	}
+	if spec.URI == "" {
+		return nil, errorAt(node, "uri is required")
+	}
+	return &definitions.ToDefinition{Identity: definitions.Identity{ID: spec.ID}, URI: spec.URI}, nil
+}
+
+type toDSpec struct {
+	ID        string `yaml:"id"`
+	URI       string `yaml:"uri"`
+	CacheSize int    `yaml:"cacheSize"`
+}
+
+func toDStep(node *yaml.Node) (core.Compilable, error) {
+	var spec toDSpec
+	if node.Kind == yaml.ScalarNode {
+		spec.URI = node.Value
+	} else if err := decode(node, &spec, "id", "uri", "cacheSize"); err != nil {
+		return nil, err
+	}
+	if spec.URI == "" {
+		return nil, errorAt(node, "uri is required")
+	}
+	if _, err := language.Simple(spec.URI); err != nil {
+		return nil, errorAt(node, "%v", err)
+	}
+	return &definitions.ToDynamicDefinition{Identity: definitions.Identity{ID: spec.ID}, URI: spec.URI, CacheSize: spec.CacheSize}, nil
+}
+
+type recipientListSpec struct {
+	ID                  string `yaml:"id"`
+	expressionSpec      `yaml:",inline"`
+	Delimiter           string `yaml:"delimiter"`
+	ParallelProcessing  bool   `yaml:"parallelProcessing"`
+	AggregationStrategy string `yaml:"aggregationStrategy"`
+	CacheSize           int    `yaml:"cacheSize"`
+}
+
+func recipientListStep(node *yaml.Node) (core.Compilable, error) {
+	var spec recipientListSpec
+	if err := decode(node, &spec, "id", "constant", "simple", "ref", "delimiter", "parallelProcessing", "aggregationStrategy", "cacheSize"); err != nil {
+		return nil, err
+	}
+	expr, err := spec.expression(node)
+	if err != nil {
+		return nil, err
+	}
+	return &definitions.RecipientListDefinition{
+		Identity:               definitions.Identity{ID: spec.ID},
+		Expression:             expr,
+		Delimiter:              spec.Delimiter,
+		ParallelProcessing:     spec.ParallelProcessing,
+		AggregationStrategyRef: spec.AggregationStrategy,
+		CacheSize:              spec.CacheSize,
+	}, nil
+}
+
+type routingSlipSpec struct {
+	ID        string `yaml:"id"`
+	Header    string `yaml:"header"`
+	Delimiter string `yaml:"delimiter"`
+	CacheSize int    `yaml:"cacheSize"`
+}
+
+func routingSlipStep(node *yaml.Node) (core.Compilable, error) {
+	var spec routingSlipSpec
+	if node.Kind == yaml.ScalarNode {
+		spec.Header = node.Value
+	} else if err := decode(node, &spec, "id", "header", "delimiter", "cacheSize"); err != nil {
+		return nil, err
+	}
+	if spec.Header == "" {
+		return nil, errorAt(node, "header is required")
+	}
+	return &definitions.RoutingSlipDefinition{
+		Identity:   definitions.Identity{ID: spec.ID},
+		Expression: language.Header(spec.Header),
+		Delimiter:  spec.Delimiter,
+		CacheSize:  spec.CacheSize,
+	}, nil
+}
+
+type setHeaderSpec struct {
+	ID             string `yaml:"id"`
+	Name           string `yaml:"name"`
+	expressionSpec `yaml:",inline"`
+}
+
+func setHeaderStep(node *yaml.Node) (core.Compilable, error) {
+	var spec setHeaderSpec
+	if err := decode(node, &spec, "id", "name", "constant", "simple", "ref"); err != nil {
+		return nil, err
+	}
+	if spec.Name == "" {
+		return nil, errorAt(node, "name is required")
+	}
+	expr, err := spec.expression(node)
+	if err != nil {
+		return nil, err
+	}
+	return &definitions.SetHeaderDefinition{Identity: definitions.Identity{ID: spec.ID}, Name: spec.Name, Expression: expr}, nil
+}
+
+// headerSpec is one header of setHeaders.
+type headerSpec struct {
+	Name           string `yaml:"name"`
+	expressionSpec `yaml:",inline"`
+
+	node *yaml.Node
+}
+
+type headerFields headerSpec
+
+func (h *headerSpec) UnmarshalYAML(node *yaml.Node) error {
+	h.node = node
+	return decode(node, (*headerFields)(h), "name", "constant", "simple", "ref")
+}
+
+type setHeadersSpec struct {
+	ID      string       `yaml:"id"`
+	Headers []headerSpec `yaml:"headers"`
+}
+
+func setHeadersStep(node *yaml.Node) (core.Compilable, error) {
+	var spec setHeadersSpec
+	if err := decode(node, &spec, "id", "headers"); err != nil {
+		return nil, err
+	}
+	if len(spec.Headers) == 0 {
+		return nil, errorAt(node, "at least one header is required")
+	}
+	headers := make(map[string]core.Expression, len(spec.Headers))
+	for _, h := range spec.Headers {
+		if h.Name == "" {
+			return nil, errorAt(h.node, "name is required")
+		}
+		if _, dup := headers[h.Name]; dup {
+			return nil, errorAt(h.node, "header %q is set twice", h.Name)
+		}
+		expr, err := h.expression(h.node)
+		if err != nil {
+			return nil, err
+		}
+		headers[h.Name] = expr
+	}
+	return &definitions.SetHeadersDefinition{Identity: definitions.Identity{ID: spec.ID}, Headers: headers}, nil
+}
+
+type nameSpec struct {
+	ID   string `yaml:"id"`
+	Name string `yaml:"name"`
+}
+
+// nameStep decodes steps taking just a name, given as a scalar or as
+// {id, name}.
+func nameStep(node *yaml.Node) (nameSpec, error) {
+	var spec nameSpec
+	if node.Kind == yaml.ScalarNode {
+		spec.Name = node.Value
+	} else if err := decode(node, &spec, "id", "name"); err != nil {
+		return spec, err
+	}
+	if spec.Name == "" {
+		return spec, errorAt(node, "name is required")
+	}
+	return spec, nil
+}
+
+func removeHeaderStep(node *yaml.Node) (core.Compilable, error) {
+	spec, err := nameStep(node)
+	if err != nil {
+		return nil, err
+	}
+	return &definitions.RemoveHeaderDefinition{Identity: definitions.Identity{ID: spec.ID}, Name: spec.Name}, nil
+}
+
+type removeHeadersSpec struct {
+	ID      string   `yaml:"id"`
+	Pattern string   `yaml:"pattern"`
+	Exclude []string `yaml:"exclude"`
+}
+
+func removeHeadersStep(node *yaml.Node) (core.Compilable, error) {
+	var spec removeHeadersSpec
+	if node.Kind == yaml.ScalarNode {
+		spec.Pattern = node.Value
+	} else if err := decode(node, &spec, "id", "pattern", "exclude"); err != nil {
+		return nil, err
+	}
+	if spec.Pattern == "" {
+		return nil, errorAt(node, "pattern is required")
+	}
+	return &definitions.RemoveHeadersDefinition{Identity: definitions.Identity{ID: spec.ID}, Pattern: spec.Pattern, Exclude: spec.Exclude}, nil
+}
+
+type setBodySpec struct {
+	ID             string `yaml:"id"`
+	expressionSpec `yaml:",inline"`
+}
+
+func setBodyStep(node *yaml.Node) (core.Compilable, error) {
+	var spec setBodySpec
+	if err := decode(node, &spec, "id", "constant", "simple", "ref"); err != nil {
+		return nil, err
+	}
+	expr, err := spec.expression(node)
+	if err != nil {
+		return nil, err
+	}
+	return &definitions.SetBodyDefinition{Identity: definitions.Identity{ID: spec.ID}, Expression: expr}, nil
+}
+
+func setPropertyStep(node *yaml.Node) (core.Compilable, error) {
+	var spec setHeaderSpec
+	if err := decode(node, &spec, "id", "name", "constant", "simple", "ref"); err != nil {
+		return nil, err
+	}
+	if spec.Name == "" {
+		return nil, errorAt(node, "name is required")
+	}
+	expr, err := spec.expression(node)
+	if err != nil {
+		return nil, err
+	}
+	return &definitions.SetPropertyDefinition{Identity: definitions.Identity{ID: spec.ID}, Name: spec.Name, Expression: expr}, nil
+}
+
+func removePropertyStep(node *yaml.Node) (core.Compilable, error) {
+	spec, err := nameStep(node)
+	if err != nil {
+		return nil, err
+	}
+	return &definitions.RemovePropertyDefinition{Identity: definitions.Identity{ID: spec.ID}, Name: spec.Name}, nil
+}
+
+// bodyTypes are the types convertBodyTo accepts by name.
+var bodyTypes = map[string]reflect.Type{
+	"string":  reflect.TypeOf(""),
+	"bytes":   reflect.TypeOf([]byte(nil)),
+	"[]byte":  reflect.TypeOf([]byte(nil)),
+	"int":     reflect.TypeOf(0),
+	"int32":   reflect.TypeOf(int32(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+	"bool":    reflect.TypeOf(false),
+}
+
+type convertBodyToSpec struct {
+	ID   string `yaml:"id"`
+	Type string `yaml:"type"`
+}
+
+func convertBodyToStep(node *yaml.Node) (core.Compilable, error) {
+	var spec convertBodyToSpec
+	if node.Kind == yaml.ScalarNode {
+		spec.Type = node.Value
+	} else if err := decode(node, &spec, "id", "type"); err != nil {
+		return nil, err
+	}
+	t, ok := bodyTypes[spec.Type]
+	if !ok {
+		names := make([]string, 0, len(bodyTypes))
+		for name := range bodyTypes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, errorAt(node, "unknown type %q, expected one of %s", spec.Type, strings.Join(names, ", "))
+	}
+	return &definitions.ConvertBodyDefinition{Identity: definitions.Identity{ID: spec.ID}, Type: t}, nil
+}
+
+type transformTemplateSpec struct {
+	ID       string `yaml:"id"`
+	Template string `yaml:"template"`
+}
+
+func transformTemplateStep(node *yaml.Node) (core.Compilable, error) {
+	var spec transformTemplateSpec
+	if node.Kind == yaml.ScalarNode {
+		spec.Template = node.Value
+	} else if err := decode(node, &spec, "id", "template"); err != nil {
+		return nil, err
+	}
+	if _, err := template.New("transformTemplate").Parse(spec.Template); err != nil {
+		return nil, errorAt(node, "%v", err)
+	}
+	return &definitions.TransformTemplateDefinition{Identity: definitions.Identity{ID: spec.ID}, Template: spec.Template}, nil
+}
+
+type logSpec struct {
+	ID         string `yaml:"id"`
+	Message    string `yaml:"message"`
+	Level      string `yaml:"level"`
+	LoggerName string `yaml:"loggerName"`
+}
+
+// logLevels are the levels of the log step. Panic and fatal are left out,
+// as logging at them would stop the route or the process.
+var logLevels = map[string]logrus.Level{
+	"trace":   logrus.TraceLevel,
+	"debug":   logrus.DebugLevel,
+	"info":    logrus.InfoLevel,
+	"warn":    logrus.WarnLevel,
+	"warning": logrus.WarnLevel,
+	"error":   logrus.ErrorLevel,
+}
+
+func logStep(node *yaml.Node) (core.Compilable, error) {
+	var spec logSpec
+	if node.Kind == yaml.ScalarNode {
+		spec.Message = node.Value
+	} else if err := decode(node, &spec, "id", "message", "level", "loggerName"); err != nil {
+		return nil, err
+	}
+	msg, err := language.Simple(spec.Message)
+	if err != nil {
+		return nil, errorAt(node, "%v", err)
+	}
+	level := logrus.InfoLevel
+	if spec.Level != "" {
+		var ok bool
+		if level, ok = logLevels[spec.Level]; !ok {
+			return nil, errorAt(node, "unknown log level %q (trace, debug, info, warn or error)", spec.Level)
+		}
+	}
+	return &definitions.LogDefinition{Identity: definitions.Identity{ID: spec.ID}, Message: msg, Level: level, LoggerName: spec.LoggerName}, nil
+}
+
+type processSpec struct {
+	ID  string `yaml:"id"`
+	Ref string `yaml:"ref"`
+}
+
+func processStep(node *yaml.Node) (core.Compilable, error) {
+	var spec processSpec
+	if err := decode(node, &spec, "id", "ref"); err != nil {
+		return nil, err
+	}
+	if spec.Ref == "" {
+		return nil, errorAt(node, "ref is required")
+	}
+	return &definitions.ProcessDefinition{Identity: definitions.Identity{ID: spec.ID}, Ref: spec.Ref}, nil
+}
+
+type filterSpec struct {
+	ID             string `yaml:"id"`
+	expressionSpec `yaml:",inline"`
+	DiscardURI     string     `yaml:"discardUri"`
+	Steps          []stepSpec `yaml:"steps"`
+}
+
+func filterStep(node *yaml.Node) (core.Compilable, error) {
+	var spec filterSpec
+	if err := decode(node, &spec, "id", "simple", "ref", "discardUri", "steps"); err != nil {
+		return nil, err
+	}
+	pred, err := spec.predicate(node)
+	if err != nil {
+		return nil, err
+	}
+	return &definitions.FilterDefinition{
+		Identity:   definitions.Identity{ID: spec.ID},
+		Predicate:  pred,
+		Steps:      compilables(spec.Steps),
+		DiscardURI: spec.DiscardURI,
+	}, nil
+}
+
+type choiceSpec struct {
+	ID        string         `yaml:"id"`
+	When      []whenSpec     `yaml:"when"`
+	Otherwise *otherwiseSpec `yaml:"otherwise"`
+}
+
+type whenSpec struct {
+	expressionSpec `yaml:",inline"`
+	Steps          []stepSpec `yaml:"steps"`
+
+	node *yaml.Node
+}
+
+// whenFields has whenSpec's fields without its UnmarshalYAML.
+type whenFields whenSpec
+
+func (w *whenSpec) UnmarshalYAML(node *yaml.Node) error {
+	w.node = node
+	return decode(node, (*whenFields)(w), "simple", "ref", "steps")
+}
+
+type otherwiseSpec struct {
+	Steps []stepSpec `yaml:"steps"`
+}
+
+type otherwiseFields otherwiseSpec
+
+func (o *otherwiseSpec) UnmarshalYAML(node *yaml.Node) error {
+	return decode(node, (*otherwiseFields)(o), "steps")
+}
+
+func choiceStep(node *yaml.Node) (core.Compilable, error) {
+	var spec choiceSpec
+	if err := decode(node, &spec, "id", "when", "otherwise"); err != nil {
+		return nil, err
+	}
+	if len(spec.When) == 0 {
+		return nil, errorAt(node, "at least one when clause is required")
+	}
+	def := &definitions.ChoiceDefinition{Identity: definitions.Identity{ID: spec.ID}}
+	for _, when := range spec.When {
+		pred, err := when.predicate(when.node)
+		if err != nil {
+			return nil, err
+		}
+		def.WhenClauses = append(def.WhenClauses, &definitions.WhenDefinition{Condition: pred, Steps: compilables(when.Steps)})
+	}
+	if spec.Otherwise != nil {
+		def.Otherwise = compilables(spec.Otherwise.Steps)
+	}
+	return def, nil
+}
+
+// decode decodes a mapping into out, rejecting keys other than allowed.
+// Decoding through a Node does not honour the decoder's KnownFields.
+func decode(node *yaml.Node, out interface{}, allowed ...string) error {
+	if node.Kind != yaml.MappingNode {
+		return errorAt(node, "expected a mapping")
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i]
+		known := false
+		for _, a := range allowed {
+			if key.Value == a {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return errorAt(key, "unknown key %q", key.Value)
+		}
+	}
+	return node.Decode(out)
+}
+
+func compilables(steps []stepSpec) []core.Compilable {
+	out := make([]core.Compilable, 0, len(steps))
+	for _, s := range steps {
+		out = append(out, s.def)
+	}
+	return out
+}